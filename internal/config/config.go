@@ -0,0 +1,113 @@
+// Package config loads Berth's user configuration file.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Config is the user configuration read from config.json in the Berth
+// configuration directory. Fields missing from the file keep their defaults.
+type Config struct {
+	Alerts  Alerts  `json:"alerts"`
+	Logs    Logs    `json:"logs"`
+	Compose Compose `json:"compose"`
+}
+
+// Alerts configures when a container is flagged in the containers table.
+// A zero threshold disables that check.
+type Alerts struct {
+	// CPUPercent flags a container whose CPU stays at or above this value
+	// for CPUSeconds.
+	CPUPercent float64 `json:"cpuPercent"`
+	CPUSeconds int     `json:"cpuSeconds"`
+	// MemoryPercent flags a container using at least this share of its
+	// memory limit. Containers without a limit are never flagged.
+	MemoryPercent float64 `json:"memoryPercent"`
+	// RestartCount flags a container that has restarted at least this often
+	// in a row, counted from when Berth started watching it. The flag clears
+	// once a check finds no new restart.
+	RestartCount int `json:"restartCount"`
+	// Unhealthy flags containers whose health check reports unhealthy.
+	Unhealthy bool `json:"unhealthy"`
+	// Hook is run with sh -c when a container starts alerting. The container
+	// and alert are passed in BERTH_CONTAINER_ID, BERTH_CONTAINER_NAME and
+	// BERTH_ALERT.
+	Hook string `json:"hook,omitempty"`
+}
+
+// Logs configures the logs view.
+type Logs struct {
+	// MaxLines is how many lines the logs view keeps; older lines are
+	// dropped as new ones arrive.
+	MaxLines int `json:"maxLines"`
+}
+
+// Compose configures compose project discovery and the compose tool.
+type Compose struct {
+	// Tool is the compose command, e.g. "docker compose", "docker-compose",
+	// "podman compose" or "podman-compose". Empty picks the first installed,
+	// preferring those of the detected engine.
+	Tool string `json:"tool,omitempty"`
+	// ProjectDirs are directories searched for compose files, in addition
+	// to the current directory and the projects of running containers.
+	ProjectDirs []string `json:"projectDirs,omitempty"`
+}
+
+// DefaultLogMaxLines is the logs view buffer size used when MaxLines is not
+// set or not positive.
+const DefaultLogMaxLines = 10000
+
+// Default returns the configuration used when no file exists.
+func Default() Config {
+	return Config{
+		Alerts: Alerts{
+			CPUPercent:    90,
+			CPUSeconds:    30,
+			MemoryPercent: 90,
+			RestartCount:  3,
+			Unhealthy:     true,
+		},
+		Logs: Logs{MaxLines: DefaultLogMaxLines},
+	}
+}
+
+// Path returns the location of the configuration file.
+func Path() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "berth", "config.json"), nil
+}
+
+// Load reads the configuration file, falling back to the defaults when it
+// does not exist. A file that cannot be parsed returns the defaults and an
+// error so the caller can report it.
+func Load() (Config, error) {
+	path, err := Path()
+	if err != nil {
+		return Default(), err
+	}
+	return LoadFile(path)
+}
+
+// LoadFile reads the configuration from path; see Load.
+func LoadFile(path string) (Config, error) {
+	cfg := Default()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Default(), fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	return cfg, nil
+}
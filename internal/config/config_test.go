@@ -0,0 +1,48 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	t.Run("missing file returns defaults", func(t *testing.T) {
+		cfg, err := LoadFile(filepath.Join(dir, "missing.json"))
+		require.NoError(t, err)
+		assert.Equal(t, Default(), cfg)
+	})
+
+	t.Run("partial file keeps other defaults", func(t *testing.T) {
+		cfg, err := LoadFile(write("partial.json", `{"alerts":{"cpuPercent":50,"restartCount":0,"hook":"notify-send berth"}}`))
+		require.NoError(t, err)
+		want := Default()
+		want.Alerts.CPUPercent = 50
+		want.Alerts.RestartCount = 0
+		want.Alerts.Hook = "notify-send berth"
+		assert.Equal(t, want, cfg)
+	})
+
+	t.Run("logs section", func(t *testing.T) {
+		cfg, err := LoadFile(write("logs.json", `{"logs":{"maxLines":50000}}`))
+		require.NoError(t, err)
+		assert.Equal(t, 50000, cfg.Logs.MaxLines)
+		assert.Equal(t, Default().Alerts, cfg.Alerts)
+	})
+
+	t.Run("invalid file returns defaults and an error", func(t *testing.T) {
+		cfg, err := LoadFile(write("bad.json", `{"alerts":`))
+		assert.Error(t, err)
+		assert.Equal(t, Default(), cfg)
+	})
+}
@@ -3,17 +3,156 @@ package controller
 import (
 	"bufio"
 	"context"
+	"fmt"
 	"io"
 	"os/exec"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/rluders/berth/internal/engine"
+)
+
+// Labels docker compose sets on the containers it creates.
+const (
+	composeProjectLabel     = "com.docker.compose.project"
+	composeServiceLabel     = "com.docker.compose.service"
+	composeNumberLabel      = "com.docker.compose.container-number"
+	composeWorkingDirLabel  = "com.docker.compose.project.working_dir"
+	composeConfigFilesLabel = "com.docker.compose.project.config_files"
+	composeEnvFileLabel     = "com.docker.compose.project.environment_file"
 )
 
+// ComposeTarget identifies the compose project a command runs against.
+type ComposeTarget struct {
+	Project string   `json:"project"`
+	WorkDir string   `json:"workDir,omitempty"` // directory compose runs in; empty for the current one
+	Files   []string `json:"files,omitempty"`   // compose files; empty lets compose find them in WorkDir
+	// EnvFiles are the env files compose reads, later ones overriding
+	// earlier ones; empty lets compose read .env in WorkDir.
+	EnvFiles []string `json:"envFiles,omitempty"`
+	Profiles []string `json:"profiles,omitempty"` // profiles enabled
+}
+
+// args returns the global compose flags selecting the project, its files,
+// env files and profiles.
+func (t ComposeTarget) args() []string {
+	args := []string{"-p", t.Project}
+	for _, f := range t.Files {
+		args = append(args, "-f", f)
+	}
+	for _, f := range t.EnvFiles {
+		args = append(args, "--env-file", f)
+	}
+	for _, p := range t.Profiles {
+		args = append(args, "--profile", p)
+	}
+	return args
+}
+
+// CommandLine renders the compose command that running args against t
+// executes, for display.
+func (t ComposeTarget) CommandLine(args ...string) string {
+	return strings.Join(append(append([]string{ComposeToolName()}, t.args()...), args...), " ")
+}
+
+// ComposeLine is one line of compose output. The last line sent for a
+// command that failed carries only its exit error.
+type ComposeLine struct {
+	Line string
+	Err  error
+}
+
+// composeTool holds the configured compose tool and the one resolved from
+// it. generation counts SetComposeTool calls, so a probe started before one
+// does not store its stale result. The mutex is never held while probing.
+var composeTool struct {
+	sync.Mutex
+	override   string
+	resolved   engine.ComposeTool
+	generation int
+}
+
+// composeToolName caches the display name read by ComposeToolName, which
+// runs on every render and must not wait for a probe.
+var composeToolName atomic.Value // string
+
+// SetComposeTool sets the compose tool commands run with, e.g.
+// "podman-compose". Empty detects it from the installed tools. The tool is
+// checked when the first compose command runs.
+func SetComposeTool(tool string) {
+	composeTool.Lock()
+	defer composeTool.Unlock()
+	composeTool.override = tool
+	composeTool.resolved = ""
+	composeTool.generation++
+	composeToolName.Store(tool)
+}
+
+// ResolveComposeTool returns the compose tool commands run with: the
+// configured one, or the first found for the detected engine. A tool found
+// is kept; a failure is checked again on the next call.
+func ResolveComposeTool() (engine.ComposeTool, error) {
+	composeTool.Lock()
+	resolved, override, generation := composeTool.resolved, composeTool.override, composeTool.generation
+	composeTool.Unlock()
+	if resolved != "" {
+		return resolved, nil
+	}
+
+	var tool engine.ComposeTool
+	if override != "" {
+		tool = engine.ComposeTool(override)
+		if err := engine.CheckComposeTool(tool); err != nil {
+			return "", fmt.Errorf("compose tool %q from the configuration: %w", override, err)
+		}
+	} else {
+		var err error
+		if tool, err = engine.DetectComposeTool(engine.DetectEngine()); err != nil {
+			return "", err
+		}
+	}
+
+	composeTool.Lock()
+	defer composeTool.Unlock()
+	if composeTool.generation == generation {
+		composeTool.resolved = tool
+		composeToolName.Store(string(tool))
+	}
+	return tool, nil
+}
+
+// ComposeToolName names the compose tool for display, falling back to
+// "docker compose" when none is set or found yet. It never blocks.
+func ComposeToolName() string {
+	if name, _ := composeToolName.Load().(string); name != "" {
+		return name
+	}
+	return string(engine.DockerCompose)
+}
+
+// composeCommand prepares a compose command against t with the resolved tool.
+func composeCommand(ctx context.Context, t ComposeTarget, args ...string) (*exec.Cmd, engine.ComposeTool, error) {
+	tool, err := ResolveComposeTool()
+	if err != nil {
+		return nil, "", err
+	}
+	name, toolArgs := tool.Command()
+	baseArgs := append(toolArgs, t.args()...)
+	cmd := exec.CommandContext(ctx, name, append(baseArgs, args...)...)
+	if t.WorkDir != "" {
+		cmd.Dir = t.WorkDir
+	}
+	return cmd, tool, nil
+}
+
 // StreamCompose runs a compose command and fans stdout+stderr line-by-line into ch.
 // ch is closed when the process exits or ctx is cancelled.
-func StreamCompose(ctx context.Context, project, workDir string, ch chan<- string, args ...string) error {
-	baseArgs := []string{"compose", "-p", project}
-	cmd := exec.CommandContext(ctx, "docker", append(baseArgs, args...)...)
-	if workDir != "" {
-		cmd.Dir = workDir
+func StreamCompose(ctx context.Context, t ComposeTarget, ch chan<- ComposeLine, args ...string) error {
+	cmd, tool, err := composeCommand(ctx, t, args...)
+	if err != nil {
+		close(ch)
+		return err
 	}
 
 	pr, pw := io.Pipe()
@@ -22,7 +161,7 @@ func StreamCompose(ctx context.Context, project, workDir string, ch chan<- strin
 
 	if err := cmd.Start(); err != nil {
 		close(ch)
-		return err
+		return fmt.Errorf("failed to run %s: %w", tool, err)
 	}
 
 	go func() {
@@ -38,7 +177,14 @@ func StreamCompose(ctx context.Context, project, workDir string, ch chan<- strin
 			select {
 			case <-ctx.Done():
 				return
-			case ch <- scanner.Text():
+			case ch <- ComposeLine{Line: scanner.Text()}:
+			}
+		}
+		// The pipe is closed with the command's exit error.
+		if err := scanner.Err(); err != nil && ctx.Err() == nil {
+			select {
+			case <-ctx.Done():
+			case ch <- ComposeLine{Err: err}:
 			}
 		}
 	}()
@@ -46,26 +192,53 @@ func StreamCompose(ctx context.Context, project, workDir string, ch chan<- strin
 	return nil
 }
 
-func ComposeUp(ctx context.Context, project, workDir string, ch chan<- string) error {
-	return StreamCompose(ctx, project, workDir, ch, "up", "-d")
+// ComposeUp starts the project, or only the given services.
+func ComposeUp(ctx context.Context, t ComposeTarget, ch chan<- ComposeLine, services ...string) error {
+	return StreamCompose(ctx, t, ch, append([]string{"up", "-d"}, services...)...)
+}
+
+func ComposeUpBuild(ctx context.Context, t ComposeTarget, ch chan<- ComposeLine) error {
+	return StreamCompose(ctx, t, ch, "up", "-d", "--build")
 }
 
-func ComposeUpBuild(ctx context.Context, project, workDir string, ch chan<- string) error {
-	return StreamCompose(ctx, project, workDir, ch, "up", "-d", "--build")
+func ComposeRecreate(ctx context.Context, t ComposeTarget, ch chan<- ComposeLine) error {
+	return StreamCompose(ctx, t, ch, "up", "-d", "--force-recreate")
 }
 
-func ComposeRecreate(ctx context.Context, project, workDir string, ch chan<- string) error {
-	return StreamCompose(ctx, project, workDir, ch, "up", "-d", "--force-recreate")
+func ComposeDown(ctx context.Context, t ComposeTarget, ch chan<- ComposeLine) error {
+	return StreamCompose(ctx, t, ch, "down")
 }
 
-func ComposeDown(ctx context.Context, project, workDir string, ch chan<- string) error {
-	return StreamCompose(ctx, project, workDir, ch, "down")
+// ComposePull pulls the images of the project, or only of the given services.
+func ComposePull(ctx context.Context, t ComposeTarget, ch chan<- ComposeLine, services ...string) error {
+	return StreamCompose(ctx, t, ch, append([]string{"pull"}, services...)...)
 }
 
-func ComposePull(ctx context.Context, project, workDir string, ch chan<- string) error {
-	return StreamCompose(ctx, project, workDir, ch, "pull")
+// ComposeBuild builds the project, or only the given services.
+func ComposeBuild(ctx context.Context, t ComposeTarget, ch chan<- ComposeLine, services ...string) error {
+	return StreamCompose(ctx, t, ch, append([]string{"build"}, services...)...)
+}
+
+// ComposeRestart restarts the containers of the project, or only of the given
+// services.
+func ComposeRestart(ctx context.Context, t ComposeTarget, ch chan<- ComposeLine, services ...string) error {
+	return StreamCompose(ctx, t, ch, append([]string{"restart"}, services...)...)
+}
+
+// ComposeScale runs replicas containers of service. It goes through
+// `up --scale` rather than `scale`, which podman-compose and older Compose
+// v2 releases do not have.
+func ComposeScale(ctx context.Context, t ComposeTarget, ch chan<- ComposeLine, service string, replicas int) error {
+	if replicas < 0 {
+		close(ch)
+		return fmt.Errorf("invalid replica count %d for %s", replicas, service)
+	}
+	return StreamCompose(ctx, t, ch, "up", "-d", "--no-recreate", "--scale", fmt.Sprintf("%s=%d", service, replicas), service)
 }
 
-func ComposeBuild(ctx context.Context, project, workDir string, ch chan<- string) error {
-	return StreamCompose(ctx, project, workDir, ch, "build")
+// ComposeServiceOf returns the compose project and service of a container
+// from its labels; ok is false for containers outside compose.
+func ComposeServiceOf(c Container) (project, service string, ok bool) {
+	project, service = c.Labels[composeProjectLabel], c.Labels[composeServiceLabel]
+	return project, service, project != "" && service != ""
 }
@@ -0,0 +1,399 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types/filters"
+	dockerImageTypes "github.com/docker/docker/api/types/image"
+)
+
+// ErrNoJSONConfig is returned by ComposeDrift when the compose tool cannot
+// render the project's configuration as JSON, as podman-compose cannot.
+var ErrNoJSONConfig = errors.New("compose tool cannot render its configuration as JSON")
+
+// Drift states of a compose service, from what `up` would do to it.
+const (
+	DriftUpToDate   = "up to date"
+	DriftOutdated   = "outdated"    // up recreates the container
+	DriftStopped    = "stopped"     // up starts the container
+	DriftNotCreated = "not created" // up creates the container
+	DriftOrphan     = "orphan"      // the service is no longer in the compose files
+)
+
+// DriftChange is a setting whose container value differs from the compose
+// files. Want or Got is empty when the setting is missing on that side.
+type DriftChange struct {
+	Field string // e.g. "image", "env DEBUG", "port", "mount /data", "label tier"
+	Want  string // from the compose files
+	Got   string // from the container
+}
+
+// ServiceDrift compares one container of a compose service, or a service
+// without containers, with the compose files.
+type ServiceDrift struct {
+	Service   string
+	Container string // empty when the service has no container
+	Status    string
+	Changes   []DriftChange
+}
+
+// composeConfig is the part of `compose config --format json` Berth compares.
+type composeConfig struct {
+	Services map[string]composeServiceConfig `json:"services"`
+	Volumes  map[string]struct {
+		Name string `json:"name"`
+	} `json:"volumes"`
+}
+
+type composeServiceConfig struct {
+	Image       string             `json:"image"`
+	Environment map[string]*string `json:"environment"` // nil values are unset
+	Labels      map[string]string  `json:"labels"`
+	Ports       []struct {
+		Target    uint32     `json:"target"`
+		Published portNumber `json:"published"`
+		Protocol  string     `json:"protocol"`
+		HostIP    string     `json:"host_ip"`
+	} `json:"ports"`
+	Volumes []struct {
+		Type   string `json:"type"`
+		Source string `json:"source"`
+		Target string `json:"target"`
+	} `json:"volumes"`
+}
+
+// portNumber accepts a published port rendered as a string or a number,
+// depending on the compose version.
+type portNumber string
+
+func (p *portNumber) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*p = portNumber(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	*p = portNumber(n.String())
+	return nil
+}
+
+// parseComposeConfig decodes the output of `compose config --format json`.
+func parseComposeConfig(data []byte) (composeConfig, error) {
+	var cfg composeConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return composeConfig{}, fmt.Errorf("failed to parse compose config: %w", err)
+	}
+	return cfg, nil
+}
+
+// renderComposeConfig runs `compose config --format json` against t. It
+// returns ErrNoJSONConfig when the tool has no JSON output, either known
+// up front or found from a rejected flag or output that is not JSON.
+func renderComposeConfig(ctx context.Context, t ComposeTarget) (composeConfig, error) {
+	cmd, tool, err := composeCommand(ctx, t, "config", "--format", "json")
+	if err != nil {
+		return composeConfig{}, err
+	}
+	if !tool.HasJSONConfig() {
+		return composeConfig{}, fmt.Errorf("%w: %s", ErrNoJSONConfig, tool)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err == nil && !bytes.HasPrefix(bytes.TrimSpace(out), []byte("{")) {
+		return composeConfig{}, fmt.Errorf("%w: %s", ErrNoJSONConfig, tool)
+	}
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if strings.Contains(msg, "--format") {
+			return composeConfig{}, fmt.Errorf("%w: %s", ErrNoJSONConfig, tool)
+		}
+		if msg != "" {
+			return composeConfig{}, fmt.Errorf("failed to run %s config: %s", tool, msg)
+		}
+		return composeConfig{}, fmt.Errorf("failed to run %s config: %w", tool, err)
+	}
+	return parseComposeConfig(out)
+}
+
+// imageIDByRef returns the ID of the local image tagged ref, or empty when
+// there is none.
+func imageIDByRef(ctx context.Context, ref string) (string, error) {
+	images, err := imageService.ImageList(ctx, dockerImageTypes.ListOptions{
+		Filters: filters.NewArgs(filters.Arg("reference", normalizeImageRef(ref))),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to list images for %s: %w", ref, err)
+	}
+	if len(images) == 0 {
+		return "", nil
+	}
+	return images[0].ID, nil
+}
+
+// normalizeImageRef adds the latest tag to a reference without tag or digest.
+func normalizeImageRef(ref string) string {
+	if strings.Contains(ref, "@") {
+		return ref
+	}
+	if i := strings.LastIndex(ref, "/"); strings.Contains(ref[i+1:], ":") {
+		return ref
+	}
+	return ref + ":latest"
+}
+
+// ComposeDrift compares the containers of t's project with its compose files
+// as rendered by `compose config`. Services come in name order, each
+// container of a service on its own.
+func ComposeDrift(ctx context.Context, t ComposeTarget) ([]ServiceDrift, error) {
+	cfg, err := renderComposeConfig(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+	containers, err := ListContainers()
+	if err != nil {
+		return nil, err
+	}
+
+	byService := map[string][]ContainerDetails{}
+	for _, c := range containers {
+		project, service, ok := ComposeServiceOf(c)
+		if !ok || project != t.Project {
+			continue
+		}
+		d, err := GetContainerDetails(c.ID)
+		if err != nil {
+			return nil, err
+		}
+		byService[service] = append(byService[service], d)
+	}
+
+	imageIDs := map[string]string{}
+	for name, svc := range cfg.Services {
+		ref := serviceImage(t.Project, name, svc)
+		if _, ok := imageIDs[ref]; ok || len(byService[name]) == 0 {
+			continue
+		}
+		if imageIDs[ref], err = imageIDByRef(ctx, ref); err != nil {
+			return nil, err
+		}
+	}
+
+	return diffComposeProject(t.Project, cfg, byService, imageIDs), nil
+}
+
+// serviceImage returns the image a service runs: the one it names, or the
+// one compose builds for it.
+func serviceImage(project, name string, svc composeServiceConfig) string {
+	if svc.Image != "" {
+		return svc.Image
+	}
+	return project + "-" + name
+}
+
+// diffComposeProject compares the containers of each service with its
+// configuration. imageIDs maps image references to local image IDs.
+func diffComposeProject(project string, cfg composeConfig, byService map[string][]ContainerDetails, imageIDs map[string]string) []ServiceDrift {
+	names := make([]string, 0, len(cfg.Services)+len(byService))
+	for name := range cfg.Services {
+		names = append(names, name)
+	}
+	for name := range byService {
+		if _, ok := cfg.Services[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var result []ServiceDrift
+	for _, name := range names {
+		svc, defined := cfg.Services[name]
+		containers := byService[name]
+		sort.Slice(containers, func(i, j int) bool { return containers[i].Name < containers[j].Name })
+		switch {
+		case !defined:
+			for _, d := range containers {
+				result = append(result, ServiceDrift{Service: name, Container: d.Name, Status: DriftOrphan})
+			}
+		case len(containers) == 0:
+			result = append(result, ServiceDrift{Service: name, Status: DriftNotCreated})
+		default:
+			image := serviceImage(project, name, svc)
+			for _, d := range containers {
+				result = append(result, diffService(project, name, svc, cfg, d, imageIDs[image]))
+			}
+		}
+	}
+	return result
+}
+
+// diffService compares one container with its service configuration.
+// imageID is the local image the service's image reference points to.
+func diffService(project, name string, svc composeServiceConfig, cfg composeConfig, d ContainerDetails, imageID string) ServiceDrift {
+	var changes []DriftChange
+	add := func(field, want, got string) {
+		changes = append(changes, DriftChange{Field: field, Want: want, Got: got})
+	}
+
+	switch {
+	case svc.Image != "" && svc.Image != d.Image:
+		add("image", svc.Image, d.Image)
+	case imageID != "" && d.ImageID != "" && imageID != d.ImageID:
+		add("image digest", shortImageID(imageID), shortImageID(d.ImageID))
+	}
+
+	env := map[string]string{}
+	for _, e := range d.Env {
+		k, v, _ := strings.Cut(e, "=")
+		env[k] = v
+	}
+	for _, k := range sortedKeys(svc.Environment) {
+		want := svc.Environment[k]
+		if want == nil {
+			continue
+		}
+		if got, ok := env[k]; !ok || got != *want {
+			add("env "+k, *want, got)
+		}
+	}
+
+	for _, k := range sortedKeys(svc.Labels) {
+		if got := d.Labels[k]; got != svc.Labels[k] {
+			add("label "+k, svc.Labels[k], got)
+		}
+	}
+
+	wantPorts := map[string]bool{}
+	for _, p := range svc.Ports {
+		wantPorts[formatPort(p.HostIP, string(p.Published), fmt.Sprint(p.Target), p.Protocol)] = true
+	}
+	gotPorts := map[string]bool{}
+	for _, p := range d.Ports {
+		gotPorts[formatPort(p.HostIP, p.HostPort, p.ContainerPort, p.Protocol)] = true
+	}
+	for _, p := range sortedKeys(wantPorts) {
+		if !gotPorts[p] {
+			add("port", p, "")
+		}
+	}
+	for _, p := range sortedKeys(gotPorts) {
+		if !wantPorts[p] {
+			add("port", "", p)
+		}
+	}
+
+	mounts := map[string]Mount{}
+	for _, m := range d.Mounts {
+		mounts[m.Destination] = m
+	}
+	for _, v := range svc.Volumes {
+		var want string
+		switch v.Type {
+		case "bind":
+			want = v.Source
+		case "volume":
+			want = volumeName(project, cfg, v.Source)
+		default:
+			continue
+		}
+		m, ok := mounts[v.Target]
+		got := m.Source
+		if m.Type == "volume" {
+			got = m.Name
+		}
+		same := got == want
+		if v.Type == "bind" {
+			same = hostPath(got) == hostPath(want)
+		}
+		if !ok || m.Type != v.Type || (want != "" && !same) {
+			add("mount "+v.Target, strings.TrimSpace(v.Type+" "+want), strings.TrimSpace(m.Type+" "+got))
+		}
+	}
+
+	status := DriftUpToDate
+	switch {
+	case len(changes) > 0:
+		status = DriftOutdated
+	case d.State != "running":
+		status = DriftStopped
+		add("state", "running", d.State)
+	}
+	return ServiceDrift{Service: name, Container: d.Name, Status: status, Changes: changes}
+}
+
+// hostPath maps a bind mount source to a form comparable across the engine
+// and compose. Docker Desktop reports sources below the VM's view of the
+// host, /host_mnt/Users/me on macOS and /run/desktop/mnt/host/c/Users/me on
+// Windows, while compose gives /Users/me and C:\Users\me.
+func hostPath(p string) string {
+	p = strings.ReplaceAll(p, `\`, "/")
+	for _, prefix := range []string{"/host_mnt/", "/run/desktop/mnt/host/"} {
+		if rest, ok := strings.CutPrefix(p, prefix); ok {
+			p = "/" + rest
+			break
+		}
+	}
+	if len(p) >= 2 && p[1] == ':' {
+		p = "/" + strings.ToLower(p[:1]) + p[2:]
+	}
+	return path.Clean(p)
+}
+
+// volumeName resolves a service volume source to the engine volume name:
+// empty for an anonymous volume, the declared name when the top-level
+// volume has one, or else the name prefixed with the project.
+func volumeName(project string, cfg composeConfig, source string) string {
+	if source == "" {
+		return ""
+	}
+	if v, ok := cfg.Volumes[source]; ok && v.Name != "" {
+		return v.Name
+	}
+	return project + "_" + source
+}
+
+// formatPort renders a port mapping as [host_ip:]published:target/protocol,
+// or target/protocol when no host port was asked for.
+func formatPort(hostIP, published, target, protocol string) string {
+	if protocol == "" {
+		protocol = "tcp"
+	}
+	port := target + "/" + protocol
+	if published == "" {
+		return port
+	}
+	port = published + ":" + port
+	if hostIP != "" {
+		port = hostIP + ":" + port
+	}
+	return port
+}
+
+// shortImageID trims an image ID to the 12 characters docker displays.
+func shortImageID(id string) string {
+	id = strings.TrimPrefix(id, "sha256:")
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
+
+// sortedKeys returns the keys of m in order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
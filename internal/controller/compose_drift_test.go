@@ -0,0 +1,173 @@
+package controller
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const shopConfigJSON = `{
+  "name": "shop",
+  "services": {
+    "web": {
+      "image": "nginx:1.27",
+      "environment": {"MODE": "prod", "FROM_HOST": null},
+      "labels": {"tier": "front"},
+      "ports": [{"target": 80, "published": "8080", "protocol": "tcp"}],
+      "volumes": [
+        {"type": "bind", "source": "/src/shop/site", "target": "/usr/share/nginx/html"},
+        {"type": "volume", "source": "cache", "target": "/cache"}
+      ]
+    },
+    "api": {"image": "api:1"},
+    "db": {"image": "postgres:16", "ports": [{"target": 5432, "published": 5432}]},
+    "worker": {}
+  },
+  "volumes": {"cache": {"name": "shop_cache"}}
+}`
+
+func TestParseComposeConfig(t *testing.T) {
+	cfg, err := parseComposeConfig([]byte(shopConfigJSON))
+	require.NoError(t, err)
+
+	require.Len(t, cfg.Services, 4)
+	assert.Equal(t, portNumber("5432"), cfg.Services["db"].Ports[0].Published, "numeric ports are read too")
+	assert.Nil(t, cfg.Services["web"].Environment["FROM_HOST"])
+	assert.Equal(t, "shop_cache", cfg.Volumes["cache"].Name)
+
+	_, err = parseComposeConfig([]byte("not json"))
+	assert.Error(t, err)
+}
+
+func TestDiffComposeProject(t *testing.T) {
+	cfg, err := parseComposeConfig([]byte(shopConfigJSON))
+	require.NoError(t, err)
+
+	web := ContainerDetails{
+		Name:    "shop-web-1",
+		Image:   "nginx:1.27",
+		ImageID: "sha256:aaaaaaaaaaaa1111",
+		State:   "running",
+		Env:     []string{"MODE=dev", "PATH=/usr/bin"},
+		Labels:  map[string]string{"tier": "front", "com.docker.compose.project": "shop"},
+		Ports:   []PortBinding{{ContainerPort: "80", Protocol: "tcp", HostPort: "8081"}},
+		Mounts: []Mount{
+			{Type: "bind", Source: "/src/shop/site", Destination: "/usr/share/nginx/html"},
+			{Type: "volume", Name: "shop_cache", Source: "/var/lib/docker/volumes/shop_cache/_data", Destination: "/cache"},
+		},
+	}
+	api := ContainerDetails{Name: "shop-api-1", Image: "api:1", ImageID: "sha256:bbbbbbbbbbbb2222", State: "exited"}
+	db := ContainerDetails{
+		Name:    "shop-db-1",
+		Image:   "postgres:16",
+		ImageID: "sha256:cccccccccccc3333",
+		State:   "running",
+		Ports:   []PortBinding{{ContainerPort: "5432", Protocol: "tcp", HostPort: "5432"}},
+	}
+	old := ContainerDetails{Name: "shop-cron-1", Image: "cron", State: "running"}
+
+	drift := diffComposeProject("shop", cfg,
+		map[string][]ContainerDetails{"web": {web}, "api": {api}, "db": {db}, "cron": {old}},
+		map[string]string{
+			"nginx:1.27":  "sha256:aaaaaaaaaaaa1111",
+			"api:1":       "sha256:bbbbbbbbbbbb2222",
+			"postgres:16": "sha256:dddddddddddd4444",
+		},
+	)
+
+	assert.Equal(t, []ServiceDrift{
+		{Service: "api", Container: "shop-api-1", Status: DriftStopped, Changes: []DriftChange{{Field: "state", Want: "running", Got: "exited"}}},
+		{Service: "cron", Container: "shop-cron-1", Status: DriftOrphan},
+		{Service: "db", Container: "shop-db-1", Status: DriftOutdated, Changes: []DriftChange{
+			{Field: "image digest", Want: "dddddddddddd", Got: "cccccccccccc"},
+		}},
+		{Service: "web", Container: "shop-web-1", Status: DriftOutdated, Changes: []DriftChange{
+			{Field: "env MODE", Want: "prod", Got: "dev"},
+			{Field: "port", Want: "8080:80/tcp"},
+			{Field: "port", Got: "8081:80/tcp"},
+		}},
+		{Service: "worker", Status: DriftNotCreated},
+	}, drift)
+}
+
+func TestDiffService_imageAndMounts(t *testing.T) {
+	cfg, err := parseComposeConfig([]byte(shopConfigJSON))
+	require.NoError(t, err)
+	d := ContainerDetails{
+		Name:   "shop-web-1",
+		Image:  "nginx:1.25",
+		State:  "running",
+		Env:    []string{"MODE=prod"},
+		Labels: map[string]string{"tier": "back"},
+		Ports:  []PortBinding{{ContainerPort: "80", Protocol: "tcp", HostPort: "8080"}},
+		Mounts: []Mount{{Type: "volume", Name: "other", Destination: "/cache"}},
+	}
+
+	drift := diffService("shop", "web", cfg.Services["web"], cfg, d, "")
+
+	assert.Equal(t, DriftOutdated, drift.Status)
+	assert.Equal(t, []DriftChange{
+		{Field: "image", Want: "nginx:1.27", Got: "nginx:1.25"},
+		{Field: "label tier", Want: "front", Got: "back"},
+		{Field: "mount /usr/share/nginx/html", Want: "bind /src/shop/site"},
+		{Field: "mount /cache", Want: "volume shop_cache", Got: "volume other"},
+	}, drift.Changes)
+}
+
+func TestDiffService_bindSourceThroughDockerDesktop(t *testing.T) {
+	cfg, err := parseComposeConfig([]byte(shopConfigJSON))
+	require.NoError(t, err)
+	d := ContainerDetails{
+		Name:   "shop-web-1",
+		Image:  "nginx:1.27",
+		State:  "running",
+		Labels: map[string]string{"tier": "front"},
+		Env:    []string{"MODE=prod"},
+		Ports:  []PortBinding{{ContainerPort: "80", Protocol: "tcp", HostPort: "8080"}},
+		Mounts: []Mount{
+			{Type: "bind", Source: "/host_mnt/src/shop/site", Destination: "/usr/share/nginx/html"},
+			{Type: "volume", Name: "shop_cache", Destination: "/cache"},
+		},
+	}
+
+	drift := diffService("shop", "web", cfg.Services["web"], cfg, d, "")
+
+	assert.Equal(t, DriftUpToDate, drift.Status)
+	assert.Empty(t, drift.Changes)
+}
+
+func TestHostPath(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"/src/shop/site", "/src/shop/site"},
+		{"/host_mnt/Users/me/shop", "/Users/me/shop"},
+		{"/run/desktop/mnt/host/c/Users/me/shop", "/c/Users/me/shop"},
+		{`C:\Users\me\shop`, "/c/Users/me/shop"},
+		{"/src/shop/site/", "/src/shop/site"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, hostPath(tt.in), tt.in)
+	}
+}
+
+func TestRenderComposeConfig_noJSONOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fake-compose")
+	script := "#!/bin/sh\nprintf 'services:\\n  web:\\n    image: nginx\\n'\n"
+	require.NoError(t, os.WriteFile(path, []byte(script), 0o755))
+	SetComposeTool(path)
+	t.Cleanup(func() { SetComposeTool("") })
+
+	_, err := renderComposeConfig(context.Background(), ComposeTarget{Project: "shop"})
+
+	assert.ErrorIs(t, err, ErrNoJSONConfig)
+}
+
+func TestNormalizeImageRef(t *testing.T) {
+	assert.Equal(t, "nginx:latest", normalizeImageRef("nginx"))
+	assert.Equal(t, "nginx:1.27", normalizeImageRef("nginx:1.27"))
+	assert.Equal(t, "localhost:5000/app:latest", normalizeImageRef("localhost:5000/app"))
+	assert.Equal(t, "nginx@sha256:abc", normalizeImageRef("nginx@sha256:abc"))
+}
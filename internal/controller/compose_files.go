@@ -0,0 +1,271 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Compose file names in the order docker compose looks for them, and the
+// override file it merges on top of each.
+var (
+	composeFileNames = []string{"compose.yaml", "compose.yml", "docker-compose.yaml", "docker-compose.yml"}
+	composeOverrides = map[string]string{
+		"compose.yaml":        "compose.override.yaml",
+		"compose.yml":         "compose.override.yml",
+		"docker-compose.yaml": "docker-compose.override.yaml",
+		"docker-compose.yml":  "docker-compose.override.yml",
+	}
+)
+
+// ComposeService is a service defined in a compose file.
+type ComposeService struct {
+	Name     string
+	Image    string   // empty for services built from source
+	Profiles []string // the service only starts when one of them is enabled
+}
+
+// ComposeProject is a compose project read from its files on disk.
+type ComposeProject struct {
+	ComposeTarget
+	Services []ComposeService // sorted by name
+}
+
+// composeCache holds the projects read by DiscoverComposeProjects, by target,
+// so that files are parsed again only when one of them changes.
+var composeCache = struct {
+	sync.Mutex
+	entries map[string]composeCacheEntry
+}{entries: map[string]composeCacheEntry{}}
+
+type composeCacheEntry struct {
+	files   []string
+	mtimes  []time.Time
+	project ComposeProject
+}
+
+// composeFile is the part of a compose file Berth reads.
+type composeFile struct {
+	Name     string `yaml:"name"`
+	Services map[string]struct {
+		Image    string   `yaml:"image"`
+		Profiles []string `yaml:"profiles"`
+	} `yaml:"services"`
+}
+
+// FindComposeFiles returns the compose file docker compose would pick in dir,
+// followed by its override file when there is one; nil when there is none.
+func FindComposeFiles(dir string) []string {
+	for _, name := range composeFileNames {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		files := []string{path}
+		override := filepath.Join(dir, composeOverrides[name])
+		if _, err := os.Stat(override); err == nil {
+			files = append(files, override)
+		}
+		return files
+	}
+	return nil
+}
+
+// LoadComposeProject reads the services of t's files, later files overriding
+// earlier ones as in docker compose. Without files, the default files of
+// t.WorkDir are used. An empty t.Project is taken from the files' top-level
+// name, or else from the directory name.
+func LoadComposeProject(t ComposeTarget) (ComposeProject, error) {
+	if len(t.Files) == 0 {
+		t.Files = FindComposeFiles(t.WorkDir)
+		if len(t.Files) == 0 {
+			return ComposeProject{}, fmt.Errorf("no compose file in %s", t.WorkDir)
+		}
+	}
+	if t.WorkDir == "" {
+		t.WorkDir = filepath.Dir(t.Files[0])
+	}
+
+	services := map[string]ComposeService{}
+	name := ""
+	for _, path := range t.Files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return ComposeProject{}, fmt.Errorf("failed to read compose file %s: %w", path, err)
+		}
+		var f composeFile
+		if err := yaml.Unmarshal(data, &f); err != nil {
+			return ComposeProject{}, fmt.Errorf("failed to parse compose file %s: %w", path, err)
+		}
+		if f.Name != "" {
+			name = f.Name
+		}
+		for svcName, def := range f.Services {
+			svc := services[svcName]
+			svc.Name = svcName
+			if def.Image != "" {
+				svc.Image = def.Image
+			}
+			if def.Profiles != nil {
+				svc.Profiles = def.Profiles
+			}
+			services[svcName] = svc
+		}
+	}
+
+	if t.Project == "" {
+		t.Project = name
+	}
+	if t.Project == "" {
+		t.Project = composeProjectName(filepath.Base(t.WorkDir))
+	}
+	p := ComposeProject{ComposeTarget: t}
+	for _, svc := range services {
+		p.Services = append(p.Services, svc)
+	}
+	sort.Slice(p.Services, func(i, j int) bool { return p.Services[i].Name < p.Services[j].Name })
+	return p, nil
+}
+
+// composeProjectName normalises a directory name the way docker compose does
+// for its default project name: lower case, keeping letters, digits, dashes
+// and underscores.
+func composeProjectName(dir string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(dir) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' || r == '_' {
+			sb.WriteRune(r)
+		}
+	}
+	return strings.TrimLeft(sb.String(), "-_")
+}
+
+// ComposeTargetsFromLabels returns the compose projects of containers as
+// recorded in their labels, one per project in order of appearance.
+func ComposeTargetsFromLabels(containers []Container) []ComposeTarget {
+	var targets []ComposeTarget
+	seen := map[string]bool{}
+	for _, c := range containers {
+		project := c.Labels[composeProjectLabel]
+		if project == "" || seen[project] {
+			continue
+		}
+		seen[project] = true
+		t := ComposeTarget{Project: project, WorkDir: c.Labels[composeWorkingDirLabel]}
+		if files := c.Labels[composeConfigFilesLabel]; files != "" {
+			t.Files = strings.Split(files, ",")
+		}
+		if envFiles := c.Labels[composeEnvFileLabel]; envFiles != "" {
+			t.EnvFiles = strings.Split(envFiles, ",")
+		}
+		targets = append(targets, t)
+	}
+	return targets
+}
+
+// DiscoverComposeProjects loads the projects of targets, then those whose
+// files are found in dirs. A project found twice keeps its first definition.
+// Projects that cannot be read are skipped and reported in the joined error.
+func DiscoverComposeProjects(targets []ComposeTarget, dirs []string) ([]ComposeProject, error) {
+	for _, dir := range dirs {
+		if files := FindComposeFiles(dir); files != nil {
+			targets = append(targets, ComposeTarget{WorkDir: dir, Files: files})
+		}
+	}
+
+	var (
+		projects []ComposeProject
+		errs     []error
+	)
+	seen := map[string]bool{}
+	used := map[string]bool{}
+	defer pruneComposeCache(used)
+	for _, t := range targets {
+		if t.Project != "" && seen[t.Project] {
+			continue
+		}
+		used[composeCacheKey(t)] = true
+		p, err := loadCachedComposeProject(t)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if seen[p.Project] {
+			continue
+		}
+		seen[p.Project] = true
+		projects = append(projects, p)
+	}
+	return projects, errors.Join(errs...)
+}
+
+// Exists reports whether t's compose files are still on disk: all of
+// t.Files, or a compose file in t.WorkDir when it names none.
+func (t ComposeTarget) Exists() bool {
+	if len(t.Files) == 0 {
+		return FindComposeFiles(t.WorkDir) != nil
+	}
+	for _, f := range t.Files {
+		if _, err := os.Stat(f); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func composeCacheKey(t ComposeTarget) string {
+	return fmt.Sprintf("%#v", t)
+}
+
+// loadCachedComposeProject returns the project of t as LoadComposeProject
+// does, reading its files only when they changed since the last call.
+func loadCachedComposeProject(t ComposeTarget) (ComposeProject, error) {
+	files := t.Files
+	if len(files) == 0 {
+		files = FindComposeFiles(t.WorkDir)
+	}
+	mtimes := make([]time.Time, len(files))
+	for i, f := range files {
+		info, err := os.Stat(f)
+		if err != nil {
+			return LoadComposeProject(t)
+		}
+		mtimes[i] = info.ModTime()
+	}
+
+	key := composeCacheKey(t)
+	composeCache.Lock()
+	e, ok := composeCache.entries[key]
+	composeCache.Unlock()
+	if ok && slices.Equal(e.files, files) && slices.EqualFunc(e.mtimes, mtimes, time.Time.Equal) {
+		return e.project, nil
+	}
+
+	p, err := LoadComposeProject(t)
+	if err != nil {
+		return ComposeProject{}, err
+	}
+	composeCache.Lock()
+	composeCache.entries[key] = composeCacheEntry{files: files, mtimes: mtimes, project: p}
+	composeCache.Unlock()
+	return p, nil
+}
+
+// pruneComposeCache forgets the projects of targets not in used.
+func pruneComposeCache(used map[string]bool) {
+	composeCache.Lock()
+	defer composeCache.Unlock()
+	for key := range composeCache.entries {
+		if !used[key] {
+			delete(composeCache.entries, key)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package controller
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func TestFindComposeFiles(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, FindComposeFiles(dir))
+
+	writeFile(t, filepath.Join(dir, "docker-compose.yml"), "services: {}\n")
+	assert.Equal(t, []string{filepath.Join(dir, "docker-compose.yml")}, FindComposeFiles(dir))
+
+	writeFile(t, filepath.Join(dir, "compose.yaml"), "services: {}\n")
+	writeFile(t, filepath.Join(dir, "compose.override.yaml"), "services: {}\n")
+	assert.Equal(t, []string{
+		filepath.Join(dir, "compose.yaml"),
+		filepath.Join(dir, "compose.override.yaml"),
+	}, FindComposeFiles(dir), "compose.yaml wins and brings its override")
+}
+
+func TestLoadComposeProject_mergesOverride(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "My.App")
+	require.NoError(t, os.Mkdir(dir, 0o700))
+	writeFile(t, filepath.Join(dir, "compose.yaml"), `
+services:
+  web:
+    image: nginx:1.27
+  db:
+    image: postgres:16
+`)
+	writeFile(t, filepath.Join(dir, "compose.override.yaml"), `
+services:
+  web:
+    image: nginx:1.28
+  debug:
+    build: ./debug
+    profiles: [dev]
+`)
+
+	p, err := LoadComposeProject(ComposeTarget{WorkDir: dir})
+	require.NoError(t, err)
+
+	assert.Equal(t, "myapp", p.Project, "name derived from the directory")
+	assert.Equal(t, dir, p.WorkDir)
+	assert.Len(t, p.Files, 2)
+	assert.Equal(t, []ComposeService{
+		{Name: "db", Image: "postgres:16"},
+		{Name: "debug", Profiles: []string{"dev"}},
+		{Name: "web", Image: "nginx:1.28"},
+	}, p.Services)
+}
+
+func TestLoadComposeProject_name(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "stack.yml")
+	writeFile(t, path, "name: shop\nservices:\n  api:\n    image: api\n")
+
+	p, err := LoadComposeProject(ComposeTarget{Files: []string{path}})
+	require.NoError(t, err)
+	assert.Equal(t, "shop", p.Project, "top-level name")
+	assert.Equal(t, dir, p.WorkDir, "work dir defaults to the first file's directory")
+
+	p, err = LoadComposeProject(ComposeTarget{Project: "labelled", Files: []string{path}})
+	require.NoError(t, err)
+	assert.Equal(t, "labelled", p.Project, "a known project name is kept")
+}
+
+func TestLoadComposeProject_errors(t *testing.T) {
+	dir := t.TempDir()
+	_, err := LoadComposeProject(ComposeTarget{WorkDir: dir})
+	assert.Error(t, err, "no compose file")
+
+	path := filepath.Join(dir, "compose.yaml")
+	writeFile(t, path, "services: [\n")
+	_, err = LoadComposeProject(ComposeTarget{Files: []string{path}})
+	assert.ErrorContains(t, err, "failed to parse compose file")
+}
+
+func TestComposeTargetsFromLabels(t *testing.T) {
+	containers := []Container{
+		{Labels: map[string]string{
+			composeProjectLabel:     "shop",
+			composeWorkingDirLabel:  "/src/shop",
+			composeConfigFilesLabel: "/src/shop/compose.yaml,/src/shop/compose.prod.yaml",
+			composeEnvFileLabel:     "/src/shop/.env,/src/shop/.env.prod",
+		}},
+		{Labels: map[string]string{composeProjectLabel: "shop"}},
+		{Labels: map[string]string{}},
+	}
+
+	assert.Equal(t, []ComposeTarget{{
+		Project:  "shop",
+		WorkDir:  "/src/shop",
+		Files:    []string{"/src/shop/compose.yaml", "/src/shop/compose.prod.yaml"},
+		EnvFiles: []string{"/src/shop/.env", "/src/shop/.env.prod"},
+	}}, ComposeTargetsFromLabels(containers))
+}
+
+func TestDiscoverComposeProjects(t *testing.T) {
+	shop := t.TempDir()
+	writeFile(t, filepath.Join(shop, "compose.yaml"), "name: shop\nservices:\n  api:\n    image: api\n")
+	blog := t.TempDir()
+	writeFile(t, filepath.Join(blog, "compose.yml"), "name: blog\nservices:\n  ghost:\n    image: ghost\n")
+	empty := t.TempDir()
+
+	projects, err := DiscoverComposeProjects(
+		[]ComposeTarget{
+			{Project: "shop", WorkDir: shop},
+			{Project: "gone", WorkDir: filepath.Join(empty, "missing")},
+		},
+		[]string{shop, blog, empty},
+	)
+
+	assert.Error(t, err, "the missing project is reported")
+	require.Len(t, projects, 2, "shop is found twice but listed once")
+	assert.Equal(t, "shop", projects[0].Project)
+	assert.Equal(t, "blog", projects[1].Project)
+}
+
+func TestDiscoverComposeProjects_rereadsChangedFilesOnly(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "compose.yaml")
+	writeFile(t, path, "name: shop\nservices:\n  api:\n    image: api\n")
+	stamp := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(path, stamp, stamp))
+	discover := func() []ComposeService {
+		projects, err := DiscoverComposeProjects([]ComposeTarget{{Project: "shop", WorkDir: dir}}, nil)
+		require.NoError(t, err)
+		require.Len(t, projects, 1)
+		return projects[0].Services
+	}
+	require.Equal(t, "api", discover()[0].Name)
+
+	writeFile(t, path, "name: shop\nservices:\n  web:\n    image: web\n")
+	require.NoError(t, os.Chtimes(path, stamp, stamp))
+	assert.Equal(t, "api", discover()[0].Name, "an unchanged file is not read again")
+
+	require.NoError(t, os.Chtimes(path, stamp.Add(time.Minute), stamp.Add(time.Minute)))
+	assert.Equal(t, "web", discover()[0].Name, "a changed file is")
+}
+
+func TestComposeTarget_Exists(t *testing.T) {
+	dir := t.TempDir()
+	assert.False(t, ComposeTarget{WorkDir: dir}.Exists())
+	writeFile(t, filepath.Join(dir, "compose.yaml"), "services: {}\n")
+	assert.True(t, ComposeTarget{WorkDir: dir}.Exists())
+	assert.True(t, ComposeTarget{Files: []string{filepath.Join(dir, "compose.yaml")}}.Exists())
+	assert.False(t, ComposeTarget{Files: []string{filepath.Join(dir, "compose.yaml"), filepath.Join(dir, "gone.yaml")}}.Exists())
+}
@@ -2,19 +2,23 @@ package controller
 
 import (
 	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/rluders/berth/internal/engine"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestStreamCompose_closesChanOnExit(t *testing.T) {
 	// Use a project name that will fail fast (docker compose -p X version exits quickly).
 	ctx := context.Background()
-	ch := make(chan string, 64)
+	ch := make(chan ComposeLine, 64)
 
-	err := StreamCompose(ctx, "berth-test-nonexistent", "", ch, "version")
+	err := StreamCompose(ctx, ComposeTarget{Project: "berth-test-nonexistent"}, ch, "version")
 	// StreamCompose may or may not error depending on docker availability.
 	// What matters: ch must be closed after output drains.
 	if err != nil {
@@ -46,9 +50,9 @@ func TestStreamCompose_ctxCancelStopsStream(t *testing.T) {
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
-	ch := make(chan string, 64)
+	ch := make(chan ComposeLine, 64)
 
-	err := StreamCompose(ctx, "berth-test-cancel", "", ch, "version")
+	err := StreamCompose(ctx, ComposeTarget{Project: "berth-test-cancel"}, ch, "version")
 	if err != nil {
 		// docker compose plugin not available — drain closed channel and skip.
 		for range ch {
@@ -79,10 +83,136 @@ func TestComposeUp_callsStreamCompose(t *testing.T) {
 	// We verify the function signature compiles and error type is sensible.
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
-	ch := make(chan string, 64)
+	ch := make(chan ComposeLine, 64)
 
 	// This will fail (no compose project) but must not panic.
-	_ = ComposeUp(ctx, "berth-noproject", "/tmp", ch)
+	_ = ComposeUp(ctx, ComposeTarget{Project: "berth-noproject", WorkDir: "/tmp"}, ch)
 	for range ch {
 	}
 }
+
+func TestComposeScale_rejectsNegativeReplicas(t *testing.T) {
+	ch := make(chan ComposeLine, 1)
+
+	err := ComposeScale(context.Background(), ComposeTarget{Project: "p"}, ch, "web", -1)
+
+	assert.Error(t, err)
+	_, ok := <-ch
+	assert.False(t, ok, "channel must be closed on error")
+}
+
+func TestComposeScale_scalesThroughUp(t *testing.T) {
+	fakeComposeTool(t)
+	ch := make(chan ComposeLine, 8)
+
+	require.NoError(t, ComposeScale(context.Background(), ComposeTarget{Project: "shop"}, ch, "web", 3))
+
+	line := <-ch
+	assert.Equal(t, "-p shop up -d --no-recreate --scale web=3 web", line.Line)
+	for range ch {
+	}
+}
+
+func TestComposeServiceOf(t *testing.T) {
+	project, service, ok := ComposeServiceOf(Container{Labels: map[string]string{
+		composeProjectLabel: "shop",
+		composeServiceLabel: "web",
+	}})
+	assert.True(t, ok)
+	assert.Equal(t, "shop", project)
+	assert.Equal(t, "web", service)
+
+	_, _, ok = ComposeServiceOf(Container{Labels: map[string]string{composeProjectLabel: "shop"}})
+	assert.False(t, ok)
+}
+
+// fakeComposeTool installs a script as the compose tool. It prints its
+// arguments and exits with the status given as its last argument.
+func fakeComposeTool(t *testing.T) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "fake-compose")
+	script := "#!/bin/sh\necho \"$@\"\nfor a; do last=$a; done\nexit $last\n"
+	require.NoError(t, os.WriteFile(path, []byte(script), 0o755))
+	SetComposeTool(path)
+	t.Cleanup(func() { SetComposeTool("") })
+}
+
+func TestStreamCompose_usesConfiguredTool(t *testing.T) {
+	fakeComposeTool(t)
+	ch := make(chan ComposeLine, 8)
+
+	target := ComposeTarget{
+		Project:  "shop",
+		Files:    []string{"a.yml", "b.yml"},
+		EnvFiles: []string{".env", ".env.local"},
+		Profiles: []string{"debug"},
+	}
+	err := StreamCompose(context.Background(), target, ch, "restart", "3")
+	require.NoError(t, err)
+
+	var lines []ComposeLine
+	for l := range ch {
+		lines = append(lines, l)
+	}
+	require.Len(t, lines, 2)
+	assert.Equal(t, "-p shop -f a.yml -f b.yml --env-file .env --env-file .env.local --profile debug restart 3", lines[0].Line)
+	var exit *exec.ExitError
+	require.ErrorAs(t, lines[1].Err, &exit)
+	assert.Equal(t, 3, exit.ExitCode())
+}
+
+func TestStreamCompose_missingToolNamesIt(t *testing.T) {
+	SetComposeTool("berth-no-such-compose")
+	t.Cleanup(func() { SetComposeTool("") })
+	ch := make(chan ComposeLine, 1)
+
+	err := StreamCompose(context.Background(), ComposeTarget{Project: "shop"}, ch, "up")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `compose tool "berth-no-such-compose" from the configuration`)
+	assert.Contains(t, err.Error(), "berth-no-such-compose is not installed or not in PATH")
+	_, ok := <-ch
+	assert.False(t, ok, "channel must be closed on error")
+}
+
+func TestComposeToolName(t *testing.T) {
+	t.Cleanup(func() { SetComposeTool("") })
+
+	SetComposeTool("")
+	assert.Equal(t, "docker compose", ComposeToolName())
+	SetComposeTool("podman-compose")
+	assert.Equal(t, "podman-compose", ComposeToolName())
+}
+
+func TestComposeToolName_doesNotWaitForProbe(t *testing.T) {
+	dir := t.TempDir()
+	started := filepath.Join(dir, "started")
+	path := filepath.Join(dir, "slow-compose")
+	script := "#!/bin/sh\ntouch " + started + "\nsleep 1\n"
+	require.NoError(t, os.WriteFile(path, []byte(script), 0o755))
+	SetComposeTool(path + " compose")
+	t.Cleanup(func() { SetComposeTool("") })
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := ResolveComposeTool()
+		done <- err
+	}()
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(started)
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	name := make(chan string, 1)
+	go func() { name <- ComposeToolName() }()
+	select {
+	case n := <-name:
+		assert.Equal(t, path+" compose", n)
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("ComposeToolName blocked on the running probe")
+	}
+
+	SetComposeTool("podman-compose")
+	require.NoError(t, <-done)
+	assert.Equal(t, "podman-compose", ComposeToolName(), "a probe started before SetComposeTool is not kept")
+}
@@ -7,7 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"os/exec"
+	"sort"
 	"strings"
 
 	"github.com/docker/docker/api/types/container"
@@ -41,20 +41,57 @@ type Container struct {
 	Ports     string
 	Names     string
 	Labels    map[string]string
+	// Health is the health check state ("healthy", "unhealthy", "starting"),
+	// or empty when the container has no health check.
+	Health string
+	// Networks are the names of the networks the container is attached
+	// to, sorted.
+	Networks []string
 }
 
 // ContainerDetails holds structured inspection data for the details view.
 type ContainerDetails struct {
-	ID       string
-	Name     string
-	Image    string
-	Command  string
-	Env      []string
-	Ports    []PortBinding
-	Mounts   []Mount
-	Networks []NetworkEndpoint
-	State    string
-	Created  string
+	ID         string
+	Name       string
+	Image      string
+	ImageID    string // full ID of the image the container was created from
+	Command    string
+	Entrypoint string
+	WorkingDir string
+	User       string
+	Env        []string
+	Labels     map[string]string
+	Ports      []PortBinding
+	Mounts     []Mount
+	Networks   []NetworkEndpoint
+	State      string
+	Created    string
+	Health     *HealthCheck // nil when the container has no health check
+
+	// Lifecycle
+	StartedAt     string
+	FinishedAt    string
+	ExitCode      int
+	OOMKilled     bool
+	Error         string
+	RestartPolicy string
+	RestartCount  int
+
+	Limits    ResourceLimits
+	LogDriver string
+	LogOpts   map[string]string
+}
+
+// ResourceLimits holds a container's resource constraints. Zero means
+// unlimited (or the engine default).
+type ResourceLimits struct {
+	Memory            int64
+	MemoryReservation int64
+	MemorySwap        int64 // -1 means unlimited swap
+	NanoCPUs          int64
+	CPUShares         int64
+	CPUSetCPUs        string
+	PidsLimit         int64
 }
 
 // PortBinding represents a single port mapping.
@@ -68,6 +105,7 @@ type PortBinding struct {
 // Mount represents a volume/bind mount.
 type Mount struct {
 	Type        string
+	Name        string // volume name; empty for bind mounts
 	Source      string
 	Destination string
 	Mode        string
@@ -81,11 +119,17 @@ type NetworkEndpoint struct {
 	Gateway   string
 }
 
-// ContainerStat holds live resource usage for a container.
+// ContainerStat holds live resource usage for a container. Network and block
+// I/O counters are cumulative bytes since the container started.
 type ContainerStat struct {
 	CPUPercent float64
 	MemUsage   uint64
 	MemLimit   uint64
+	NetRx      uint64
+	NetTx      uint64
+	BlockRead  uint64
+	BlockWrite uint64
+	PIDs       uint64
 }
 
 // statsJSON is a minimal struct to decode Docker stats API response.
@@ -109,6 +153,19 @@ type statsJSON struct {
 		Limit uint64            `json:"limit"`
 		Stats map[string]uint64 `json:"stats"`
 	} `json:"memory_stats"`
+	Networks map[string]struct {
+		RxBytes uint64 `json:"rx_bytes"`
+		TxBytes uint64 `json:"tx_bytes"`
+	} `json:"networks"`
+	BlkioStats struct {
+		IoServiceBytesRecursive []struct {
+			Op    string `json:"op"`
+			Value uint64 `json:"value"`
+		} `json:"io_service_bytes_recursive"`
+	} `json:"blkio_stats"`
+	PidsStats struct {
+		Current uint64 `json:"current"`
+	} `json:"pids_stats"`
 }
 
 // ListContainers lists all running and stopped containers.
@@ -131,12 +188,27 @@ func ListContainers() ([]Container, error) {
 			Ports:     ports,
 			Names:     strings.TrimPrefix(strings.Join(c.Names, ","), "/"),
 			Labels:    c.Labels,
+			Health:    parseHealth(c.Status),
+			Networks:  containerNetworks(c),
 		})
 	}
 
 	return result, nil
 }
 
+// containerNetworks returns the sorted names of the networks c is attached to.
+func containerNetworks(c container.Summary) []string {
+	if c.NetworkSettings == nil {
+		return nil
+	}
+	networks := make([]string, 0, len(c.NetworkSettings.Networks))
+	for name := range c.NetworkSettings.Networks {
+		networks = append(networks, name)
+	}
+	sort.Strings(networks)
+	return networks
+}
+
 // StartContainer starts a container by its ID or name.
 func StartContainer(idOrName string) error {
 	return containerService.StartContainer(context.Background(), idOrName, container.StartOptions{})
@@ -158,12 +230,8 @@ func RemoveContainer(idOrName string) error {
 }
 
 // GetContainerLogs retrieves the logs of a container (one-shot).
-func GetContainerLogs(idOrName string) (logs string, err error) {
-	out, err := containerService.ContainerLogs(context.Background(), idOrName, container.LogsOptions{
-		ShowStdout: true,
-		ShowStderr: true,
-		Tail:       "500",
-	})
+func GetContainerLogs(idOrName string, opts LogOptions) (logs string, err error) {
+	out, err := containerService.ContainerLogs(context.Background(), idOrName, opts.engineOptions(false))
 	if err != nil {
 		return "", fmt.Errorf("failed to get logs for container %s: %w", idOrName, err)
 	}
@@ -186,15 +254,13 @@ func GetContainerLogs(idOrName string) (logs string, err error) {
 }
 
 // StreamContainerLogs streams container logs line by line into ch, closing ch when done or ctx cancelled.
-func StreamContainerLogs(ctx context.Context, idOrName string, ch chan<- string) {
+// Each entry records whether the line came from stdout or stderr; the output of TTY
+// containers is not multiplexed and arrives as stdout. The stream follows new output
+// unless opts sets an upper time bound.
+func StreamContainerLogs(ctx context.Context, idOrName string, opts LogOptions, ch chan<- LogEntry) {
 	defer close(ch)
 
-	out, err := containerService.ContainerLogs(ctx, idOrName, container.LogsOptions{
-		ShowStdout: true,
-		ShowStderr: true,
-		Follow:     true,
-		Tail:       "200",
-	})
+	out, err := containerService.ContainerLogs(ctx, idOrName, opts.engineOptions(opts.Until == ""))
 	if err != nil {
 		return
 	}
@@ -203,28 +269,17 @@ func StreamContainerLogs(ctx context.Context, idOrName string, ch chan<- string)
 		_ = out.Close()
 	}()
 
-	pr, pw := io.Pipe()
-	go func() {
-		var copyErr error
-		if _, err := stdcopy.StdCopy(pw, pw, out); err != nil {
-			// TTY container — fallback to raw copy
-			if _, err2 := io.Copy(pw, out); err2 != nil {
-				copyErr = err2
-			}
-		}
-		if err := pw.CloseWithError(copyErr); err != nil {
-			return
-		}
-	}()
+	stdout := &logLineWriter{ctx: ctx, ch: ch, stream: StreamStdout}
+	stderr := &logLineWriter{ctx: ctx, ch: ch, stream: StreamStderr}
 
-	scanner := bufio.NewScanner(pr)
-	for scanner.Scan() {
-		select {
-		case <-ctx.Done():
-			return
-		case ch <- scanner.Text():
-		}
+	br := bufio.NewReader(out)
+	if multiplexedLogs(br) {
+		_, _ = stdcopy.StdCopy(stdout, stderr, br)
+	} else {
+		_, _ = io.Copy(stdout, br)
 	}
+	_ = stdout.flush()
+	_ = stderr.flush()
 }
 
 // InspectContainer inspects a container and returns raw JSON.
@@ -255,18 +310,48 @@ func GetContainerDetails(idOrName string) (ContainerDetails, error) {
 	}
 
 	details := ContainerDetails{
-		ID:      inspect.ID[:12],
-		Name:    name,
-		Image:   inspect.Config.Image,
-		Command: strings.Join(inspect.Config.Cmd, " "),
-		Env:     inspect.Config.Env,
-		State:   inspect.State.Status,
-		Created: formatCreated(inspect.Created),
+		ID:           inspect.ID[:12],
+		Name:         name,
+		Image:        inspect.Config.Image,
+		ImageID:      inspect.Image,
+		Command:      strings.Join(inspect.Config.Cmd, " "),
+		Entrypoint:   strings.Join(inspect.Config.Entrypoint, " "),
+		WorkingDir:   inspect.Config.WorkingDir,
+		User:         inspect.Config.User,
+		Env:          inspect.Config.Env,
+		Labels:       inspect.Config.Labels,
+		State:        inspect.State.Status,
+		Created:      formatCreated(inspect.Created),
+		Health:       healthFromInspect(inspect),
+		StartedAt:    formatTimestamp(inspect.State.StartedAt),
+		FinishedAt:   formatTimestamp(inspect.State.FinishedAt),
+		ExitCode:     inspect.State.ExitCode,
+		OOMKilled:    inspect.State.OOMKilled,
+		Error:        inspect.State.Error,
+		RestartCount: inspect.RestartCount,
+	}
+
+	if hc := inspect.HostConfig; hc != nil {
+		details.RestartPolicy = formatRestartPolicy(hc.RestartPolicy)
+		details.LogDriver = hc.LogConfig.Type
+		details.LogOpts = hc.LogConfig.Config
+		details.Limits = ResourceLimits{
+			Memory:            hc.Memory,
+			MemoryReservation: hc.MemoryReservation,
+			MemorySwap:        hc.MemorySwap,
+			NanoCPUs:          hc.NanoCPUs,
+			CPUShares:         hc.CPUShares,
+			CPUSetCPUs:        hc.CpusetCpus,
+		}
+		if hc.PidsLimit != nil {
+			details.Limits.PidsLimit = *hc.PidsLimit
+		}
 	}
 
 	for _, m := range inspect.Mounts {
 		details.Mounts = append(details.Mounts, Mount{
 			Type:        string(m.Type),
+			Name:        m.Name,
 			Source:      m.Source,
 			Destination: m.Destination,
 			Mode:        m.Mode,
@@ -303,26 +388,6 @@ func GetContainerDetails(idOrName string) (ContainerDetails, error) {
 	return details, nil
 }
 
-// GetContainerStats returns one-shot CPU/memory stats for a container.
-func GetContainerStats(idOrName string) (stat ContainerStat, err error) {
-	resp, err := containerService.ContainerStats(context.Background(), idOrName, false)
-	if err != nil {
-		return ContainerStat{}, err
-	}
-	defer func() {
-		if closeErr := resp.Body.Close(); closeErr != nil && err == nil {
-			err = fmt.Errorf("failed to close stats response for container %s: %w", idOrName, closeErr)
-		}
-	}()
-
-	var s statsJSON
-	if err := json.NewDecoder(resp.Body).Decode(&s); err != nil {
-		return ContainerStat{}, err
-	}
-
-	return calculateStat(s), nil
-}
-
 func calculateStat(s statsJSON) ContainerStat {
 	cpuDelta := float64(s.CPUStats.CPUUsage.TotalUsage) - float64(s.PreCPUStats.CPUUsage.TotalUsage)
 	systemDelta := float64(s.CPUStats.SystemCPUUsage) - float64(s.PreCPUStats.SystemCPUUsage)
@@ -330,22 +395,66 @@ func calculateStat(s statsJSON) ContainerStat {
 	if numCPUs == 0 {
 		numCPUs = float64(len(s.CPUStats.CPUUsage.PercpuUsage))
 	}
+	if numCPUs == 0 {
+		// Some Podman versions report neither; show usage relative to one CPU.
+		numCPUs = 1
+	}
 
 	var cpuPercent float64
 	if systemDelta > 0 && cpuDelta > 0 {
 		cpuPercent = (cpuDelta / systemDelta) * numCPUs * 100.0
 	}
 
-	memUsage := s.MemoryStats.Usage
-	if cache, ok := s.MemoryStats.Stats["cache"]; ok {
-		memUsage -= cache
+	memLimit := s.MemoryStats.Limit
+	if memLimit >= unlimitedMemory {
+		memLimit = 0
 	}
 
-	return ContainerStat{
+	stat := ContainerStat{
 		CPUPercent: cpuPercent,
-		MemUsage:   memUsage,
-		MemLimit:   s.MemoryStats.Limit,
+		MemUsage:   memUsageNoCache(s.MemoryStats.Usage, s.MemoryStats.Stats),
+		MemLimit:   memLimit,
+		PIDs:       s.PidsStats.Current,
+	}
+	for _, n := range s.Networks {
+		stat.NetRx += n.RxBytes
+		stat.NetTx += n.TxBytes
+	}
+	for _, io := range s.BlkioStats.IoServiceBytesRecursive {
+		// cgroup v1 reports "Read"/"Write", cgroup v2 "read"/"write".
+		switch strings.ToLower(io.Op) {
+		case "read":
+			stat.BlockRead += io.Value
+		case "write":
+			stat.BlockWrite += io.Value
+		}
+	}
+	return stat
+}
+
+// unlimitedMemory is the smallest limit treated as "no limit": cgroups report
+// an unset limit as a page-aligned value close to MaxInt64 (or MaxUint64 on
+// some Podman versions) rather than the host's memory.
+const unlimitedMemory = 1 << 62
+
+// memUsageNoCache returns memory usage without reclaimable page cache, the
+// same figure "docker stats" shows:
+//   - cgroup v1 reports total_inactive_file,
+//   - cgroup v2 reports inactive_file,
+//   - older daemons only report cache.
+//
+// Podman's compat API leaves stats empty and already excludes the cache from
+// usage, so usage is returned unchanged.
+func memUsageNoCache(usage uint64, stats map[string]uint64) uint64 {
+	for _, key := range []string{"total_inactive_file", "inactive_file", "cache"} {
+		if v, ok := stats[key]; ok {
+			if v < usage {
+				return usage - v
+			}
+			return usage
+		}
 	}
+	return usage
 }
 
 // formatCreated parses a Docker RFC3339 created timestamp into a human-readable age.
@@ -367,13 +476,27 @@ func formatCreated(s string) string {
 	}
 }
 
-// ExecShell returns an exec.Cmd that opens an interactive shell in the container.
-func ExecShell(containerID string) *exec.Cmd {
-	enginePath := engine.GetEnginePath()
-	if enginePath == "" {
-		enginePath = "docker"
+// formatTimestamp renders an inspect timestamp as local time with its age,
+// or "" for the zero time the engine reports for events that never happened.
+func formatTimestamp(s string) string {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil || t.Year() <= 1 {
+		return ""
+	}
+	return t.Local().Format("2006-01-02 15:04:05") + " (" + formatCreated(s) + ")"
+}
+
+// formatRestartPolicy renders a restart policy like the docker CLI flag,
+// e.g. "on-failure:5".
+func formatRestartPolicy(p container.RestartPolicy) string {
+	name := string(p.Name)
+	if name == "" {
+		return "no"
+	}
+	if p.MaximumRetryCount > 0 {
+		return fmt.Sprintf("%s:%d", name, p.MaximumRetryCount)
 	}
-	return exec.Command(enginePath, "exec", "-it", containerID, "/bin/sh")
+	return name
 }
 
 // formatPorts converts Docker port list to a compact string.
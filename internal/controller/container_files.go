@@ -0,0 +1,277 @@
+package controller
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// FileChange is one entry of a container's writable-layer diff.
+type FileChange struct {
+	Kind string // "A" added, "C" changed, "D" deleted
+	Path string
+}
+
+// GetContainerChanges lists paths added, changed or deleted in the container's
+// writable layer, sorted by path.
+func GetContainerChanges(idOrName string) ([]FileChange, error) {
+	changes, err := containerService.ContainerDiff(context.Background(), idOrName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get changes for container %s: %w", idOrName, err)
+	}
+
+	result := make([]FileChange, 0, len(changes))
+	for _, c := range changes {
+		result = append(result, FileChange{Kind: c.Kind.String(), Path: c.Path})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Path < result[j].Path })
+	return result, nil
+}
+
+// CopyFromContainer copies srcPath out of the container to hostDest, following
+// `docker cp` semantics: when hostDest is an existing directory the item is
+// placed inside it, otherwise it is written as hostDest. Returns the host path
+// that was written and the archive entries that could not be, such as devices.
+func CopyFromContainer(idOrName, srcPath, hostDest string) (written string, skipped []string, err error) {
+	rc, _, err := containerService.CopyFromContainer(context.Background(), idOrName, srcPath)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to copy %s from container %s: %w", srcPath, idOrName, err)
+	}
+	defer func() {
+		// The archive has been fully consumed (or extraction failed); close errors carry no extra information.
+		_ = rc.Close()
+	}()
+
+	destDir, rename := hostDest, ""
+	if fi, err := os.Stat(hostDest); err != nil || !fi.IsDir() {
+		destDir, rename = filepath.Dir(hostDest), filepath.Base(hostDest)
+	}
+	if err := os.MkdirAll(destDir, 0o755); err != nil {
+		return "", nil, fmt.Errorf("failed to create %s: %w", destDir, err)
+	}
+
+	written, skipped, err = extractTar(rc, destDir, rename)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to extract %s: %w", srcPath, err)
+	}
+	return written, skipped, nil
+}
+
+// CopyToContainer copies hostSrc (a file or directory) into the container,
+// following `docker cp` semantics: when containerDest is an existing
+// directory the item is placed inside it, otherwise it is written as
+// containerDest.
+func CopyToContainer(idOrName, hostSrc, containerDest string) error {
+	if _, err := os.Lstat(hostSrc); err != nil {
+		return fmt.Errorf("failed to read %s: %w", hostSrc, err)
+	}
+
+	destDir, name := containerDest, filepath.Base(hostSrc)
+	stat, err := containerService.ContainerStatPath(context.Background(), idOrName, containerDest)
+	if err != nil || !stat.Mode.IsDir() {
+		destDir, name = path.Dir(containerDest), path.Base(containerDest)
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		_ = pw.CloseWithError(writeTar(pw, hostSrc, name))
+	}()
+
+	if err := containerService.CopyToContainer(context.Background(), idOrName, destDir, pr, container.CopyToContainerOptions{}); err != nil {
+		_ = pr.CloseWithError(err)
+		return fmt.Errorf("failed to copy %s to container %s: %w", hostSrc, idOrName, err)
+	}
+	return nil
+}
+
+// writeTar archives src into w with its top-level entry renamed to name.
+func writeTar(w io.Writer, src, name string) error {
+	tw := tar.NewWriter(w)
+	err := filepath.Walk(src, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		link := ""
+		if fi.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(p); err != nil {
+				return err
+			}
+		}
+		hdr, err := tar.FileInfoHeader(fi, link)
+		if err != nil {
+			return err
+		}
+		hdr.Name = path.Join(name, filepath.ToSlash(rel))
+		if fi.IsDir() {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !fi.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// extractTar unpacks r into destDir. When rename is set, the archive's
+// top-level entry is written under that name instead. Entries escaping
+// destDir are rejected. Returns the path of the top-level entry written and
+// the names of the entries skipped, such as devices and fifos.
+func extractTar(r io.Reader, destDir, rename string) (top string, skipped []string, err error) {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", nil, err
+		}
+
+		name, err := archiveEntryName(hdr.Name, rename)
+		if err != nil {
+			return "", nil, err
+		}
+		target := filepath.Join(destDir, filepath.FromSlash(name))
+		if top == "" {
+			first, _, _ := strings.Cut(name, "/")
+			top = filepath.Join(destDir, first)
+		}
+
+		if err := ensureInside(destDir, filepath.Dir(target)); err != nil {
+			return "", nil, err
+		}
+		if fi, err := os.Lstat(target); err == nil && fi.Mode()&os.ModeSymlink != 0 && hdr.Typeflag != tar.TypeSymlink {
+			return "", nil, fmt.Errorf("refusing to write through symlink: %s", hdr.Name)
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, hdr.FileInfo().Mode().Perm()|0o700); err != nil {
+				return "", nil, err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return "", nil, err
+			}
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, hdr.FileInfo().Mode().Perm())
+			if err != nil {
+				return "", nil, err
+			}
+			if _, err := io.Copy(f, tr); err != nil {
+				_ = f.Close()
+				return "", nil, err
+			}
+			if err := f.Close(); err != nil {
+				return "", nil, err
+			}
+		case tar.TypeSymlink:
+			// Links are kept as written, like docker cp does; the checks
+			// above stop later entries from being written through them.
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return "", nil, err
+			}
+			_ = os.Remove(target)
+			if err := os.Symlink(hdr.Linkname, target); err != nil {
+				return "", nil, err
+			}
+		case tar.TypeLink:
+			linkName, err := archiveEntryName(hdr.Linkname, rename)
+			if err != nil {
+				return "", nil, err
+			}
+			source := filepath.Join(destDir, filepath.FromSlash(linkName))
+			if err := ensureInside(destDir, filepath.Dir(source)); err != nil {
+				return "", nil, err
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return "", nil, err
+			}
+			_ = os.Remove(target)
+			if err := os.Link(source, target); err != nil {
+				return "", nil, fmt.Errorf("failed to link %s to %s: %w", hdr.Name, hdr.Linkname, err)
+			}
+		default:
+			skipped = append(skipped, hdr.Name)
+		}
+	}
+
+	if top == "" {
+		return "", nil, fmt.Errorf("archive is empty")
+	}
+	return top, skipped, nil
+}
+
+// archiveEntryName returns the cleaned, relative name of an archive entry,
+// with its top-level part replaced by rename when set.
+func archiveEntryName(name, rename string) (string, error) {
+	clean := path.Clean(strings.TrimPrefix(name, "/"))
+	if clean == "." || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("invalid path in archive: %s", name)
+	}
+	if rename != "" {
+		_, rest, _ := strings.Cut(clean, "/")
+		clean = path.Join(rename, rest)
+	}
+	return clean, nil
+}
+
+// isInside reports whether p, a cleaned path, is dir or lies below it.
+func isInside(dir, p string) bool {
+	rel, err := filepath.Rel(dir, p)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// ensureInside checks that p still lies below destDir once the symlinks of
+// its deepest existing ancestor are resolved, so that entries are never
+// written through a link pointing elsewhere.
+func ensureInside(destDir, p string) error {
+	root, err := filepath.EvalSymlinks(destDir)
+	if err != nil {
+		return err
+	}
+	existing, rest := p, ""
+	for {
+		if _, err := os.Lstat(existing); err == nil {
+			break
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			break
+		}
+		rest = filepath.Join(filepath.Base(existing), rest)
+		existing = parent
+	}
+	resolved, err := filepath.EvalSymlinks(existing)
+	if err != nil {
+		return err
+	}
+	if !isInside(root, filepath.Join(resolved, rest)) {
+		return fmt.Errorf("refusing to write outside %s: %s", destDir, p)
+	}
+	return nil
+}
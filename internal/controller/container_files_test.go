@@ -0,0 +1,270 @@
+package controller
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/rluders/berth/internal/service"
+	clientmock "github.com/rluders/berth/mocks/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetContainerChanges_sortedByPath(t *testing.T) {
+	mockClient := clientmock.NewMockAPIClient(t)
+	mockClient.EXPECT().
+		ContainerDiff(mock.Anything, "abc123").
+		Return([]container.FilesystemChange{
+			{Kind: container.ChangeModify, Path: "/etc"},
+			{Kind: container.ChangeDelete, Path: "/app/old.txt"},
+			{Kind: container.ChangeAdd, Path: "/etc/app.conf"},
+		}, nil)
+
+	setContainerServiceForTest(service.NewContainerService(mockClient))
+
+	changes, err := GetContainerChanges("abc123")
+
+	require.NoError(t, err)
+	assert.Equal(t, []FileChange{
+		{Kind: "D", Path: "/app/old.txt"},
+		{Kind: "C", Path: "/etc"},
+		{Kind: "A", Path: "/etc/app.conf"},
+	}, changes)
+}
+
+func TestGetContainerChanges_propagatesError(t *testing.T) {
+	mockClient := clientmock.NewMockAPIClient(t)
+	mockClient.EXPECT().
+		ContainerDiff(mock.Anything, "abc123").
+		Return(nil, errors.New("no such container"))
+
+	setContainerServiceForTest(service.NewContainerService(mockClient))
+
+	_, err := GetContainerChanges("abc123")
+	assert.ErrorContains(t, err, "no such container")
+}
+
+func TestTarRoundTrip_directory(t *testing.T) {
+	src := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "conf", "nested"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "conf", "app.conf"), []byte("port=80"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "conf", "nested", "x"), []byte("x"), 0o600))
+
+	var buf bytes.Buffer
+	require.NoError(t, writeTar(&buf, filepath.Join(src, "conf"), "conf"))
+
+	dest := t.TempDir()
+	top, _, err := extractTar(&buf, dest, "")
+
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dest, "conf"), top)
+	data, err := os.ReadFile(filepath.Join(dest, "conf", "app.conf"))
+	require.NoError(t, err)
+	assert.Equal(t, "port=80", string(data))
+	fi, err := os.Stat(filepath.Join(dest, "conf", "nested", "x"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), fi.Mode().Perm())
+}
+
+func TestExtractTar_renamesTopLevelEntry(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "hosts")
+	require.NoError(t, os.WriteFile(src, []byte("127.0.0.1 localhost"), 0o644))
+
+	var buf bytes.Buffer
+	require.NoError(t, writeTar(&buf, src, "hosts"))
+
+	dest := t.TempDir()
+	top, _, err := extractTar(&buf, dest, "hosts.bak")
+
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dest, "hosts.bak"), top)
+	data, err := os.ReadFile(top)
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1 localhost", string(data))
+}
+
+func TestExtractTar_rejectsPathTraversal(t *testing.T) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "../evil", Mode: 0o644, Size: 1, Typeflag: tar.TypeReg}))
+	_, err := tw.Write([]byte("x"))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+
+	_, _, err = extractTar(&buf, t.TempDir(), "")
+	assert.ErrorContains(t, err, "invalid path")
+}
+
+func TestExtractTar_keepsLinksAsWrittenButNotWritesThroughThem(t *testing.T) {
+	outside := t.TempDir()
+	for _, link := range []string{"/usr/share/zoneinfo/UTC", "../../outside", outside} {
+		var buf bytes.Buffer
+		tw := tar.NewWriter(&buf)
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: "etc/", Mode: 0o755, Typeflag: tar.TypeDir}))
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: "etc/localtime", Linkname: link, Typeflag: tar.TypeSymlink}))
+		require.NoError(t, tw.Close())
+
+		dest := t.TempDir()
+		_, _, err := extractTar(&buf, dest, "")
+		require.NoError(t, err, link)
+		got, err := os.Readlink(filepath.Join(dest, "etc", "localtime"))
+		require.NoError(t, err)
+		assert.Equal(t, link, got)
+	}
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "etc/out", Linkname: outside, Typeflag: tar.TypeSymlink}))
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "etc/out/passwd", Mode: 0o644, Size: 1, Typeflag: tar.TypeReg}))
+	_, err := tw.Write([]byte("x"))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+
+	_, _, err = extractTar(&buf, t.TempDir(), "")
+	assert.ErrorContains(t, err, "refusing to write outside")
+	assert.NoFileExists(t, filepath.Join(outside, "passwd"))
+}
+
+func TestExtractTar_hardLinksAndSkippedEntries(t *testing.T) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "bin/", Mode: 0o755, Typeflag: tar.TypeDir}))
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "bin/gzip", Mode: 0o755, Size: 2, Typeflag: tar.TypeReg}))
+	_, err := tw.Write([]byte("gz"))
+	require.NoError(t, err)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "bin/gunzip", Linkname: "bin/gzip", Typeflag: tar.TypeLink}))
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "bin/fifo", Mode: 0o644, Typeflag: tar.TypeFifo}))
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "bin/escape", Linkname: "../etc/passwd", Typeflag: tar.TypeLink}))
+	require.NoError(t, tw.Close())
+
+	dest := t.TempDir()
+	_, skipped, err := extractTar(&buf, dest, "usr")
+	assert.ErrorContains(t, err, "invalid path", "a hard link must point into the archive")
+	assert.Nil(t, skipped)
+
+	data, err := os.ReadFile(filepath.Join(dest, "usr", "gunzip"))
+	require.NoError(t, err, "the link follows the renamed top-level entry")
+	assert.Equal(t, "gz", string(data))
+	assert.NoFileExists(t, filepath.Join(dest, "usr", "fifo"))
+}
+
+func TestExtractTar_reportsSkippedEntries(t *testing.T) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "dev/", Mode: 0o755, Typeflag: tar.TypeDir}))
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "dev/null", Mode: 0o666, Typeflag: tar.TypeChar, Devmajor: 1, Devminor: 3}))
+	require.NoError(t, tw.Close())
+
+	_, skipped, err := extractTar(&buf, t.TempDir(), "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dev/null"}, skipped)
+}
+
+func TestExtractTar_refusesToWriteThroughSymlink(t *testing.T) {
+	outside := t.TempDir()
+	dest := t.TempDir()
+	require.NoError(t, os.Symlink(outside, filepath.Join(dest, "dir")))
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "dir/passwd", Mode: 0o644, Size: 1, Typeflag: tar.TypeReg}))
+	_, err := tw.Write([]byte("x"))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+
+	_, _, err = extractTar(&buf, dest, "")
+	assert.ErrorContains(t, err, "refusing to write outside")
+	assert.NoFileExists(t, filepath.Join(outside, "passwd"))
+}
+
+func TestExtractTar_keepsLinksInsideArchive(t *testing.T) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "app/", Mode: 0o755, Typeflag: tar.TypeDir}))
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "app/current", Linkname: "releases/v2", Typeflag: tar.TypeSymlink}))
+	require.NoError(t, tw.Close())
+
+	dest := t.TempDir()
+	_, _, err := extractTar(&buf, dest, "")
+	require.NoError(t, err)
+	link, err := os.Readlink(filepath.Join(dest, "app", "current"))
+	require.NoError(t, err)
+	assert.Equal(t, "releases/v2", link)
+}
+
+func TestCopyFromContainer_intoExistingDirectory(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "app.conf")
+	require.NoError(t, os.WriteFile(src, []byte("debug=true"), 0o644))
+	var buf bytes.Buffer
+	require.NoError(t, writeTar(&buf, src, "app.conf"))
+
+	mockClient := clientmock.NewMockAPIClient(t)
+	mockClient.EXPECT().
+		CopyFromContainer(mock.Anything, "abc123", "/etc/app.conf").
+		Return(io.NopCloser(&buf), container.PathStat{Name: "app.conf"}, nil)
+
+	setContainerServiceForTest(service.NewContainerService(mockClient))
+
+	dest := t.TempDir()
+	written, _, err := CopyFromContainer("abc123", "/etc/app.conf", dest)
+
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dest, "app.conf"), written)
+}
+
+// copyRecorder is a container service reading the archive CopyToContainer
+// sends. Unlike the client mock it never formats the content reader, which
+// the archive goroutine is still writing to.
+type copyRecorder struct {
+	service.ContainerService
+	stat    container.PathStat
+	dstPath string
+	names   []string
+}
+
+func (c *copyRecorder) ContainerStatPath(context.Context, string, string) (container.PathStat, error) {
+	return c.stat, nil
+}
+
+func (c *copyRecorder) CopyToContainer(_ context.Context, _, dstPath string, content io.Reader, _ container.CopyToContainerOptions) error {
+	c.dstPath = dstPath
+	tr := tar.NewReader(content)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		c.names = append(c.names, hdr.Name)
+	}
+	// Reading to the end of the pipe waits for the archive goroutine.
+	_, err := io.Copy(io.Discard, content)
+	return err
+}
+
+func TestCopyToContainer_intoExistingDirectory(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "patch.txt")
+	require.NoError(t, os.WriteFile(src, []byte("fixed"), 0o644))
+
+	rec := &copyRecorder{stat: container.PathStat{Name: "app", Mode: os.ModeDir | 0o755}}
+	setContainerServiceForTest(rec)
+
+	require.NoError(t, CopyToContainer("abc123", src, "/app"))
+	assert.Equal(t, "/app", rec.dstPath)
+	assert.Equal(t, []string{"patch.txt"}, rec.names)
+}
+
+func TestCopyToContainer_missingHostPath(t *testing.T) {
+	err := CopyToContainer("abc123", filepath.Join(t.TempDir(), "missing"), "/app")
+	assert.ErrorContains(t, err, "failed to read")
+}
@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// Health check states reported in Container.Health.
+const (
+	HealthHealthy   = "healthy"
+	HealthUnhealthy = "unhealthy"
+	HealthStarting  = "starting"
+)
+
+// parseHealth extracts the health check state from a container list status
+// such as "Up 5 minutes (unhealthy)" or "Up 3 seconds (health: starting)".
+func parseHealth(status string) string {
+	switch {
+	case strings.HasSuffix(status, "(unhealthy)"):
+		return HealthUnhealthy
+	case strings.HasSuffix(status, "(healthy)"):
+		return HealthHealthy
+	case strings.HasSuffix(status, "(health: starting)"):
+		return HealthStarting
+	}
+	return ""
+}
+
+// GetRestartCounts inspects each container and returns how often the engine
+// has restarted it in total. The container list does not carry this, so it costs one
+// inspect per container; containers that fail to inspect are left out.
+func GetRestartCounts(ids []string) map[string]int {
+	counts := make(map[string]int, len(ids))
+	for _, id := range ids {
+		info, err := containerService.ContainerInspect(context.Background(), id)
+		if err != nil {
+			slog.Debug("GetRestartCounts: inspect failed", "id", id, "err", err)
+			continue
+		}
+		counts[id] = info.RestartCount
+	}
+	return counts
+}
+
+// maxHealthProbes caps the probe results kept in HealthCheck.Probes.
+const maxHealthProbes = 5
+
+// HealthCheck describes a container's health check and its latest probes.
+type HealthCheck struct {
+	Test          []string
+	Interval      time.Duration
+	Timeout       time.Duration
+	StartPeriod   time.Duration
+	Retries       int
+	Status        string
+	FailingStreak int
+	Probes        []HealthProbe // newest first
+}
+
+// HealthProbe is the result of one health check run.
+type HealthProbe struct {
+	Start    time.Time
+	End      time.Time
+	ExitCode int
+	Output   string
+}
+
+// healthFromInspect extracts the health check of an inspected container, or
+// nil when it has none (or it was disabled with NONE).
+func healthFromInspect(inspect container.InspectResponse) *HealthCheck {
+	var cfg *container.HealthConfig
+	if inspect.Config != nil {
+		cfg = inspect.Config.Healthcheck
+	}
+	var state *container.Health
+	if inspect.ContainerJSONBase != nil && inspect.State != nil {
+		state = inspect.State.Health
+	}
+	if state == nil && (cfg == nil || len(cfg.Test) == 0 || cfg.Test[0] == "NONE") {
+		return nil
+	}
+
+	hc := &HealthCheck{}
+	if cfg != nil {
+		hc.Test = cfg.Test
+		hc.Interval = cfg.Interval
+		hc.Timeout = cfg.Timeout
+		hc.StartPeriod = cfg.StartPeriod
+		hc.Retries = cfg.Retries
+	}
+	if state != nil {
+		hc.Status = string(state.Status)
+		hc.FailingStreak = state.FailingStreak
+		for i := len(state.Log) - 1; i >= 0 && len(hc.Probes) < maxHealthProbes; i-- {
+			r := state.Log[i]
+			if r == nil {
+				continue
+			}
+			hc.Probes = append(hc.Probes, HealthProbe{
+				Start:    r.Start,
+				End:      r.End,
+				ExitCode: r.ExitCode,
+				Output:   strings.TrimSpace(r.Output),
+			})
+		}
+	}
+	return hc
+}
@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/rluders/berth/internal/service"
+	clientmock "github.com/rluders/berth/mocks/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHealth(t *testing.T) {
+	tests := []struct {
+		status string
+		want   string
+	}{
+		{"Up 5 minutes (healthy)", HealthHealthy},
+		{"Up 5 minutes (unhealthy)", HealthUnhealthy},
+		{"Up 3 seconds (health: starting)", HealthStarting},
+		{"Up 2 hours", ""},
+		{"Exited (1) 3 minutes ago", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseHealth(tt.status))
+		})
+	}
+}
+
+func TestGetRestartCounts_skipsFailedInspects(t *testing.T) {
+	mockClient := clientmock.NewMockAPIClient(t)
+	mockClient.EXPECT().
+		ContainerInspect(mock.Anything, "abc123").
+		Return(container.InspectResponse{ContainerJSONBase: &container.ContainerJSONBase{RestartCount: 4}}, nil)
+	mockClient.EXPECT().
+		ContainerInspect(mock.Anything, "def456").
+		Return(container.InspectResponse{}, errors.New("no such container"))
+
+	setContainerServiceForTest(service.NewContainerService(mockClient))
+
+	assert.Equal(t, map[string]int{"abc123": 4}, GetRestartCounts([]string{"abc123", "def456"}))
+}
+
+func TestHealthFromInspect(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var log []*container.HealthcheckResult
+	for i := range 7 {
+		log = append(log, &container.HealthcheckResult{
+			Start:    start.Add(time.Duration(i) * time.Minute),
+			End:      start.Add(time.Duration(i)*time.Minute + time.Second),
+			ExitCode: i % 2,
+			Output:   fmt.Sprintf("probe %d\n", i),
+		})
+	}
+	inspect := container.InspectResponse{
+		ContainerJSONBase: &container.ContainerJSONBase{
+			State: &container.State{Health: &container.Health{Status: container.Unhealthy, FailingStreak: 1, Log: log}},
+		},
+		Config: &container.Config{Healthcheck: &container.HealthConfig{
+			Test:     []string{"CMD-SHELL", "curl -f http://localhost/"},
+			Interval: 30 * time.Second,
+			Timeout:  5 * time.Second,
+			Retries:  3,
+		}},
+	}
+
+	hc := healthFromInspect(inspect)
+
+	require.NotNil(t, hc)
+	assert.Equal(t, []string{"CMD-SHELL", "curl -f http://localhost/"}, hc.Test)
+	assert.Equal(t, 30*time.Second, hc.Interval)
+	assert.Equal(t, 3, hc.Retries)
+	assert.Equal(t, HealthUnhealthy, hc.Status)
+	assert.Equal(t, 1, hc.FailingStreak)
+	require.Len(t, hc.Probes, maxHealthProbes)
+	assert.Equal(t, "probe 6", hc.Probes[0].Output, "newest probe first, output trimmed")
+	assert.Equal(t, 0, hc.Probes[0].ExitCode)
+	assert.Equal(t, "probe 2", hc.Probes[maxHealthProbes-1].Output)
+}
+
+func TestHealthFromInspect_noHealthCheck(t *testing.T) {
+	base := &container.ContainerJSONBase{State: &container.State{}}
+	assert.Nil(t, healthFromInspect(container.InspectResponse{ContainerJSONBase: base, Config: &container.Config{}}))
+	assert.Nil(t, healthFromInspect(container.InspectResponse{
+		ContainerJSONBase: base,
+		Config:            &container.Config{Healthcheck: &container.HealthConfig{Test: []string{"NONE"}}},
+	}))
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
 	clientmock "github.com/rluders/berth/mocks/client"
 	"github.com/rluders/berth/internal/service"
 	"github.com/stretchr/testify/assert"
@@ -24,6 +25,10 @@ func TestListContainers_returnsMappedContainers(t *testing.T) {
 				Names:   []string{"/my-nginx"},
 				Status:  "Up 2 hours",
 				State:   "running",
+				NetworkSettings: &container.NetworkSettingsSummary{Networks: map[string]*network.EndpointSettings{
+					"shop_default": {},
+					"bridge":       {},
+				}},
 			},
 		}, nil)
 
@@ -37,6 +42,7 @@ func TestListContainers_returnsMappedContainers(t *testing.T) {
 	assert.Equal(t, "nginx:latest", result[0].Image)
 	assert.Equal(t, "my-nginx", result[0].Names) // leading / stripped
 	assert.Equal(t, "running", result[0].State)
+	assert.Equal(t, []string{"bridge", "shop_default"}, result[0].Networks)
 }
 
 func TestListContainers_propagatesError(t *testing.T) {
@@ -98,3 +104,57 @@ func TestRemoveContainer_callsServiceWithForce(t *testing.T) {
 	err := RemoveContainer("abc123")
 	assert.NoError(t, err)
 }
+
+func TestGetContainerDetails_mapsInspectFields(t *testing.T) {
+	pids := int64(100)
+	mockClient := clientmock.NewMockAPIClient(t)
+	mockClient.EXPECT().
+		ContainerInspect(mock.Anything, "abc123").
+		Return(container.InspectResponse{
+			ContainerJSONBase: &container.ContainerJSONBase{
+				ID:           "abcdef1234567890",
+				Name:         "/api",
+				RestartCount: 2,
+				State: &container.State{
+					Status:     "exited",
+					ExitCode:   137,
+					OOMKilled:  true,
+					FinishedAt: "0001-01-01T00:00:00Z",
+				},
+				HostConfig: &container.HostConfig{
+					RestartPolicy: container.RestartPolicy{Name: container.RestartPolicyOnFailure, MaximumRetryCount: 3},
+					LogConfig:     container.LogConfig{Type: "json-file", Config: map[string]string{"max-size": "10m"}},
+					Resources:     container.Resources{Memory: 512 << 20, NanoCPUs: 1_500_000_000, PidsLimit: &pids},
+				},
+			},
+			Config: &container.Config{
+				Image:      "api:latest",
+				Entrypoint: []string{"/entrypoint.sh"},
+				Cmd:        []string{"serve", "--port", "80"},
+				WorkingDir: "/app",
+				User:       "1000:1000",
+				Labels:     map[string]string{"team": "core"},
+			},
+			NetworkSettings: &container.NetworkSettings{},
+		}, nil)
+
+	setContainerServiceForTest(service.NewContainerService(mockClient))
+
+	d, err := GetContainerDetails("abc123")
+
+	require.NoError(t, err)
+	assert.Equal(t, "api", d.Name)
+	assert.Equal(t, "/entrypoint.sh", d.Entrypoint)
+	assert.Equal(t, "serve --port 80", d.Command)
+	assert.Equal(t, "/app", d.WorkingDir)
+	assert.Equal(t, "1000:1000", d.User)
+	assert.Equal(t, map[string]string{"team": "core"}, d.Labels)
+	assert.Equal(t, 137, d.ExitCode)
+	assert.True(t, d.OOMKilled)
+	assert.Empty(t, d.FinishedAt)
+	assert.Equal(t, "on-failure:3", d.RestartPolicy)
+	assert.Equal(t, 2, d.RestartCount)
+	assert.Equal(t, "json-file", d.LogDriver)
+	assert.Equal(t, map[string]string{"max-size": "10m"}, d.LogOpts)
+	assert.Equal(t, ResourceLimits{Memory: 512 << 20, NanoCPUs: 1_500_000_000, PidsLimit: 100}, d.Limits)
+}
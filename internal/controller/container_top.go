@@ -0,0 +1,144 @@
+package controller
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// Process is one entry of a container's process list.
+type Process struct {
+	PID     string // host PID, as reported by the engine
+	User    string
+	CPU     float64 // percent; -1 when ps did not report it
+	Memory  float64 // percent; -1 when ps did not report it
+	Command string
+}
+
+// topArgs asks ps for CPU and memory columns; engines whose ps rejects them
+// are retried with their default arguments.
+var topArgs = []string{"aux"}
+
+// GetContainerProcesses lists the processes running in a container.
+func GetContainerProcesses(idOrName string) ([]Process, error) {
+	ctx := context.Background()
+	top, err := containerService.ContainerTop(ctx, idOrName, topArgs)
+	if err != nil {
+		top, err = containerService.ContainerTop(ctx, idOrName, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return parseTop(top), nil
+}
+
+// parseTop maps ps output to processes by column title, so both the "aux"
+// (USER, %CPU, %MEM, COMMAND) and default "-ef" (UID, C, CMD) layouts work.
+func parseTop(top container.TopResponse) []Process {
+	col := map[string]int{}
+	for i, title := range top.Titles {
+		col[strings.ToUpper(strings.TrimSpace(title))] = i
+	}
+	index := func(names ...string) int {
+		for _, n := range names {
+			if i, ok := col[n]; ok {
+				return i
+			}
+		}
+		return -1
+	}
+	pidCol := index("PID")
+	userCol := index("USER", "UID")
+	cpuCol := index("%CPU", "C")
+	memCol := index("%MEM")
+	cmdCol := index("COMMAND", "CMD", "ARGS")
+
+	field := func(row []string, i int) string {
+		if i < 0 || i >= len(row) {
+			return ""
+		}
+		return row[i]
+	}
+	percent := func(row []string, i int) float64 {
+		v, err := strconv.ParseFloat(field(row, i), 64)
+		if err != nil {
+			return -1
+		}
+		return v
+	}
+
+	procs := make([]Process, 0, len(top.Processes))
+	for _, row := range top.Processes {
+		procs = append(procs, Process{
+			PID:     field(row, pidCol),
+			User:    field(row, userCol),
+			CPU:     percent(row, cpuCol),
+			Memory:  percent(row, memCol),
+			Command: field(row, cmdCol),
+		})
+	}
+	return procs
+}
+
+// SignalProcess sends signal (e.g. "TERM", "KILL") to a process in the
+// container by running kill through exec. hostPID is translated to the PID
+// inside the container's namespace, which needs the host's /proc; without it
+// no signal is sent rather than risk signalling another process.
+func SignalProcess(idOrName, hostPID, signal string) error {
+	pid, err := namespacedPID(hostPID)
+	if err != nil {
+		return err
+	}
+	var stderr bytes.Buffer
+	code, err := runExec(context.Background(), idOrName, ExecConfig{Cmd: []string{"kill", "-s", signal, pid}}, nil, io.Discard, &stderr)
+	if err != nil {
+		return fmt.Errorf("failed to signal process %s: %w", pid, err)
+	}
+	if code != 0 {
+		return fmt.Errorf("kill -s %s %s exited with code %d: %s", signal, pid, code, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// procRoot is the host proc filesystem; tests point it at a fixture.
+var procRoot = "/proc"
+
+// CanSignalProcess reports whether SignalProcess can find hostPID in the
+// container, which needs the engine host's /proc: false with a remote engine
+// or a VM-based desktop.
+func CanSignalProcess(hostPID string) bool {
+	_, err := os.Stat(filepath.Join(procRoot, hostPID, "status"))
+	return err == nil
+}
+
+// namespacedPID maps a host PID to the PID seen inside the container using
+// the last NSpid entry of /proc/<pid>/status. It fails when the host's /proc
+// is not reachable (remote engine, VM-based desktop).
+func namespacedPID(hostPID string) (string, error) {
+	f, err := os.Open(filepath.Join(procRoot, hostPID, "status"))
+	if err != nil {
+		return "", fmt.Errorf("failed to find PID %s in the container: %w", hostPID, err)
+	}
+	defer func() {
+		// Read-only file; a close failure cannot affect the result.
+		_ = f.Close()
+	}()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if rest, ok := strings.CutPrefix(scanner.Text(), "NSpid:"); ok {
+			if ids := strings.Fields(rest); len(ids) > 0 {
+				return ids[len(ids)-1], nil
+			}
+		}
+	}
+	return "", fmt.Errorf("failed to find PID %s in the container: no NSpid entry", hostPID)
+}
@@ -0,0 +1,137 @@
+package controller
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/rluders/berth/internal/service"
+	clientmock "github.com/rluders/berth/mocks/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTop(t *testing.T) {
+	tests := []struct {
+		name string
+		top  container.TopResponse
+		want []Process
+	}{
+		{
+			name: "ps aux layout",
+			top: container.TopResponse{
+				Titles:    []string{"USER", "PID", "%CPU", "%MEM", "VSZ", "RSS", "TTY", "STAT", "START", "TIME", "COMMAND"},
+				Processes: [][]string{{"root", "4242", "1.5", "0.3", "1000", "200", "?", "Ss", "10:00", "0:01", "nginx: master process"}},
+			},
+			want: []Process{{PID: "4242", User: "root", CPU: 1.5, Memory: 0.3, Command: "nginx: master process"}},
+		},
+		{
+			name: "default ps -ef layout",
+			top: container.TopResponse{
+				Titles:    []string{"UID", "PID", "PPID", "C", "STIME", "TTY", "TIME", "CMD"},
+				Processes: [][]string{{"101", "4243", "4242", "0", "10:00", "?", "00:00:00", "nginx: worker process"}},
+			},
+			want: []Process{{PID: "4243", User: "101", CPU: 0, Memory: -1, Command: "nginx: worker process"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseTop(tt.top))
+		})
+	}
+}
+
+// useProcRoot points the host /proc lookups at root for the test.
+func useProcRoot(t *testing.T, root string) {
+	t.Helper()
+	orig := procRoot
+	procRoot = root
+	t.Cleanup(func() { procRoot = orig })
+}
+
+// writeProcStatus adds a /proc/<pid>/status fixture mapping hostPID to nsPID.
+func writeProcStatus(t *testing.T, root, hostPID, nsPID string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, hostPID), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, hostPID, "status"),
+		[]byte("Name:\tnginx\nPid:\t"+hostPID+"\nNSpid:\t"+hostPID+"\t"+nsPID+"\n"), 0o644))
+}
+
+func TestGetContainerProcesses_fallsBackToDefaultArgs(t *testing.T) {
+	mockClient := clientmock.NewMockAPIClient(t)
+	mockClient.EXPECT().
+		ContainerTop(mock.Anything, "abc123", []string{"aux"}).
+		Return(container.TopResponse{}, errors.New("ps: unrecognized option"))
+	mockClient.EXPECT().
+		ContainerTop(mock.Anything, "abc123", []string(nil)).
+		Return(container.TopResponse{
+			Titles:    []string{"PID", "USER", "TIME", "COMMAND"},
+			Processes: [][]string{{"1", "root", "0:00", "sleep 1000"}},
+		}, nil)
+
+	setContainerServiceForTest(service.NewContainerService(mockClient))
+
+	procs, err := GetContainerProcesses("abc123")
+
+	require.NoError(t, err)
+	assert.Equal(t, []Process{{PID: "1", User: "root", CPU: -1, Memory: -1, Command: "sleep 1000"}}, procs)
+}
+
+func TestNamespacedPID(t *testing.T) {
+	root := t.TempDir()
+	writeProcStatus(t, root, "4242", "1")
+	useProcRoot(t, root)
+
+	pid, err := namespacedPID("4242")
+	require.NoError(t, err)
+	assert.Equal(t, "1", pid)
+
+	_, err = namespacedPID("999")
+	assert.ErrorContains(t, err, "failed to find PID 999", "an unreachable /proc is an error, not the host PID")
+}
+
+func TestCanSignalProcess(t *testing.T) {
+	root := t.TempDir()
+	writeProcStatus(t, root, "4242", "1")
+	useProcRoot(t, root)
+
+	assert.True(t, CanSignalProcess("4242"))
+	assert.False(t, CanSignalProcess("999"))
+}
+
+func TestSignalProcess_refusesWithoutHostProc(t *testing.T) {
+	useProcRoot(t, t.TempDir())
+	setContainerServiceForTest(service.NewContainerService(clientmock.NewMockAPIClient(t)))
+
+	err := SignalProcess("abc123", "4242", "KILL")
+
+	assert.ErrorContains(t, err, "failed to find PID 4242")
+}
+
+func TestSignalProcess_runsKillThroughExec(t *testing.T) {
+	root := t.TempDir()
+	writeProcStatus(t, root, "999999999", "7")
+	useProcRoot(t, root)
+	mockClient := clientmock.NewMockAPIClient(t)
+	mockClient.EXPECT().
+		ContainerExecCreate(mock.Anything, "abc123", mock.MatchedBy(func(o container.ExecOptions) bool {
+			return assert.ObjectsAreEqual([]string{"kill", "-s", "TERM", "7"}, o.Cmd)
+		})).
+		Return(container.ExecCreateResponse{ID: "exec1"}, nil)
+	mockClient.EXPECT().
+		ContainerExecAttach(mock.Anything, "exec1", container.ExecAttachOptions{}).
+		Return(hijackedStreams(t, "", "kill: no such process\n"), nil)
+	mockClient.EXPECT().
+		ContainerExecInspect(mock.Anything, "exec1").
+		Return(container.ExecInspect{ExitCode: 1}, nil)
+
+	setContainerServiceForTest(service.NewContainerService(mockClient))
+
+	err := SignalProcess("abc123", "999999999", "TERM")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no such process")
+}
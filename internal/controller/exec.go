@@ -0,0 +1,246 @@
+package controller
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/muesli/cancelreader"
+)
+
+// ExecConfig describes a command to run inside a container.
+type ExecConfig struct {
+	Cmd        []string
+	User       string
+	WorkingDir string
+	Env        []string
+	TTY        bool
+}
+
+// detectShellScript prints the path of the first interactive shell found.
+const detectShellScript = `for s in bash zsh ash sh; do command -v "$s" && exit 0; done; exit 1`
+
+// DetectShell returns the best interactive shell available in the container
+// (bash, zsh, ash, then sh). It returns an empty string when none is found,
+// e.g. for distroless images without /bin/sh.
+func DetectShell(idOrName string) string {
+	var out bytes.Buffer
+	code, err := runExec(context.Background(), idOrName, ExecConfig{Cmd: []string{"/bin/sh", "-c", detectShellScript}}, nil, &out, io.Discard)
+	if err != nil || code != 0 {
+		return ""
+	}
+	return strings.TrimSpace(strings.SplitN(out.String(), "\n", 2)[0])
+}
+
+// ExecLine is one line of output from a non-interactive exec.
+type ExecLine struct {
+	Line   string
+	Stderr bool
+}
+
+// StreamExec runs cfg without a TTY and sends each stdout/stderr line to ch,
+// closing ch once the command's output ends. It returns the exit code.
+func StreamExec(ctx context.Context, idOrName string, cfg ExecConfig, ch chan<- ExecLine) (int, error) {
+	defer close(ch)
+
+	cfg.TTY = false
+	stdoutR, stdoutW := io.Pipe()
+	stderrR, stderrW := io.Pipe()
+
+	var wg sync.WaitGroup
+	forward := func(r io.Reader, stderr bool) {
+		defer wg.Done()
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			select {
+			case <-ctx.Done():
+			case ch <- ExecLine{Line: scanner.Text(), Stderr: stderr}:
+			}
+		}
+		// Drain the rest so the exec output copy never blocks on a full pipe.
+		_, _ = io.Copy(io.Discard, r)
+	}
+	wg.Add(2)
+	go forward(stdoutR, false)
+	go forward(stderrR, true)
+
+	code, err := runExec(ctx, idOrName, cfg, nil, stdoutW, stderrW)
+	_ = stdoutW.Close()
+	_ = stderrW.Close()
+	wg.Wait()
+	return code, err
+}
+
+// runExec runs cfg inside the container, attaching stdin when given and
+// copying output to stdout/stderr (a single stream when cfg.TTY is set). It
+// blocks until the output stream ends and returns the command's exit code.
+func runExec(ctx context.Context, idOrName string, cfg ExecConfig, stdin io.Reader, stdout, stderr io.Writer) (int, error) {
+	created, err := containerService.ExecCreate(ctx, idOrName, container.ExecOptions{
+		User:         cfg.User,
+		Tty:          cfg.TTY,
+		AttachStdin:  stdin != nil,
+		AttachStdout: true,
+		AttachStderr: true,
+		Env:          cfg.Env,
+		WorkingDir:   cfg.WorkingDir,
+		Cmd:          cfg.Cmd,
+	})
+	if err != nil {
+		return -1, err
+	}
+
+	resp, err := containerService.ExecAttach(ctx, created.ID, container.ExecAttachOptions{Tty: cfg.TTY})
+	if err != nil {
+		return -1, err
+	}
+	defer resp.Close()
+
+	// Closing the hijacked connection is the only way to interrupt a read
+	// that is blocked on a long-running command.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			resp.Close()
+		case <-done:
+		}
+	}()
+
+	if cfg.TTY {
+		resizeCtx, stopResize := context.WithCancel(ctx)
+		defer stopResize()
+		resizeExec(resizeCtx, created.ID, stdout)
+	}
+
+	if stdin != nil {
+		go func() {
+			// Input ends when stdin is exhausted or cancelled; the exec sees EOF either way.
+			_, _ = io.Copy(resp.Conn, stdin)
+			_ = resp.CloseWrite()
+		}()
+	}
+
+	if cfg.TTY {
+		_, err = io.Copy(stdout, resp.Reader)
+	} else {
+		_, err = stdcopy.StdCopy(stdout, stderr, resp.Reader)
+	}
+	if ctx.Err() != nil {
+		return -1, ctx.Err()
+	}
+	if err != nil {
+		return -1, fmt.Errorf("failed to read exec output: %w", err)
+	}
+
+	inspect, err := containerService.ExecInspect(context.Background(), created.ID)
+	if err != nil {
+		return -1, fmt.Errorf("failed to inspect exec %s: %w", created.ID, err)
+	}
+	return inspect.ExitCode, nil
+}
+
+// ExecSession is an interactive exec attached through the engine API, so it
+// works when only the engine socket (no CLI binary) is available. It satisfies
+// Bubble Tea's ExecCommand interface.
+type ExecSession struct {
+	containerID string
+	config      ExecConfig
+	stdin       io.Reader
+	stdout      io.Writer
+	stderr      io.Writer
+	exitCode    int
+}
+
+// NewExecSession creates an interactive session running cfg in the container.
+func NewExecSession(containerID string, cfg ExecConfig) *ExecSession {
+	return &ExecSession{
+		containerID: containerID,
+		config:      cfg,
+		stdin:       os.Stdin,
+		stdout:      os.Stdout,
+		stderr:      os.Stderr,
+	}
+}
+
+// SetStdin sets the session's input.
+func (s *ExecSession) SetStdin(r io.Reader) { s.stdin = r }
+
+// SetStdout sets the session's output.
+func (s *ExecSession) SetStdout(w io.Writer) { s.stdout = w }
+
+// SetStderr sets the session's error output.
+func (s *ExecSession) SetStderr(w io.Writer) { s.stderr = w }
+
+// ExitCode returns the exit code of the command once Run has returned.
+func (s *ExecSession) ExitCode() int { return s.exitCode }
+
+// Run attaches the terminal to the exec until the command exits. The
+// command's exit code is not an error: a shell exits with the status of its
+// last command. Read it with ExitCode.
+func (s *ExecSession) Run() error {
+	ctx := context.Background()
+
+	if f, ok := s.stdin.(*os.File); ok && s.config.TTY && term.IsTerminal(f.Fd()) {
+		state, err := term.MakeRaw(f.Fd())
+		if err != nil {
+			return fmt.Errorf("failed to set raw terminal mode: %w", err)
+		}
+		defer func() {
+			// Best effort: Bubble Tea restores its own terminal state once Run returns.
+			_ = term.Restore(f.Fd(), state)
+		}()
+	}
+
+	// A cancellable reader lets us stop reading stdin once the exec ends, so
+	// no keypress is swallowed after control returns to the TUI.
+	in, err := cancelreader.NewReader(s.stdin)
+	if err != nil {
+		return fmt.Errorf("failed to read terminal input: %w", err)
+	}
+	defer func() {
+		in.Cancel()
+		_ = in.Close()
+	}()
+
+	code, err := runExec(ctx, s.containerID, s.config, in, s.stdout, s.stderr)
+	if err != nil {
+		return err
+	}
+	s.exitCode = code
+	return nil
+}
+
+// resizeExec matches the exec TTY to the size of out when it is a terminal,
+// and keeps matching it as the terminal is resized until ctx is done.
+func resizeExec(ctx context.Context, execID string, out io.Writer) {
+	f, ok := out.(*os.File)
+	if !ok {
+		return
+	}
+	var width, height int
+	resize := func() {
+		w, h, err := term.GetSize(f.Fd())
+		if err != nil || w <= 0 || h <= 0 || (w == width && h == height) {
+			return
+		}
+		width, height = w, h
+		// A failed resize only affects line wrapping inside the session.
+		_ = containerService.ExecResize(ctx, execID, container.ResizeOptions{Height: uint(h), Width: uint(w)})
+	}
+	resize()
+	go func() {
+		for range terminalResizes(ctx) {
+			resize()
+		}
+	}()
+}
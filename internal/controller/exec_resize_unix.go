@@ -0,0 +1,34 @@
+//go:build !windows
+
+package controller
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// terminalResizes signals each SIGWINCH until ctx is done, then closes.
+func terminalResizes(ctx context.Context) <-chan struct{} {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGWINCH)
+	out := make(chan struct{})
+	go func() {
+		defer close(out)
+		defer signal.Stop(sigs)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sigs:
+				select {
+				case out <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out
+}
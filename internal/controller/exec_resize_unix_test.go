@@ -0,0 +1,28 @@
+//go:build !windows
+
+package controller
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTerminalResizes_signalsEachSIGWINCH(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	resizes := terminalResizes(ctx)
+
+	require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGWINCH))
+	select {
+	case <-resizes:
+	case <-time.After(time.Second):
+		t.Fatal("no resize after SIGWINCH")
+	}
+
+	cancel()
+	_, open := <-resizes
+	require.False(t, open, "the channel closes once ctx is done")
+}
@@ -0,0 +1,36 @@
+//go:build windows
+
+package controller
+
+import (
+	"context"
+	"time"
+)
+
+// resizePollInterval is how often the console size is checked; Windows has
+// no signal for console resizes.
+const resizePollInterval = 250 * time.Millisecond
+
+// terminalResizes ticks every resizePollInterval until ctx is done, then
+// closes. The caller ignores ticks where the size did not change.
+func terminalResizes(ctx context.Context) <-chan struct{} {
+	out := make(chan struct{})
+	go func() {
+		defer close(out)
+		ticker := time.NewTicker(resizePollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				select {
+				case out <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,167 @@
+package controller
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/rluders/berth/internal/service"
+	clientmock "github.com/rluders/berth/mocks/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// hijackedOutput builds an exec attach response whose stdout carries out.
+func hijackedOutput(t *testing.T, out string) types.HijackedResponse {
+	t.Helper()
+	var buf bytes.Buffer
+	if _, err := stdcopy.NewStdWriter(&buf, stdcopy.Stdout).Write([]byte(out)); err != nil {
+		t.Fatal(err)
+	}
+	conn, peer := net.Pipe()
+	t.Cleanup(func() { _ = peer.Close() })
+	return types.HijackedResponse{Conn: conn, Reader: bufio.NewReader(&buf)}
+}
+
+func TestDetectShell_returnsFirstShellFound(t *testing.T) {
+	mockClient := clientmock.NewMockAPIClient(t)
+	mockClient.EXPECT().
+		ContainerExecCreate(mock.Anything, "abc123", mock.MatchedBy(func(o container.ExecOptions) bool {
+			return len(o.Cmd) == 3 && o.Cmd[0] == "/bin/sh" && !o.Tty && !o.AttachStdin
+		})).
+		Return(container.ExecCreateResponse{ID: "exec1"}, nil)
+	mockClient.EXPECT().
+		ContainerExecAttach(mock.Anything, "exec1", container.ExecAttachOptions{}).
+		Return(hijackedOutput(t, "/bin/bash\n"), nil)
+	mockClient.EXPECT().
+		ContainerExecInspect(mock.Anything, "exec1").
+		Return(container.ExecInspect{ExitCode: 0}, nil)
+
+	setContainerServiceForTest(service.NewContainerService(mockClient))
+
+	assert.Equal(t, "/bin/bash", DetectShell("abc123"))
+}
+
+func TestDetectShell_emptyWhenExecFails(t *testing.T) {
+	mockClient := clientmock.NewMockAPIClient(t)
+	mockClient.EXPECT().
+		ContainerExecCreate(mock.Anything, "abc123", mock.Anything).
+		Return(container.ExecCreateResponse{}, errors.New("no such file: /bin/sh"))
+
+	setContainerServiceForTest(service.NewContainerService(mockClient))
+
+	assert.Empty(t, DetectShell("abc123"))
+}
+
+func TestDetectShell_emptyOnNonZeroExit(t *testing.T) {
+	mockClient := clientmock.NewMockAPIClient(t)
+	mockClient.EXPECT().
+		ContainerExecCreate(mock.Anything, "abc123", mock.Anything).
+		Return(container.ExecCreateResponse{ID: "exec1"}, nil)
+	mockClient.EXPECT().
+		ContainerExecAttach(mock.Anything, "exec1", container.ExecAttachOptions{}).
+		Return(hijackedOutput(t, ""), nil)
+	mockClient.EXPECT().
+		ContainerExecInspect(mock.Anything, "exec1").
+		Return(container.ExecInspect{ExitCode: 1}, nil)
+
+	setContainerServiceForTest(service.NewContainerService(mockClient))
+
+	assert.Empty(t, DetectShell("abc123"))
+}
+
+// hijackedStreams builds an exec attach response carrying separate stdout and stderr.
+func hijackedStreams(t *testing.T, stdout, stderr string) types.HijackedResponse {
+	t.Helper()
+	var buf bytes.Buffer
+	if _, err := stdcopy.NewStdWriter(&buf, stdcopy.Stdout).Write([]byte(stdout)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stdcopy.NewStdWriter(&buf, stdcopy.Stderr).Write([]byte(stderr)); err != nil {
+		t.Fatal(err)
+	}
+	conn, peer := net.Pipe()
+	t.Cleanup(func() { _ = peer.Close() })
+	return types.HijackedResponse{Conn: conn, Reader: bufio.NewReader(&buf)}
+}
+
+func TestExecSession_exitCodeIsNotAnError(t *testing.T) {
+	mockClient := clientmock.NewMockAPIClient(t)
+	mockClient.EXPECT().
+		ContainerExecCreate(mock.Anything, "abc123", mock.Anything).
+		Return(container.ExecCreateResponse{ID: "exec1"}, nil)
+	mockClient.EXPECT().
+		ContainerExecAttach(mock.Anything, "exec1", container.ExecAttachOptions{}).
+		Return(hijackedOutput(t, "exit\n"), nil)
+	mockClient.EXPECT().
+		ContainerExecInspect(mock.Anything, "exec1").
+		Return(container.ExecInspect{ExitCode: 130}, nil)
+
+	setContainerServiceForTest(service.NewContainerService(mockClient))
+
+	session := NewExecSession("abc123", ExecConfig{Cmd: []string{"sh"}})
+	session.SetStdin(bytes.NewReader(nil))
+	session.SetStdout(io.Discard)
+
+	require.NoError(t, session.Run())
+	assert.Equal(t, 130, session.ExitCode())
+}
+
+func TestStreamExec_streamsLinesAndExitCode(t *testing.T) {
+	mockClient := clientmock.NewMockAPIClient(t)
+	mockClient.EXPECT().
+		ContainerExecCreate(mock.Anything, "abc123", mock.MatchedBy(func(o container.ExecOptions) bool {
+			return len(o.Cmd) == 2 && o.Cmd[0] == "cat" && !o.Tty && !o.AttachStdin
+		})).
+		Return(container.ExecCreateResponse{ID: "exec1"}, nil)
+	mockClient.EXPECT().
+		ContainerExecAttach(mock.Anything, "exec1", container.ExecAttachOptions{}).
+		Return(hijackedStreams(t, "127.0.0.1 localhost\n::1 localhost\n", "cat: warning\n"), nil)
+	mockClient.EXPECT().
+		ContainerExecInspect(mock.Anything, "exec1").
+		Return(container.ExecInspect{ExitCode: 2}, nil)
+
+	setContainerServiceForTest(service.NewContainerService(mockClient))
+
+	ch := make(chan ExecLine, 10)
+	code, err := StreamExec(context.Background(), "abc123", ExecConfig{Cmd: []string{"cat", "/etc/hosts"}, TTY: true}, ch)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, code)
+
+	var stdout, stderr []string
+	for l := range ch {
+		if l.Stderr {
+			stderr = append(stderr, l.Line)
+		} else {
+			stdout = append(stdout, l.Line)
+		}
+	}
+	assert.Equal(t, []string{"127.0.0.1 localhost", "::1 localhost"}, stdout)
+	assert.Equal(t, []string{"cat: warning"}, stderr)
+}
+
+func TestStreamExec_closesChannelOnError(t *testing.T) {
+	mockClient := clientmock.NewMockAPIClient(t)
+	mockClient.EXPECT().
+		ContainerExecCreate(mock.Anything, "abc123", mock.Anything).
+		Return(container.ExecCreateResponse{}, errors.New("container is not running"))
+
+	setContainerServiceForTest(service.NewContainerService(mockClient))
+
+	ch := make(chan ExecLine, 10)
+	code, err := StreamExec(context.Background(), "abc123", ExecConfig{Cmd: []string{"env"}}, ch)
+
+	assert.Error(t, err)
+	assert.Equal(t, -1, code)
+	_, open := <-ch
+	assert.False(t, open)
+}
@@ -66,3 +66,27 @@ func TestFormatCreated(t *testing.T) {
 		assert.Equal(t, fmt.Sprintf("%dd ago", 48/24), got)
 	})
 }
+
+func TestFormatTimestamp(t *testing.T) {
+	assert.Empty(t, formatTimestamp("0001-01-01T00:00:00Z"), "zero time means never")
+	assert.Empty(t, formatTimestamp(""))
+
+	ts := time.Now().Add(-5 * time.Minute)
+	assert.Equal(t, ts.Local().Format("2006-01-02 15:04:05")+" (5m ago)", formatTimestamp(ts.Format(time.RFC3339Nano)))
+}
+
+func TestFormatRestartPolicy(t *testing.T) {
+	tests := []struct {
+		policy container.RestartPolicy
+		want   string
+	}{
+		{container.RestartPolicy{}, "no"},
+		{container.RestartPolicy{Name: container.RestartPolicyAlways}, "always"},
+		{container.RestartPolicy{Name: container.RestartPolicyOnFailure, MaximumRetryCount: 5}, "on-failure:5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, formatRestartPolicy(tt.policy))
+		})
+	}
+}
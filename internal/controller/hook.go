@@ -0,0 +1,24 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// RunHook runs a user-defined shell command with sh -c, adding env to the
+// current environment. Output is discarded; a failure includes whatever the
+// command printed.
+func RunHook(ctx context.Context, command string, env []string) error {
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Env = append(os.Environ(), env...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("hook %q failed: %w: %s", command, err, msg)
+		}
+		return fmt.Errorf("hook %q failed: %w", command, err)
+	}
+	return nil
+}
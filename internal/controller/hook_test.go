@@ -0,0 +1,19 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunHook_passesEnvironment(t *testing.T) {
+	err := RunHook(context.Background(), `test "$BERTH_ALERT" = unhealthy`, []string{"BERTH_ALERT=unhealthy"})
+	assert.NoError(t, err)
+}
+
+func TestRunHook_reportsOutputOnFailure(t *testing.T) {
+	err := RunHook(context.Background(), "echo boom >&2; exit 3", nil)
+	assert.ErrorContains(t, err, "exit status 3")
+	assert.ErrorContains(t, err, "boom")
+}
@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	timetypes "github.com/docker/docker/api/types/time"
+)
+
+// DefaultLogTail is the number of lines read when a log view opens.
+const DefaultLogTail = "200"
+
+// LogOptions selects which part of a container's log is read.
+type LogOptions struct {
+	Tail       string // lines from the end of the log, or "all"
+	Since      string // relative ("15m") or absolute (RFC 3339, Unix) lower bound
+	Until      string // upper bound, same formats as Since
+	Timestamps bool   // prefix each line with the engine's timestamp
+}
+
+// DefaultLogOptions returns the options used when a log view opens.
+func DefaultLogOptions() LogOptions {
+	return LogOptions{Tail: DefaultLogTail}
+}
+
+// Validate reports a tail size or time bound the engine would reject.
+func (o LogOptions) Validate() error {
+	if o.Tail != "" && o.Tail != "all" {
+		if n, err := strconv.Atoi(o.Tail); err != nil || n < 0 {
+			return fmt.Errorf("invalid tail %q: want a line count or \"all\"", o.Tail)
+		}
+	}
+	now := time.Now()
+	for _, bound := range []struct{ name, value string }{{"since", o.Since}, {"until", o.Until}} {
+		if bound.value == "" {
+			continue
+		}
+		if _, err := timetypes.GetTimestamp(bound.value, now); err != nil {
+			return fmt.Errorf("invalid %s %q: %w", bound.name, bound.value, err)
+		}
+	}
+	return nil
+}
+
+// Summary describes the options in a few words, e.g. "tail 200 · since 15m".
+func (o LogOptions) Summary() string {
+	var parts []string
+	if o.Tail != "" {
+		parts = append(parts, "tail "+o.Tail)
+	}
+	if o.Since != "" {
+		parts = append(parts, "since "+o.Since)
+	}
+	if o.Until != "" {
+		parts = append(parts, "until "+o.Until)
+	}
+	if o.Timestamps {
+		parts = append(parts, "timestamps")
+	}
+	return strings.Join(parts, " · ")
+}
+
+// engineOptions converts the options to an engine logs request.
+func (o LogOptions) engineOptions(follow bool) container.LogsOptions {
+	return container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     follow,
+		Tail:       o.Tail,
+		Since:      o.Since,
+		Until:      o.Until,
+		Timestamps: o.Timestamps,
+	}
+}
@@ -0,0 +1,205 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/rluders/berth/internal/service"
+	clientmock "github.com/rluders/berth/mocks/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    LogOptions
+		wantErr bool
+	}{
+		{"defaults", DefaultLogOptions(), false},
+		{"all lines", LogOptions{Tail: "all"}, false},
+		{"relative window", LogOptions{Since: "15m", Until: "5m"}, false},
+		{"absolute window", LogOptions{Since: "2024-05-01T10:00:00Z", Until: "2024-05-01T10:30:00Z"}, false},
+		{"unix timestamp", LogOptions{Since: "1714557600"}, false},
+		{"bad tail", LogOptions{Tail: "lots"}, true},
+		{"negative tail", LogOptions{Tail: "-5"}, true},
+		{"bad since", LogOptions{Since: "yesterday"}, true},
+		{"bad until", LogOptions{Until: "15 minutes"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.Validate()
+			assert.Equal(t, tt.wantErr, err != nil, "Validate() error = %v", err)
+		})
+	}
+}
+
+func TestLogOptions_Summary(t *testing.T) {
+	assert.Equal(t, "tail 200", DefaultLogOptions().Summary())
+	assert.Equal(t, "tail all · since 15m · until 5m · timestamps",
+		LogOptions{Tail: "all", Since: "15m", Until: "5m", Timestamps: true}.Summary())
+}
+
+func TestStreamContainerLogs_passesOptions(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       LogOptions
+		wantFollow bool
+	}{
+		{"open window follows", LogOptions{Tail: "50", Since: "15m", Timestamps: true}, true},
+		{"closed window stops", LogOptions{Tail: "all", Since: "30m", Until: "10m"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := clientmock.NewMockAPIClient(t)
+			mockClient.EXPECT().
+				ContainerLogs(mock.Anything, "abc123", mock.MatchedBy(func(o container.LogsOptions) bool {
+					return o.ShowStdout && o.ShowStderr &&
+						o.Follow == tt.wantFollow &&
+						o.Tail == tt.opts.Tail &&
+						o.Since == tt.opts.Since &&
+						o.Until == tt.opts.Until &&
+						o.Timestamps == tt.opts.Timestamps
+				})).
+				Return(io.NopCloser(strings.NewReader("")), nil)
+			setContainerServiceForTest(service.NewContainerService(mockClient))
+
+			ch := make(chan LogEntry, 10)
+			StreamContainerLogs(context.Background(), "abc123", tt.opts, ch)
+			for range ch {
+			}
+		})
+	}
+}
+
+func TestStreamContainerLogs_separatesStreams(t *testing.T) {
+	mockClient := clientmock.NewMockAPIClient(t)
+	mockClient.EXPECT().ContainerLogs(mock.Anything, "abc123", mock.Anything).
+		Return(multiplexed(t, []string{"listening on :8080"}, []string{"warning: low memory"}), nil)
+	setContainerServiceForTest(service.NewContainerService(mockClient))
+
+	ch := make(chan LogEntry, 10)
+	StreamContainerLogs(context.Background(), "abc123", DefaultLogOptions(), ch)
+
+	var entries []LogEntry
+	for e := range ch {
+		entries = append(entries, e)
+	}
+	assert.ElementsMatch(t, []LogEntry{
+		{Stream: StreamStdout, Line: "listening on :8080"},
+		{Stream: StreamStderr, Line: "warning: low memory"},
+	}, entries)
+}
+
+func TestStreamContainerLogs_keepsStreamOrder(t *testing.T) {
+	var buf bytes.Buffer
+	frames := []struct {
+		stream stdcopy.StdType
+		data   string
+	}{
+		{stdcopy.Stdout, "starting\n"},
+		{stdcopy.Stderr, "warning: no "},
+		{stdcopy.Stdout, "config loaded\nlisten"},
+		{stdcopy.Stderr, "cache\n"},
+		{stdcopy.Stdout, "ing on :8080\n"},
+		{stdcopy.Stderr, "exiting"},
+	}
+	for _, f := range frames {
+		_, err := stdcopy.NewStdWriter(&buf, f.stream).Write([]byte(f.data))
+		require.NoError(t, err)
+	}
+	mockClient := clientmock.NewMockAPIClient(t)
+	mockClient.EXPECT().ContainerLogs(mock.Anything, "abc123", mock.Anything).
+		Return(io.NopCloser(&buf), nil)
+	setContainerServiceForTest(service.NewContainerService(mockClient))
+
+	ch := make(chan LogEntry, 10)
+	StreamContainerLogs(context.Background(), "abc123", DefaultLogOptions(), ch)
+
+	var entries []LogEntry
+	for e := range ch {
+		entries = append(entries, e)
+	}
+	assert.Equal(t, []LogEntry{
+		{Stream: StreamStdout, Line: "starting"},
+		{Stream: StreamStdout, Line: "config loaded"},
+		{Stream: StreamStderr, Line: "warning: no cache"},
+		{Stream: StreamStdout, Line: "listening on :8080"},
+		{Stream: StreamStderr, Line: "exiting"},
+	}, entries)
+}
+
+func TestStreamContainerLogs_ttyOutputIsStdout(t *testing.T) {
+	mockClient := clientmock.NewMockAPIClient(t)
+	mockClient.EXPECT().ContainerLogs(mock.Anything, "abc123", mock.Anything).
+		Return(io.NopCloser(strings.NewReader("$ ls\r\nbin  etc\r\n")), nil)
+	setContainerServiceForTest(service.NewContainerService(mockClient))
+
+	ch := make(chan LogEntry, 10)
+	StreamContainerLogs(context.Background(), "abc123", DefaultLogOptions(), ch)
+
+	var entries []LogEntry
+	for e := range ch {
+		entries = append(entries, e)
+	}
+	assert.Equal(t, []LogEntry{
+		{Stream: StreamStdout, Line: "$ ls"},
+		{Stream: StreamStdout, Line: "bin  etc"},
+	}, entries)
+}
+
+func TestStreamMultiContainerLogs_tagsAndStripsTimestamps(t *testing.T) {
+	mockClient := clientmock.NewMockAPIClient(t)
+	timestamped := mock.MatchedBy(func(o container.LogsOptions) bool { return o.Timestamps })
+	mockClient.EXPECT().ContainerLogs(mock.Anything, "web1", timestamped).
+		Return(multiplexed(t, []string{"2024-05-01T10:00:02Z GET /"}, nil), nil)
+	mockClient.EXPECT().ContainerLogs(mock.Anything, "db1", timestamped).
+		Return(multiplexed(t, nil, []string{"2024-05-01T10:00:01Z slow query"}), nil)
+	// Both containers are gone once their streams end, so neither reattaches.
+	mockClient.EXPECT().ContainerInspect(mock.Anything, mock.Anything).
+		Return(container.InspectResponse{}, errors.New("no such container"))
+	setReattachIntervalForTest(t, time.Millisecond)
+	setContainerServiceForTest(service.NewContainerService(mockClient))
+
+	ch := make(chan LogEntry, 10)
+	StreamMultiContainerLogs(context.Background(), []Container{
+		{ID: "web1", Names: "web"},
+		{ID: "db1", Names: "db"},
+	}, DefaultLogOptions(), ch)
+
+	var entries []LogEntry
+	for e := range ch {
+		entries = append(entries, e)
+	}
+	assert.ElementsMatch(t, []LogEntry{
+		{ContainerName: "web", Stream: StreamStdout, Line: "GET /", Time: time.Date(2024, 5, 1, 10, 0, 2, 0, time.UTC)},
+		{ContainerName: "db", Stream: StreamStderr, Line: "slow query", Time: time.Date(2024, 5, 1, 10, 0, 1, 0, time.UTC)},
+	}, entries)
+}
+
+func TestMergeLogEntries_ordersByTime(t *testing.T) {
+	at := func(sec int) time.Time { return time.Date(2024, 5, 1, 10, 0, sec, 0, time.UTC) }
+	in := make(chan LogEntry, 3)
+	in <- LogEntry{ContainerName: "web", Line: "second", Time: at(2)}
+	in <- LogEntry{ContainerName: "db", Line: "first", Time: at(1)}
+	in <- LogEntry{ContainerName: "db", Line: "third", Time: at(3)}
+	close(in)
+
+	out := make(chan LogEntry, 3)
+	mergeLogEntries(context.Background(), in, out, time.Hour)
+	close(out)
+
+	var lines []string
+	for e := range out {
+		lines = append(lines, e.Line)
+	}
+	assert.Equal(t, []string{"first", "second", "third"}, lines)
+}
@@ -0,0 +1,189 @@
+package controller
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// Log export formats.
+const (
+	LogFormatText  = "text"
+	LogFormatJSONL = "jsonl"
+)
+
+// LogRecord is one log line with its origin, as written by SaveLogRecords.
+type LogRecord struct {
+	Container string    `json:"container"`
+	Stream    string    `json:"stream,omitempty"` // "stdout" or "stderr"; empty when unknown
+	Time      time.Time `json:"timestamp,omitzero"`
+	Line      string    `json:"line"`
+}
+
+// SplitLogTimestamp separates the RFC 3339 timestamp the engine prefixes to
+// each line when timestamps are requested. ok is false when line has none.
+func SplitLogTimestamp(line string) (ts time.Time, rest string, ok bool) {
+	prefix, rest, found := strings.Cut(line, " ")
+	if !found {
+		prefix, rest = line, ""
+	}
+	ts, err := time.Parse(time.RFC3339Nano, prefix)
+	if err != nil {
+		return time.Time{}, line, false
+	}
+	return ts, rest, true
+}
+
+// FetchLogRecords reads the logs of each container within the range of opts,
+// without following, and returns them ordered by time. Timestamps are always
+// requested so records from several containers interleave correctly.
+func FetchLogRecords(ctx context.Context, containers []Container, opts LogOptions) ([]LogRecord, error) {
+	opts.Timestamps = true
+	var records []LogRecord
+	for _, c := range containers {
+		recs, err := fetchContainerLogRecords(ctx, c, opts)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, recs...)
+	}
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Time.Before(records[j].Time) })
+	return records, nil
+}
+
+func fetchContainerLogRecords(ctx context.Context, c Container, opts LogOptions) ([]LogRecord, error) {
+	out, err := containerService.ContainerLogs(ctx, c.ID, opts.engineOptions(false))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get logs for container %s: %w", c.Names, err)
+	}
+	defer func() {
+		// The body has been read in full; a close failure loses nothing.
+		_ = out.Close()
+	}()
+
+	raw, err := io.ReadAll(out)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read logs for container %s: %w", c.Names, err)
+	}
+
+	br := bufio.NewReader(bytes.NewReader(raw))
+	if !multiplexedLogs(br) {
+		// TTY container — stdout and stderr are merged.
+		return splitLogRecords(c.Names, "", raw), nil
+	}
+	var stdout, stderr bytes.Buffer
+	if _, err := stdcopy.StdCopy(&stdout, &stderr, br); err != nil {
+		return nil, fmt.Errorf("failed to read logs for container %s: %w", c.Names, err)
+	}
+	records := splitLogRecords(c.Names, StreamStdout, stdout.Bytes())
+	records = append(records, splitLogRecords(c.Names, StreamStderr, stderr.Bytes())...)
+	return records, nil
+}
+
+// splitLogRecords turns timestamped log output into records.
+func splitLogRecords(name, stream string, data []byte) []LogRecord {
+	var records []LogRecord
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		ts, line, _ := SplitLogTimestamp(scanner.Text())
+		records = append(records, LogRecord{Container: name, Stream: stream, Time: ts, Line: line})
+	}
+	return records
+}
+
+// WriteLogRecords writes records in the given format. Text lines carry the
+// timestamp when known and the container name when records come from more
+// than one container, like the logs view.
+func WriteLogRecords(w io.Writer, records []LogRecord, format string) error {
+	bw := bufio.NewWriter(w)
+	switch format {
+	case LogFormatJSONL:
+		enc := json.NewEncoder(bw)
+		for _, r := range records {
+			if err := enc.Encode(r); err != nil {
+				return fmt.Errorf("failed to encode log record: %w", err)
+			}
+		}
+	case LogFormatText:
+		prefix := multipleContainers(records)
+		for _, r := range records {
+			var line strings.Builder
+			if prefix {
+				line.WriteString("[" + r.Container + "] ")
+			}
+			if !r.Time.IsZero() {
+				line.WriteString(r.Time.Format(time.RFC3339Nano) + " ")
+			}
+			line.WriteString(r.Line)
+			line.WriteByte('\n')
+			if _, err := bw.WriteString(line.String()); err != nil {
+				return fmt.Errorf("failed to write log line: %w", err)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown log format %q: want %s or %s", format, LogFormatText, LogFormatJSONL)
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write logs: %w", err)
+	}
+	return nil
+}
+
+func multipleContainers(records []LogRecord) bool {
+	for _, r := range records {
+		if r.Container != records[0].Container {
+			return true
+		}
+	}
+	return false
+}
+
+// SaveLogRecords writes records to a new file in dir named after name and
+// the current time, creating dir when needed. Returns the path written.
+func SaveLogRecords(dir, name string, records []LogRecord, format string) (path string, err error) {
+	ext := "log"
+	if format == LogFormatJSONL {
+		ext = "jsonl"
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	path = filepath.Join(dir, fmt.Sprintf("%s-%s.%s", logFileName(name), time.Now().Format("20060102-150405"), ext))
+
+	f, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("failed to close %s: %w", path, closeErr)
+		}
+	}()
+	if err := WriteLogRecords(f, records, format); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// logFileName makes a container or group name safe to use in a file name.
+func logFileName(name string) string {
+	name = strings.TrimPrefix(name, "/")
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		}
+		return '_'
+	}, name)
+}
@@ -0,0 +1,125 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/rluders/berth/internal/service"
+	clientmock "github.com/rluders/berth/mocks/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitLogTimestamp(t *testing.T) {
+	ts, rest, ok := SplitLogTimestamp("2024-05-01T10:00:00.123456789Z GET /health 200")
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2024, 5, 1, 10, 0, 0, 123456789, time.UTC), ts)
+	assert.Equal(t, "GET /health 200", rest)
+
+	_, rest, ok = SplitLogTimestamp("GET /health 200")
+	assert.False(t, ok)
+	assert.Equal(t, "GET /health 200", rest)
+}
+
+// multiplexed builds a non-TTY logs body with the given stdout and stderr lines.
+func multiplexed(t *testing.T, stdout, stderr []string) io.ReadCloser {
+	t.Helper()
+	var buf bytes.Buffer
+	for _, l := range stdout {
+		_, err := stdcopy.NewStdWriter(&buf, stdcopy.Stdout).Write([]byte(l + "\n"))
+		require.NoError(t, err)
+	}
+	for _, l := range stderr {
+		_, err := stdcopy.NewStdWriter(&buf, stdcopy.Stderr).Write([]byte(l + "\n"))
+		require.NoError(t, err)
+	}
+	return io.NopCloser(&buf)
+}
+
+func TestFetchLogRecords_mergesContainersByTime(t *testing.T) {
+	mockClient := clientmock.NewMockAPIClient(t)
+	timestamped := mock.MatchedBy(func(o container.LogsOptions) bool {
+		return o.Timestamps && !o.Follow && o.Since == "15m"
+	})
+	mockClient.EXPECT().ContainerLogs(mock.Anything, "web1", timestamped).Return(multiplexed(t,
+		[]string{"2024-05-01T10:00:01Z listening", "2024-05-01T10:00:03Z GET /"},
+		[]string{"2024-05-01T10:00:04Z panic: boom"},
+	), nil)
+	mockClient.EXPECT().ContainerLogs(mock.Anything, "db1", timestamped).Return(multiplexed(t,
+		[]string{"2024-05-01T10:00:02Z ready"}, nil,
+	), nil)
+	setContainerServiceForTest(service.NewContainerService(mockClient))
+
+	records, err := FetchLogRecords(context.Background(),
+		[]Container{{ID: "web1", Names: "web"}, {ID: "db1", Names: "db"}},
+		LogOptions{Tail: "all", Since: "15m"})
+
+	require.NoError(t, err)
+	require.Len(t, records, 4)
+	assert.Equal(t, LogRecord{Container: "web", Stream: "stdout", Time: time.Date(2024, 5, 1, 10, 0, 1, 0, time.UTC), Line: "listening"}, records[0])
+	assert.Equal(t, "db", records[1].Container)
+	assert.Equal(t, "GET /", records[2].Line)
+	assert.Equal(t, "stderr", records[3].Stream)
+}
+
+func TestFetchLogRecords_ttyContainer(t *testing.T) {
+	mockClient := clientmock.NewMockAPIClient(t)
+	mockClient.EXPECT().ContainerLogs(mock.Anything, "tty1", mock.Anything).
+		Return(io.NopCloser(strings.NewReader("2024-05-01T10:00:01Z $ ls\n")), nil)
+	setContainerServiceForTest(service.NewContainerService(mockClient))
+
+	records, err := FetchLogRecords(context.Background(), []Container{{ID: "tty1", Names: "shell"}}, DefaultLogOptions())
+
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Empty(t, records[0].Stream)
+	assert.Equal(t, "$ ls", records[0].Line)
+}
+
+func TestWriteLogRecords(t *testing.T) {
+	ts := time.Date(2024, 5, 1, 10, 0, 1, 0, time.UTC)
+	records := []LogRecord{
+		{Container: "web", Stream: "stdout", Time: ts, Line: "listening"},
+		{Container: "db", Line: "ready"},
+	}
+
+	var text bytes.Buffer
+	require.NoError(t, WriteLogRecords(&text, records, LogFormatText))
+	assert.Equal(t, "[web] 2024-05-01T10:00:01Z listening\n[db] ready\n", text.String())
+
+	var single bytes.Buffer
+	require.NoError(t, WriteLogRecords(&single, records[:1], LogFormatText))
+	assert.Equal(t, "2024-05-01T10:00:01Z listening\n", single.String())
+
+	var jsonl bytes.Buffer
+	require.NoError(t, WriteLogRecords(&jsonl, records, LogFormatJSONL))
+	assert.Equal(t,
+		`{"container":"web","stream":"stdout","timestamp":"2024-05-01T10:00:01Z","line":"listening"}`+"\n"+
+			`{"container":"db","line":"ready"}`+"\n",
+		jsonl.String())
+
+	assert.Error(t, WriteLogRecords(io.Discard, records, "xml"))
+}
+
+func TestSaveLogRecords(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "incident")
+
+	path, err := SaveLogRecords(dir, "/my app", []LogRecord{{Container: "my app", Line: "hello"}}, LogFormatJSONL)
+
+	require.NoError(t, err)
+	assert.Equal(t, dir, filepath.Dir(path))
+	assert.True(t, strings.HasPrefix(filepath.Base(path), "my_app-"))
+	assert.Equal(t, ".jsonl", filepath.Ext(path))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `{"container":"my app","line":"hello"}`+"\n", string(data))
+}
@@ -0,0 +1,180 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// logReattachInterval is how often a stopped container is checked for a
+// restart while its logs are followed, and logReattachTimeout how long it is
+// waited for.
+var (
+	logReattachInterval = time.Second
+	logReattachTimeout  = 5 * time.Minute
+)
+
+// FollowContainerLogs streams a container's logs into ch like
+// StreamContainerLogs. When a followed stream ends because the container
+// stopped, it waits for the container to run again — or, for a compose
+// service, for the container that replaced it — then sends a marker entry and
+// resumes from the restart. While waiting it sends a Waiting entry, and a
+// marker when it gives up. ch is closed when ctx is cancelled, when a bounded
+// stream (opts.Until) ends, or when the container is gone for good or did not
+// run again within logReattachTimeout.
+func FollowContainerLogs(ctx context.Context, idOrName string, opts LogOptions, ch chan<- LogEntry) {
+	defer close(ch)
+
+	id := idOrName
+	info, _ := containerService.ContainerInspect(ctx, id)
+	for {
+		entryCh := make(chan LogEntry, 100)
+		go StreamContainerLogs(ctx, id, opts, entryCh)
+		for entry := range entryCh {
+			select {
+			case <-ctx.Done():
+			case ch <- entry:
+			}
+		}
+		if opts.Until != "" || ctx.Err() != nil {
+			return
+		}
+
+		waiting := false
+		next, ok := waitForRestart(ctx, id, info, func() {
+			waiting = true
+			select {
+			case <-ctx.Done():
+			case ch <- LogEntry{Stream: StreamStdout, Waiting: true}:
+			}
+		})
+		if !ok {
+			if waiting {
+				select {
+				case <-ctx.Done():
+				case ch <- stoppedMarker():
+				}
+			}
+			return
+		}
+		started := containerStartedAt(next)
+		select {
+		case <-ctx.Done():
+			return
+		case ch <- restartMarker(started):
+		}
+		id, info = next.ID, next
+		opts.Tail = "all"
+		opts.Since = started.Format(time.RFC3339Nano)
+	}
+}
+
+// restartMarker is the entry inserted where a followed stream resumes.
+func restartMarker(started time.Time) LogEntry {
+	return LogEntry{
+		Stream: StreamStdout,
+		Line:   fmt.Sprintf("--- container restarted at %s ---", started.Local().Format(time.TimeOnly)),
+		Time:   started,
+		Marker: true,
+	}
+}
+
+// stoppedMarker is the entry sent when a followed stream is not resumed.
+func stoppedMarker() LogEntry {
+	return LogEntry{
+		Stream: StreamStdout,
+		Line:   "--- container did not restart, stopped following ---",
+		Time:   time.Now(),
+		Marker: true,
+	}
+}
+
+// waitForRestart polls until the container described by prev runs again with
+// a later start time, or a running container of the same compose service
+// replaces it. waiting is called once, when the container is first found
+// stopped or removed. It gives up when ctx is cancelled, after
+// logReattachTimeout, when a container that is still running ended its
+// stream for another reason, or when a container outside compose was
+// removed.
+func waitForRestart(ctx context.Context, id string, prev container.InspectResponse, waiting func()) (container.InspectResponse, bool) {
+	prevStart := containerStartedAt(prev)
+	ticker := time.NewTicker(logReattachInterval)
+	defer ticker.Stop()
+	timeout := time.NewTimer(logReattachTimeout)
+	defer timeout.Stop()
+	announced := false
+	announce := func() {
+		if !announced {
+			announced = true
+			waiting()
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return container.InspectResponse{}, false
+		case <-timeout.C:
+			return container.InspectResponse{}, false
+		case <-ticker.C:
+		}
+
+		info, err := containerService.ContainerInspect(ctx, id)
+		if err == nil {
+			if info.ContainerJSONBase == nil || info.State == nil || !info.State.Running {
+				announce()
+				continue
+			}
+			if containerStartedAt(info).After(prevStart) {
+				return info, true
+			}
+			// Still running from the same start: not a restart.
+			return container.InspectResponse{}, false
+		}
+
+		if prev.Config == nil || prev.Config.Labels[composeServiceLabel] == "" {
+			return container.InspectResponse{}, false
+		}
+		if next, ok := findComposeReplacement(ctx, prev.Config.Labels); ok {
+			return next, true
+		}
+		announce()
+	}
+}
+
+// findComposeReplacement looks for a running container of the same compose
+// project, service and container number.
+func findComposeReplacement(ctx context.Context, labels map[string]string) (container.InspectResponse, bool) {
+	summaries, err := containerService.ListContainers(ctx, container.ListOptions{})
+	if err != nil {
+		return container.InspectResponse{}, false
+	}
+	for _, s := range summaries {
+		if s.State != "running" ||
+			s.Labels[composeProjectLabel] != labels[composeProjectLabel] ||
+			s.Labels[composeServiceLabel] != labels[composeServiceLabel] ||
+			s.Labels[composeNumberLabel] != labels[composeNumberLabel] {
+			continue
+		}
+		info, err := containerService.ContainerInspect(ctx, s.ID)
+		if err == nil && info.ContainerJSONBase != nil {
+			return info, true
+		}
+	}
+	return container.InspectResponse{}, false
+}
+
+// containerStartedAt returns when the container last started, or the zero
+// time when unknown.
+func containerStartedAt(info container.InspectResponse) time.Time {
+	if info.ContainerJSONBase == nil || info.State == nil {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.RFC3339Nano, info.State.StartedAt)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
@@ -0,0 +1,164 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/rluders/berth/internal/service"
+	clientmock "github.com/rluders/berth/mocks/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setReattachIntervalForTest(t *testing.T, d time.Duration) {
+	t.Helper()
+	prev := logReattachInterval
+	logReattachInterval = d
+	t.Cleanup(func() { logReattachInterval = prev })
+}
+
+func setReattachTimeoutForTest(t *testing.T, d time.Duration) {
+	t.Helper()
+	prev := logReattachTimeout
+	logReattachTimeout = d
+	t.Cleanup(func() { logReattachTimeout = prev })
+}
+
+func inspected(id string, running bool, startedAt string, labels map[string]string) container.InspectResponse {
+	return container.InspectResponse{
+		ContainerJSONBase: &container.ContainerJSONBase{
+			ID:    id,
+			State: &container.State{Running: running, StartedAt: startedAt},
+		},
+		Config: &container.Config{Labels: labels},
+	}
+}
+
+func collectEntries(ch <-chan LogEntry) []LogEntry {
+	var entries []LogEntry
+	for e := range ch {
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func TestFollowContainerLogs_reattachesAfterRestart(t *testing.T) {
+	setReattachIntervalForTest(t, time.Millisecond)
+	mockClient := clientmock.NewMockAPIClient(t)
+	gone := errors.New("no such container")
+
+	mockClient.EXPECT().ContainerInspect(mock.Anything, "abc").
+		Return(inspected("abc", true, "2024-05-01T10:00:00Z", nil), nil).Once()
+	mockClient.EXPECT().ContainerLogs(mock.Anything, "abc", mock.MatchedBy(func(o container.LogsOptions) bool {
+		return o.Tail == "200" && o.Since == ""
+	})).Return(io.NopCloser(strings.NewReader("before\n")), nil).Once()
+	// Stopped, then running again with a new start time.
+	mockClient.EXPECT().ContainerInspect(mock.Anything, "abc").
+		Return(inspected("abc", false, "2024-05-01T10:00:00Z", nil), nil).Once()
+	mockClient.EXPECT().ContainerInspect(mock.Anything, "abc").
+		Return(inspected("abc", true, "2024-05-01T10:05:00Z", nil), nil).Once()
+	mockClient.EXPECT().ContainerLogs(mock.Anything, "abc", mock.MatchedBy(func(o container.LogsOptions) bool {
+		return o.Tail == "all" && o.Since == "2024-05-01T10:05:00Z"
+	})).Return(io.NopCloser(strings.NewReader("after\n")), nil).Once()
+	// Removed for good: a container outside compose is not replaced.
+	mockClient.EXPECT().ContainerInspect(mock.Anything, "abc").
+		Return(container.InspectResponse{}, gone).Once()
+	setContainerServiceForTest(service.NewContainerService(mockClient))
+
+	ch := make(chan LogEntry, 10)
+	FollowContainerLogs(context.Background(), "abc", DefaultLogOptions(), ch)
+	entries := collectEntries(ch)
+
+	started := time.Date(2024, 5, 1, 10, 5, 0, 0, time.UTC)
+	assert.Equal(t, []LogEntry{
+		{Stream: StreamStdout, Line: "before"},
+		{Stream: StreamStdout, Waiting: true},
+		restartMarker(started),
+		{Stream: StreamStdout, Line: "after"},
+	}, entries)
+	assert.Contains(t, entries[2].Line, "--- container restarted at ")
+}
+
+func TestFollowContainerLogs_followsComposeReplacement(t *testing.T) {
+	setReattachIntervalForTest(t, time.Millisecond)
+	mockClient := clientmock.NewMockAPIClient(t)
+	labels := map[string]string{
+		composeProjectLabel: "shop",
+		composeServiceLabel: "web",
+		composeNumberLabel:  "1",
+	}
+
+	mockClient.EXPECT().ContainerInspect(mock.Anything, "old").
+		Return(inspected("old", true, "2024-05-01T10:00:00Z", labels), nil).Once()
+	mockClient.EXPECT().ContainerLogs(mock.Anything, "old", mock.Anything).
+		Return(io.NopCloser(strings.NewReader("old run\n")), nil).Once()
+	mockClient.EXPECT().ContainerInspect(mock.Anything, "old").
+		Return(container.InspectResponse{}, errors.New("no such container"))
+	mockClient.EXPECT().ContainerList(mock.Anything, mock.Anything).Return([]container.Summary{
+		{ID: "other", State: "running", Labels: map[string]string{composeProjectLabel: "shop", composeServiceLabel: "db", composeNumberLabel: "1"}},
+		{ID: "new", State: "running", Labels: labels},
+	}, nil).Once()
+	mockClient.EXPECT().ContainerInspect(mock.Anything, "new").
+		Return(inspected("new", true, "2024-05-01T10:01:00Z", labels), nil).Once()
+	mockClient.EXPECT().ContainerLogs(mock.Anything, "new", mock.Anything).
+		Return(io.NopCloser(strings.NewReader("new run\n")), nil).Once()
+	// The replacement is still running from the same start after its stream ends.
+	mockClient.EXPECT().ContainerInspect(mock.Anything, "new").
+		Return(inspected("new", true, "2024-05-01T10:01:00Z", labels), nil).Once()
+	setContainerServiceForTest(service.NewContainerService(mockClient))
+
+	ch := make(chan LogEntry, 10)
+	FollowContainerLogs(context.Background(), "old", DefaultLogOptions(), ch)
+	entries := collectEntries(ch)
+
+	lines := make([]string, len(entries))
+	for i, e := range entries {
+		lines[i] = e.Line
+	}
+	assert.Len(t, lines, 3)
+	assert.Equal(t, "old run", lines[0])
+	assert.True(t, entries[1].Marker)
+	assert.Equal(t, "new run", lines[2])
+}
+
+func TestFollowContainerLogs_boundedStreamDoesNotReattach(t *testing.T) {
+	mockClient := clientmock.NewMockAPIClient(t)
+	mockClient.EXPECT().ContainerInspect(mock.Anything, "abc").
+		Return(inspected("abc", true, "2024-05-01T10:00:00Z", nil), nil).Once()
+	mockClient.EXPECT().ContainerLogs(mock.Anything, "abc", mock.Anything).
+		Return(io.NopCloser(strings.NewReader("line\n")), nil).Once()
+	setContainerServiceForTest(service.NewContainerService(mockClient))
+
+	ch := make(chan LogEntry, 10)
+	FollowContainerLogs(context.Background(), "abc", LogOptions{Tail: "all", Until: "5m"}, ch)
+
+	assert.Len(t, collectEntries(ch), 1)
+}
+
+func TestFollowContainerLogs_givesUpOnContainerThatStaysStopped(t *testing.T) {
+	setReattachIntervalForTest(t, time.Millisecond)
+	setReattachTimeoutForTest(t, 20*time.Millisecond)
+	mockClient := clientmock.NewMockAPIClient(t)
+	mockClient.EXPECT().ContainerInspect(mock.Anything, "abc").
+		Return(inspected("abc", true, "2024-05-01T10:00:00Z", nil), nil).Once()
+	mockClient.EXPECT().ContainerLogs(mock.Anything, "abc", mock.Anything).
+		Return(io.NopCloser(strings.NewReader("line\n")), nil).Once()
+	mockClient.EXPECT().ContainerInspect(mock.Anything, "abc").
+		Return(inspected("abc", false, "2024-05-01T10:00:00Z", nil), nil)
+	setContainerServiceForTest(service.NewContainerService(mockClient))
+
+	ch := make(chan LogEntry, 10)
+	FollowContainerLogs(context.Background(), "abc", DefaultLogOptions(), ch)
+	entries := collectEntries(ch)
+
+	require.Len(t, entries, 3)
+	assert.True(t, entries[1].Waiting)
+	assert.True(t, entries[2].Marker)
+	assert.Equal(t, "--- container did not restart, stopped following ---", entries[2].Line)
+}
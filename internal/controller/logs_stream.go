@@ -1,36 +1,190 @@
 package controller
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"sort"
 	"sync"
+	"time"
+
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// Log streams a line can come from.
+const (
+	StreamStdout = "stdout"
+	StreamStderr = "stderr"
 )
 
-// LogEntry carries a single log line with its source container name.
+// LogEntry carries a single log line with its source container name and stream.
 type LogEntry struct {
-	ContainerName string
+	ContainerName string // empty for a single-container stream
+	Stream        string // StreamStdout or StreamStderr
 	Line          string
+	// Time is the engine timestamp of a multi-container entry, used to
+	// interleave the containers; zero when unknown.
+	Time time.Time
+	// Marker flags a note inserted by Berth, such as a restart, rather than
+	// container output.
+	Marker bool
+	// Waiting flags an entry without a line, sent when a followed stream
+	// ended and the container is awaited to run again.
+	Waiting bool
+}
+
+// multiplexedLogs reports whether a logs body carries stdcopy frame headers,
+// which start with the stream number. TTY containers send raw output instead.
+func multiplexedLogs(r *bufio.Reader) bool {
+	b, err := r.Peek(1)
+	if err != nil {
+		return false
+	}
+	switch stdcopy.StdType(b[0]) {
+	case stdcopy.Stdin, stdcopy.Stdout, stdcopy.Stderr, stdcopy.Systemerr:
+		return true
+	}
+	return false
+}
+
+// maxLogLineBytes caps a log line; longer output is sent in pieces.
+const maxLogLineBytes = 1024 * 1024
+
+// logLineWriter splits what is written to it into lines and sends them on ch
+// tagged with its stream. StreamContainerLogs demultiplexes into one writer per
+// stream from a single goroutine, so lines keep the order the engine sent them in.
+type logLineWriter struct {
+	ctx    context.Context
+	ch     chan<- LogEntry
+	stream string
+	buf    []byte
+}
+
+func (w *logLineWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	start := 0
+	for {
+		i := bytes.IndexByte(w.buf[start:], '\n')
+		if i < 0 {
+			break
+		}
+		if err := w.send(w.buf[start : start+i]); err != nil {
+			return 0, err
+		}
+		start += i + 1
+	}
+	w.buf = append(w.buf[:0], w.buf[start:]...)
+	if len(w.buf) >= maxLogLineBytes {
+		if err := w.flush(); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// flush sends what is left of a line without a trailing newline.
+func (w *logLineWriter) flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	err := w.send(w.buf)
+	w.buf = w.buf[:0]
+	return err
+}
+
+// send delivers one line, without a trailing carriage return, unless ctx is
+// cancelled first.
+func (w *logLineWriter) send(line []byte) error {
+	entry := LogEntry{Stream: w.stream, Line: string(bytes.TrimSuffix(line, []byte("\r")))}
+	select {
+	case <-w.ctx.Done():
+		return w.ctx.Err()
+	case w.ch <- entry:
+		return nil
+	}
 }
 
-// StreamMultiContainerLogs fans out one goroutine per container, writing LogEntry
-// values to ch. ch is closed when all goroutines finish or ctx is cancelled.
-func StreamMultiContainerLogs(ctx context.Context, containers []Container, ch chan<- LogEntry) {
+// logMergeWindow is how long StreamMultiContainerLogs holds entries so that
+// lines of several containers arriving close together leave in time order.
+const logMergeWindow = 100 * time.Millisecond
+
+// StreamMultiContainerLogs fans out one goroutine per container and writes
+// their LogEntry values to ch, interleaved by engine timestamp. Each
+// container is followed across restarts as by FollowContainerLogs. Timestamps are
+// always requested and are left in the line only when opts asks for them.
+// ch is closed when all goroutines finish or ctx is cancelled.
+func StreamMultiContainerLogs(ctx context.Context, containers []Container, opts LogOptions, ch chan<- LogEntry) {
 	defer close(ch)
 
+	engineOpts := opts
+	engineOpts.Timestamps = true
+	merged := make(chan LogEntry, 100)
 	var wg sync.WaitGroup
 	for _, c := range containers {
 		wg.Add(1)
 		go func(c Container) {
 			defer wg.Done()
-			lineCh := make(chan string, 100)
-			go StreamContainerLogs(ctx, c.ID, lineCh)
-			for line := range lineCh {
+			entryCh := make(chan LogEntry, 100)
+			go FollowContainerLogs(ctx, c.ID, engineOpts, entryCh)
+			for entry := range entryCh {
+				entry.ContainerName = c.Names
+				if ts, rest, ok := SplitLogTimestamp(entry.Line); ok {
+					entry.Time = ts
+					if !opts.Timestamps {
+						entry.Line = rest
+					}
+				}
 				select {
 				case <-ctx.Done():
 					return
-				case ch <- LogEntry{ContainerName: c.Names, Line: line}:
+				case merged <- entry:
 				}
 			}
 		}(c)
 	}
-	wg.Wait()
+	go func() {
+		wg.Wait()
+		close(merged)
+	}()
+	mergeLogEntries(ctx, merged, ch, logMergeWindow)
+}
+
+// mergeLogEntries forwards entries from in to out, holding them for up to
+// window and sending each batch sorted by time. It returns once in is closed
+// and drained, or when ctx is cancelled.
+func mergeLogEntries(ctx context.Context, in <-chan LogEntry, out chan<- LogEntry, window time.Duration) {
+	ticker := time.NewTicker(window)
+	defer ticker.Stop()
+
+	var pending []LogEntry
+	flush := func() bool {
+		sort.SliceStable(pending, func(i, j int) bool {
+			return pending[i].Time.Before(pending[j].Time)
+		})
+		for _, e := range pending {
+			select {
+			case <-ctx.Done():
+				return false
+			case out <- e:
+			}
+		}
+		pending = pending[:0]
+		return true
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e, ok := <-in:
+			if !ok {
+				flush()
+				return
+			}
+			pending = append(pending, e)
+		case <-ticker.C:
+			if !flush() {
+				return
+			}
+		}
+	}
 }
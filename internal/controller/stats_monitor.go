@@ -0,0 +1,141 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// StatSample is one point in a container's stats history.
+type StatSample struct {
+	Time time.Time
+	Stat ContainerStat
+}
+
+// StatsMonitor keeps one streaming stats connection per watched container and
+// records a rolling history of samples for each. It is safe for concurrent use.
+type StatsMonitor struct {
+	mu      sync.Mutex
+	size    int
+	streams map[string]context.CancelFunc
+	history map[string][]StatSample
+}
+
+// NewStatsMonitor creates a monitor that keeps up to historySize samples per
+// container.
+func NewStatsMonitor(historySize int) *StatsMonitor {
+	return &StatsMonitor{
+		size:    historySize,
+		streams: make(map[string]context.CancelFunc),
+		history: make(map[string][]StatSample),
+	}
+}
+
+// Sync starts a stream for every id not yet watched and stops (and forgets)
+// containers that are no longer in ids. Streams that ended, e.g. because the
+// engine restarted, are started again.
+func (s *StatsMonitor) Sync(ids []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	want := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		want[id] = true
+		if _, ok := s.streams[id]; ok {
+			continue
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		s.streams[id] = cancel
+		go s.stream(ctx, id)
+	}
+	for id, cancel := range s.streams {
+		if !want[id] {
+			cancel()
+			delete(s.streams, id)
+			delete(s.history, id)
+		}
+	}
+	for id := range s.history {
+		if !want[id] {
+			delete(s.history, id)
+		}
+	}
+}
+
+// Stop closes every stream.
+func (s *StatsMonitor) Stop() {
+	s.Sync(nil)
+}
+
+// Latest returns the most recent stat of every watched container that has
+// reported at least once.
+func (s *StatsMonitor) Latest() map[string]ContainerStat {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	latest := make(map[string]ContainerStat, len(s.history))
+	for id, samples := range s.history {
+		if len(samples) > 0 {
+			latest[id] = samples[len(samples)-1].Stat
+		}
+	}
+	return latest
+}
+
+// History returns a copy of a container's samples, oldest first.
+func (s *StatsMonitor) History(id string) []StatSample {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]StatSample(nil), s.history[id]...)
+}
+
+// Record appends a sample to a container's history, dropping the oldest
+// samples beyond the history size.
+func (s *StatsMonitor) Record(id string, sample StatSample) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h := append(s.history[id], sample)
+	if len(h) > s.size {
+		h = h[len(h)-s.size:]
+	}
+	s.history[id] = h
+}
+
+// stream decodes the engine's stats stream for id until ctx is cancelled or
+// the stream ends, then unregisters itself so the next Sync can reconnect.
+func (s *StatsMonitor) stream(ctx context.Context, id string) {
+	defer func() {
+		s.mu.Lock()
+		// Only unregister if Sync has not already replaced or removed this stream.
+		if cancel, ok := s.streams[id]; ok && ctx.Err() == nil {
+			cancel()
+			delete(s.streams, id)
+		}
+		s.mu.Unlock()
+	}()
+
+	resp, err := containerService.ContainerStats(ctx, id, true)
+	if err != nil {
+		slog.Debug("stats stream: connect failed", "id", id, "err", err)
+		return
+	}
+	defer func() {
+		// The stream is read-only; a close failure leaves nothing to recover.
+		_ = resp.Body.Close()
+	}()
+
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var raw statsJSON
+		if err := dec.Decode(&raw); err != nil {
+			if ctx.Err() == nil {
+				slog.Debug("stats stream: ended", "id", id, "err", err)
+			}
+			return
+		}
+		s.Record(id, StatSample{Time: time.Now(), Stat: calculateStat(raw)})
+	}
+}
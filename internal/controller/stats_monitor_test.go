@@ -0,0 +1,72 @@
+package controller
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/rluders/berth/internal/service"
+	clientmock "github.com/rluders/berth/mocks/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const streamedStats = `{"cpu_stats":{"cpu_usage":{"total_usage":2000},"system_cpu_usage":10000,"online_cpus":1},"precpu_stats":{"cpu_usage":{"total_usage":1000},"system_cpu_usage":5000},"memory_stats":{"usage":100,"limit":1000},"networks":{"eth0":{"rx_bytes":10,"tx_bytes":20},"eth1":{"rx_bytes":1,"tx_bytes":2}},"blkio_stats":{"io_service_bytes_recursive":[{"op":"read","value":300},{"op":"write","value":400}]},"pids_stats":{"current":7}}
+{"cpu_stats":{"cpu_usage":{"total_usage":3000},"system_cpu_usage":15000,"online_cpus":1},"precpu_stats":{"cpu_usage":{"total_usage":2000},"system_cpu_usage":10000},"memory_stats":{"usage":200,"limit":1000},"pids_stats":{"current":8}}
+`
+
+func TestStatsMonitor_recordsStreamedSamples(t *testing.T) {
+	mockClient := clientmock.NewMockAPIClient(t)
+	mockClient.EXPECT().
+		ContainerStats(mock.Anything, "abc123", true).
+		Return(container.StatsResponseReader{Body: io.NopCloser(strings.NewReader(streamedStats))}, nil)
+
+	setContainerServiceForTest(service.NewContainerService(mockClient))
+
+	mon := NewStatsMonitor(10)
+	mon.Sync([]string{"abc123"})
+
+	require.Eventually(t, func() bool { return len(mon.History("abc123")) == 2 }, time.Second, 5*time.Millisecond)
+
+	first := mon.History("abc123")[0].Stat
+	assert.Equal(t, uint64(11), first.NetRx)
+	assert.Equal(t, uint64(22), first.NetTx)
+	assert.Equal(t, uint64(300), first.BlockRead)
+	assert.Equal(t, uint64(400), first.BlockWrite)
+	assert.Equal(t, uint64(7), first.PIDs)
+	assert.InDelta(t, 20.0, first.CPUPercent, 0.001)
+
+	assert.Equal(t, uint64(200), mon.Latest()["abc123"].MemUsage)
+
+	// The stream ended, so the container is no longer registered.
+	require.Eventually(t, func() bool {
+		mon.mu.Lock()
+		defer mon.mu.Unlock()
+		return len(mon.streams) == 0
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestStatsMonitor_recordKeepsRollingWindow(t *testing.T) {
+	mon := NewStatsMonitor(3)
+	for i := 1; i <= 5; i++ {
+		mon.Record("abc", StatSample{Stat: ContainerStat{PIDs: uint64(i)}})
+	}
+
+	h := mon.History("abc")
+	require.Len(t, h, 3)
+	assert.Equal(t, uint64(3), h[0].Stat.PIDs)
+	assert.Equal(t, uint64(5), h[2].Stat.PIDs)
+}
+
+func TestStatsMonitor_syncForgetsRemovedContainers(t *testing.T) {
+	mon := NewStatsMonitor(3)
+	mon.Record("gone", StatSample{Stat: ContainerStat{PIDs: 1}})
+
+	mon.Sync(nil)
+
+	assert.Empty(t, mon.History("gone"))
+	assert.Empty(t, mon.Latest())
+}
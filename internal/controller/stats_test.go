@@ -1,6 +1,9 @@
 package controller
 
 import (
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -208,3 +211,60 @@ func TestCalculateStat(t *testing.T) {
 		})
 	}
 }
+
+func TestCalculateStat_recordedPayloads(t *testing.T) {
+	tests := []struct {
+		file string
+		want ContainerStat
+	}{
+		{
+			// usage - total_inactive_file; blkio ops are capitalised on cgroup v1.
+			file: "docker_cgroup_v1.json",
+			want: ContainerStat{CPUPercent: 20, MemUsage: 94371840, MemLimit: 2147483648, NetRx: 1000, NetTx: 2000, BlockRead: 4096, BlockWrite: 8192, PIDs: 12},
+		},
+		{
+			// usage - inactive_file; cgroup v2 has no cache key.
+			file: "docker_cgroup_v2.json",
+			want: ContainerStat{CPUPercent: 40, MemUsage: 36700160, MemLimit: 8589934592, NetRx: 5100, NetTx: 6200, BlockRead: 1048576, BlockWrite: 2097152, PIDs: 5},
+		},
+		{
+			// No memory stats map, unlimited memory sentinel and no online_cpus.
+			file: "podman_compat.json",
+			want: ContainerStat{CPUPercent: 25, MemUsage: 73400320, MemLimit: 0, NetRx: 700, NetTx: 800, PIDs: 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", "stats", tt.file))
+			require.NoError(t, err)
+
+			var s statsJSON
+			require.NoError(t, json.Unmarshal(data, &s))
+
+			got := calculateStat(s)
+			assert.InDelta(t, tt.want.CPUPercent, got.CPUPercent, 0.001)
+			got.CPUPercent = tt.want.CPUPercent
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMemUsageNoCache(t *testing.T) {
+	tests := []struct {
+		name  string
+		usage uint64
+		stats map[string]uint64
+		want  uint64
+	}{
+		{"cgroup v1 prefers total_inactive_file over cache", 1000, map[string]uint64{"total_inactive_file": 100, "cache": 300}, 900},
+		{"cgroup v2 inactive_file", 1000, map[string]uint64{"inactive_file": 200}, 800},
+		{"legacy cache only", 1000, map[string]uint64{"cache": 300}, 700},
+		{"no stats map (podman)", 1000, nil, 1000},
+		{"cache larger than usage does not underflow", 100, map[string]uint64{"inactive_file": 200}, 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, memUsageNoCache(tt.usage, tt.stats))
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ComposeTool is a compose implementation, named by the command that runs it.
+type ComposeTool string
+
+const (
+	// DockerCompose is the compose v2 plugin of the docker CLI.
+	DockerCompose ComposeTool = "docker compose"
+	// DockerComposeStandalone is the standalone docker-compose binary (v1,
+	// or v2 installed outside the CLI plugins).
+	DockerComposeStandalone ComposeTool = "docker-compose"
+	// PodmanCompose is podman's compose subcommand, which runs an external
+	// compose provider.
+	PodmanCompose ComposeTool = "podman compose"
+	// PodmanComposeStandalone is the podman-compose Python tool.
+	PodmanComposeStandalone ComposeTool = "podman-compose"
+)
+
+// Command splits the tool into its executable and the arguments that come
+// before the compose arguments.
+func (t ComposeTool) Command() (string, []string) {
+	fields := strings.Fields(string(t))
+	if len(fields) == 0 {
+		return "", nil
+	}
+	return fields[0], fields[1:]
+}
+
+// HasJSONConfig reports whether the tool renders `config --format json`;
+// podman-compose only prints YAML.
+func (t ComposeTool) HasJSONConfig() bool {
+	name, _ := t.Command()
+	return filepath.Base(name) != string(PodmanComposeStandalone)
+}
+
+// lookPath and probeCompose are replaced in tests.
+var (
+	lookPath     = exec.LookPath
+	probeCompose = func(path string, args []string) error {
+		return exec.Command(path, append(args, "version")...).Run()
+	}
+)
+
+// composeCandidates returns the compose tools in the order they are tried:
+// those of engine first.
+func composeCandidates(engine EngineType) []ComposeTool {
+	docker := []ComposeTool{DockerCompose, DockerComposeStandalone}
+	podman := []ComposeTool{PodmanCompose, PodmanComposeStandalone}
+	if engine == Podman {
+		return append(podman, docker...)
+	}
+	return append(docker, podman...)
+}
+
+// DetectComposeTool returns the first compose tool available, preferring the
+// tools of engine. A subcommand tool (docker compose, podman compose) must
+// also answer "version", as the CLI may be installed without compose. The
+// error lists why each tool was rejected.
+func DetectComposeTool(engine EngineType) (ComposeTool, error) {
+	var reasons []string
+	for _, tool := range composeCandidates(engine) {
+		err := CheckComposeTool(tool)
+		if err == nil {
+			return tool, nil
+		}
+		reasons = append(reasons, err.Error())
+	}
+	return "", fmt.Errorf("no compose tool found: %s", strings.Join(reasons, "; "))
+}
+
+// CheckComposeTool reports whether tool can be run, with an error naming
+// what is missing when it cannot.
+func CheckComposeTool(tool ComposeTool) error {
+	name, args := tool.Command()
+	if name == "" {
+		return errors.New("empty compose tool")
+	}
+	path, err := lookPath(name)
+	if err != nil {
+		return fmt.Errorf("%s: %s is not installed or not in PATH", tool, name)
+	}
+	if len(args) > 0 {
+		if err := probeCompose(path, args); err != nil {
+			return fmt.Errorf("%s: %s has no working %q subcommand: %w", tool, name, strings.Join(args, " "), err)
+		}
+	}
+	return nil
+}
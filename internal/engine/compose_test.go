@@ -0,0 +1,82 @@
+package engine
+
+import (
+	"errors"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubComposeTools makes only the given executables exist, and "compose"
+// subcommands work only for those in plugins.
+func stubComposeTools(t *testing.T, installed []string, plugins []string) {
+	t.Helper()
+	origLook, origProbe := lookPath, probeCompose
+	t.Cleanup(func() { lookPath, probeCompose = origLook, origProbe })
+
+	lookPath = func(name string) (string, error) {
+		for _, n := range installed {
+			if n == name {
+				return "/usr/bin/" + name, nil
+			}
+		}
+		return "", exec.ErrNotFound
+	}
+	probeCompose = func(path string, _ []string) error {
+		for _, n := range plugins {
+			if path == "/usr/bin/"+n {
+				return nil
+			}
+		}
+		return errors.New("exit status 125")
+	}
+}
+
+func TestComposeTool_Command(t *testing.T) {
+	name, args := DockerCompose.Command()
+	assert.Equal(t, "docker", name)
+	assert.Equal(t, []string{"compose"}, args)
+
+	name, args = PodmanComposeStandalone.Command()
+	assert.Equal(t, "podman-compose", name)
+	assert.Empty(t, args)
+}
+
+func TestComposeTool_HasJSONConfig(t *testing.T) {
+	assert.True(t, DockerCompose.HasJSONConfig())
+	assert.True(t, PodmanCompose.HasJSONConfig(), "depends on its provider, found out when run")
+	assert.False(t, PodmanComposeStandalone.HasJSONConfig())
+	assert.False(t, ComposeTool("/usr/local/bin/podman-compose").HasJSONConfig())
+}
+
+func TestDetectComposeTool_prefersEngine(t *testing.T) {
+	stubComposeTools(t, []string{"docker", "podman"}, []string{"docker", "podman"})
+
+	tool, err := DetectComposeTool(Docker)
+	require.NoError(t, err)
+	assert.Equal(t, DockerCompose, tool)
+
+	tool, err = DetectComposeTool(Podman)
+	require.NoError(t, err)
+	assert.Equal(t, PodmanCompose, tool)
+}
+
+func TestDetectComposeTool_fallsBackWithoutPlugin(t *testing.T) {
+	stubComposeTools(t, []string{"docker", "docker-compose"}, nil)
+
+	tool, err := DetectComposeTool(Docker)
+	require.NoError(t, err)
+	assert.Equal(t, DockerComposeStandalone, tool)
+}
+
+func TestDetectComposeTool_explainsWhatIsMissing(t *testing.T) {
+	stubComposeTools(t, []string{"docker"}, nil)
+
+	_, err := DetectComposeTool(Docker)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `docker compose: docker has no working "compose" subcommand`)
+	assert.Contains(t, err.Error(), "docker-compose: docker-compose is not installed or not in PATH")
+	assert.Contains(t, err.Error(), "podman-compose: podman-compose is not installed")
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/docker/docker/api/types"
 	containerTypes "github.com/docker/docker/api/types/container"
 	dockerClient "github.com/docker/docker/client"
 )
@@ -19,6 +20,15 @@ type ContainerService interface {
 	ContainerLogs(ctx context.Context, containerID string, options containerTypes.LogsOptions) (io.ReadCloser, error)
 	ContainerInspect(ctx context.Context, containerID string) (containerTypes.InspectResponse, error)
 	ContainerStats(ctx context.Context, containerID string, stream bool) (containerTypes.StatsResponseReader, error)
+	ContainerDiff(ctx context.Context, containerID string) ([]containerTypes.FilesystemChange, error)
+	ContainerTop(ctx context.Context, containerID string, arguments []string) (containerTypes.TopResponse, error)
+	CopyFromContainer(ctx context.Context, containerID, srcPath string) (io.ReadCloser, containerTypes.PathStat, error)
+	CopyToContainer(ctx context.Context, containerID, dstPath string, content io.Reader, options containerTypes.CopyToContainerOptions) error
+	ContainerStatPath(ctx context.Context, containerID, path string) (containerTypes.PathStat, error)
+	ExecCreate(ctx context.Context, containerID string, options containerTypes.ExecOptions) (containerTypes.ExecCreateResponse, error)
+	ExecAttach(ctx context.Context, execID string, options containerTypes.ExecAttachOptions) (types.HijackedResponse, error)
+	ExecInspect(ctx context.Context, execID string) (containerTypes.ExecInspect, error)
+	ExecResize(ctx context.Context, execID string, options containerTypes.ResizeOptions) error
 }
 
 // dockerContainerService is a concrete implementation of ContainerService.
@@ -78,3 +88,65 @@ func (s *dockerContainerService) ContainerInspect(ctx context.Context, container
 func (s *dockerContainerService) ContainerStats(ctx context.Context, containerID string, stream bool) (containerTypes.StatsResponseReader, error) {
 	return s.client.ContainerStats(ctx, containerID, stream)
 }
+
+// ContainerDiff lists the filesystem changes in a container's writable layer.
+func (s *dockerContainerService) ContainerDiff(ctx context.Context, containerID string) ([]containerTypes.FilesystemChange, error) {
+	changes, err := s.client.ContainerDiff(ctx, containerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff container %s: %w", containerID, err)
+	}
+	return changes, nil
+}
+
+// ContainerTop lists the processes running in a container. arguments are
+// passed to ps on the host; nil uses the engine default.
+func (s *dockerContainerService) ContainerTop(ctx context.Context, containerID string, arguments []string) (containerTypes.TopResponse, error) {
+	top, err := s.client.ContainerTop(ctx, containerID, arguments)
+	if err != nil {
+		return containerTypes.TopResponse{}, fmt.Errorf("failed to list processes of container %s: %w", containerID, err)
+	}
+	return top, nil
+}
+
+// CopyFromContainer returns a tar archive of srcPath inside the container.
+func (s *dockerContainerService) CopyFromContainer(ctx context.Context, containerID, srcPath string) (io.ReadCloser, containerTypes.PathStat, error) {
+	return s.client.CopyFromContainer(ctx, containerID, srcPath)
+}
+
+// CopyToContainer extracts a tar archive into dstPath inside the container.
+func (s *dockerContainerService) CopyToContainer(ctx context.Context, containerID, dstPath string, content io.Reader, options containerTypes.CopyToContainerOptions) error {
+	return s.client.CopyToContainer(ctx, containerID, dstPath, content, options)
+}
+
+// ContainerStatPath returns file information for a path inside the container.
+func (s *dockerContainerService) ContainerStatPath(ctx context.Context, containerID, path string) (containerTypes.PathStat, error) {
+	return s.client.ContainerStatPath(ctx, containerID, path)
+}
+
+// ExecCreate creates an exec instance in a running container.
+func (s *dockerContainerService) ExecCreate(ctx context.Context, containerID string, options containerTypes.ExecOptions) (containerTypes.ExecCreateResponse, error) {
+	resp, err := s.client.ContainerExecCreate(ctx, containerID, options)
+	if err != nil {
+		return containerTypes.ExecCreateResponse{}, fmt.Errorf("failed to create exec in container %s: %w", containerID, err)
+	}
+	return resp, nil
+}
+
+// ExecAttach starts an exec instance and attaches to its streams.
+func (s *dockerContainerService) ExecAttach(ctx context.Context, execID string, options containerTypes.ExecAttachOptions) (types.HijackedResponse, error) {
+	resp, err := s.client.ContainerExecAttach(ctx, execID, options)
+	if err != nil {
+		return types.HijackedResponse{}, fmt.Errorf("failed to attach to exec %s: %w", execID, err)
+	}
+	return resp, nil
+}
+
+// ExecInspect returns the state of an exec instance, including its exit code.
+func (s *dockerContainerService) ExecInspect(ctx context.Context, execID string) (containerTypes.ExecInspect, error) {
+	return s.client.ContainerExecInspect(ctx, execID)
+}
+
+// ExecResize resizes the TTY of an exec instance.
+func (s *dockerContainerService) ExecResize(ctx context.Context, execID string, options containerTypes.ResizeOptions) error {
+	return s.client.ContainerExecResize(ctx, execID, options)
+}
@@ -447,3 +447,216 @@ func Test_dockerContainerService_StopContainer(t *testing.T) {
 		})
 	}
 }
+
+func Test_dockerContainerService_ContainerDiff(t *testing.T) {
+	type fields struct {
+		client dockerClient.APIClient
+	}
+	type args struct {
+		ctx         context.Context
+		containerID string
+	}
+
+	mockClient := client.NewMockAPIClient(t)
+	successChanges := []container.FilesystemChange{
+		{Kind: container.ChangeAdd, Path: "/tmp/new.conf"},
+		{Kind: container.ChangeModify, Path: "/etc"},
+	}
+
+	// Setup successful container diff
+	mockClient.EXPECT().ContainerDiff(mock.Anything, "container123").Return(successChanges, nil)
+
+	// Setup failed container diff
+	mockClient.EXPECT().ContainerDiff(mock.Anything, "invalid-container").Return(nil, fmt.Errorf("container not found"))
+
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []container.FilesystemChange
+		wantErr bool
+	}{
+		{
+			name: "successful container diff",
+			fields: fields{
+				client: mockClient,
+			},
+			args: args{
+				ctx:         context.Background(),
+				containerID: "container123",
+			},
+			want:    successChanges,
+			wantErr: false,
+		},
+		{
+			name: "failed container diff",
+			fields: fields{
+				client: mockClient,
+			},
+			args: args{
+				ctx:         context.Background(),
+				containerID: "invalid-container",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &dockerContainerService{
+				client: tt.fields.client,
+			}
+			got, err := s.ContainerDiff(tt.args.ctx, tt.args.containerID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ContainerDiff() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ContainerDiff() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_dockerContainerService_ExecCreate(t *testing.T) {
+	type fields struct {
+		client dockerClient.APIClient
+	}
+	type args struct {
+		ctx         context.Context
+		containerID string
+		options     container.ExecOptions
+	}
+
+	mockClient := client.NewMockAPIClient(t)
+	options := container.ExecOptions{Cmd: []string{"/bin/sh"}, Tty: true, AttachStdout: true}
+
+	// Setup successful exec create
+	mockClient.EXPECT().ContainerExecCreate(mock.Anything, "container123", options).Return(container.ExecCreateResponse{ID: "exec123"}, nil)
+
+	// Setup failed exec create
+	mockClient.EXPECT().ContainerExecCreate(mock.Anything, "stopped-container", options).Return(container.ExecCreateResponse{}, fmt.Errorf("container is not running"))
+
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    container.ExecCreateResponse
+		wantErr bool
+	}{
+		{
+			name: "successful exec create",
+			fields: fields{
+				client: mockClient,
+			},
+			args: args{
+				ctx:         context.Background(),
+				containerID: "container123",
+				options:     options,
+			},
+			want:    container.ExecCreateResponse{ID: "exec123"},
+			wantErr: false,
+		},
+		{
+			name: "failed exec create",
+			fields: fields{
+				client: mockClient,
+			},
+			args: args{
+				ctx:         context.Background(),
+				containerID: "stopped-container",
+				options:     options,
+			},
+			want:    container.ExecCreateResponse{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &dockerContainerService{
+				client: tt.fields.client,
+			}
+			got, err := s.ExecCreate(tt.args.ctx, tt.args.containerID, tt.args.options)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ExecCreate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExecCreate() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_dockerContainerService_ContainerTop(t *testing.T) {
+	type fields struct {
+		client dockerClient.APIClient
+	}
+	type args struct {
+		ctx         context.Context
+		containerID string
+		arguments   []string
+	}
+
+	mockClient := client.NewMockAPIClient(t)
+	successTop := container.TopResponse{
+		Titles:    []string{"PID", "COMMAND"},
+		Processes: [][]string{{"1", "nginx"}},
+	}
+
+	// Setup successful container top
+	mockClient.EXPECT().ContainerTop(mock.Anything, "container123", []string{"aux"}).Return(successTop, nil)
+
+	// Setup failed container top
+	mockClient.EXPECT().ContainerTop(mock.Anything, "stopped-container", []string{"aux"}).Return(container.TopResponse{}, fmt.Errorf("container is not running"))
+
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    container.TopResponse
+		wantErr bool
+	}{
+		{
+			name: "successful container top",
+			fields: fields{
+				client: mockClient,
+			},
+			args: args{
+				ctx:         context.Background(),
+				containerID: "container123",
+				arguments:   []string{"aux"},
+			},
+			want:    successTop,
+			wantErr: false,
+		},
+		{
+			name: "failed container top",
+			fields: fields{
+				client: mockClient,
+			},
+			args: args{
+				ctx:         context.Background(),
+				containerID: "stopped-container",
+				arguments:   []string{"aux"},
+			},
+			want:    container.TopResponse{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &dockerContainerService{
+				client: tt.fields.client,
+			}
+			got, err := s.ContainerTop(tt.args.ctx, tt.args.containerID, tt.args.arguments)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ContainerTop() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ContainerTop() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/rluders/berth/internal/config"
+	"github.com/rluders/berth/internal/controller"
+)
+
+// evaluateAlerts returns the reasons a container breaches the configured
+// thresholds, or nil when it is within them.
+func evaluateAlerts(cfg config.Alerts, c controller.Container, history []controller.StatSample, restarts int) []string {
+	var reasons []string
+	if cfg.Unhealthy && c.Health == controller.HealthUnhealthy {
+		reasons = append(reasons, "unhealthy")
+	}
+	if cfg.RestartCount > 0 && restarts >= cfg.RestartCount {
+		reasons = append(reasons, fmt.Sprintf("restarted %d times", restarts))
+	}
+	if c.State != "running" || len(history) == 0 {
+		return reasons
+	}
+	if cfg.CPUPercent > 0 && cpuSustained(history, cfg.CPUPercent, time.Duration(cfg.CPUSeconds)*time.Second) {
+		reasons = append(reasons, fmt.Sprintf("CPU ≥%.0f%% for %ds", cfg.CPUPercent, cfg.CPUSeconds))
+	}
+	latest := history[len(history)-1].Stat
+	if cfg.MemoryPercent > 0 && latest.MemLimit > 0 {
+		if pct := float64(latest.MemUsage) / float64(latest.MemLimit) * 100; pct >= cfg.MemoryPercent {
+			reasons = append(reasons, fmt.Sprintf("memory %.0f%% of limit", pct))
+		}
+	}
+	return reasons
+}
+
+// cpuSustained reports whether every sample in the trailing window of length
+// d is at or above threshold. The history must cover the whole window.
+func cpuSustained(history []controller.StatSample, threshold float64, d time.Duration) bool {
+	newest := history[len(history)-1].Time
+	for i := len(history) - 1; i >= 0; i-- {
+		s := history[i]
+		if s.Stat.CPUPercent < threshold {
+			return false
+		}
+		if newest.Sub(s.Time) >= d {
+			return true
+		}
+	}
+	return false
+}
+
+// updateAlerts re-evaluates every container, announces containers that
+// started alerting (or gained a reason) in the status bar and runs the
+// configured hook for them.
+func (m Model) updateAlerts() (Model, tea.Cmd) {
+	cfg := m.config.Alerts
+	alerts := make(map[string][]string)
+	var raised []string
+	var cmds []tea.Cmd
+	for _, c := range m.containers {
+		reasons := evaluateAlerts(cfg, c, m.statsMonitor.History(c.ID), m.restartStreaks[c.ID])
+		if len(reasons) == 0 {
+			continue
+		}
+		alerts[c.ID] = reasons
+		if newReasons := missing(reasons, m.alerts[c.ID]); len(newReasons) > 0 {
+			alert := strings.Join(newReasons, ", ")
+			raised = append(raised, c.Names+": "+alert)
+			if cfg.Hook != "" {
+				cmds = append(cmds, runAlertHookCmd(cfg.Hook, c.ID, c.Names, alert))
+			}
+		}
+	}
+	m.alerts = alerts
+	m.syncContainerViewport()
+	if len(raised) > 0 {
+		m.statusMessage = "⚠ " + strings.Join(raised, "; ")
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// missing returns the entries of want that are not in have.
+func missing(want, have []string) []string {
+	var out []string
+	for _, w := range want {
+		found := false
+		for _, h := range have {
+			if h == w {
+				found = true
+				break
+			}
+		}
+		if !found {
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+// alertSummary returns the footer line listing alerting containers, or ""
+// when none are alerting.
+func (m Model) alertSummary() string {
+	if len(m.alerts) == 0 {
+		return ""
+	}
+	var items []string
+	for _, c := range m.containers {
+		if reasons, ok := m.alerts[c.ID]; ok {
+			items = append(items, fmt.Sprintf("%s (%s)", c.Names, strings.Join(reasons, ", ")))
+		}
+	}
+	sort.Strings(items)
+	noun := "containers"
+	if len(items) == 1 {
+		noun = "container"
+	}
+	return fmt.Sprintf("⚠ %d %s alerting: %s", len(items), noun, strings.Join(items, "; "))
+}
+
+// restartWatchIDs returns the containers whose restart count is checked:
+// those that are new or changed state since prev, those restarting, and
+// those with a streak of restarts still to confirm or clear. Exited
+// containers cannot keep restarting, so they are skipped.
+func (m Model) restartWatchIDs(prev []controller.Container) []string {
+	if m.config.Alerts.RestartCount <= 0 {
+		return nil
+	}
+	prevState := make(map[string]string, len(prev))
+	for _, c := range prev {
+		prevState[c.ID] = c.State
+	}
+	var ids []string
+	for _, c := range m.containers {
+		if c.State != "running" && c.State != "restarting" {
+			continue
+		}
+		state, seen := prevState[c.ID]
+		if !seen || state != c.State || c.State == "restarting" || m.restartStreaks[c.ID] > 0 {
+			ids = append(ids, c.ID)
+		}
+	}
+	return ids
+}
+
+// recordRestartCounts compares sampled restart counts with the previous
+// sample of each container. An increase extends the container's streak; a
+// sample without one ends it. The first sample of a container only sets
+// its baseline.
+func (m *Model) recordRestartCounts(counts map[string]int) {
+	for id, n := range counts {
+		prev, seen := m.restartCounts[id]
+		switch {
+		case seen && n > prev:
+			m.restartStreaks[id] += n - prev
+		case seen:
+			delete(m.restartStreaks, id)
+		}
+		m.restartCounts[id] = n
+	}
+}
+
+// forgetRestartCounts drops the samples of containers no longer listed.
+func (m *Model) forgetRestartCounts() {
+	listed := make(map[string]bool, len(m.containers))
+	for _, c := range m.containers {
+		listed[c.ID] = true
+	}
+	for id := range m.restartCounts {
+		if !listed[id] {
+			delete(m.restartCounts, id)
+			delete(m.restartStreaks, id)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package tui
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/rluders/berth/internal/config"
+	"github.com/rluders/berth/internal/controller"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cpuHistory returns one sample per second with the given CPU values.
+func cpuHistory(values ...float64) []controller.StatSample {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	history := make([]controller.StatSample, len(values))
+	for i, v := range values {
+		history[i] = controller.StatSample{Time: start.Add(time.Duration(i) * time.Second), Stat: controller.ContainerStat{CPUPercent: v}}
+	}
+	return history
+}
+
+func TestEvaluateAlerts(t *testing.T) {
+	cfg := config.Alerts{CPUPercent: 80, CPUSeconds: 3, MemoryPercent: 90, RestartCount: 3, Unhealthy: true}
+	running := controller.Container{ID: "abc", State: "running"}
+	memory := func(usage, limit uint64) []controller.StatSample {
+		return []controller.StatSample{{Stat: controller.ContainerStat{MemUsage: usage, MemLimit: limit}}}
+	}
+
+	tests := []struct {
+		name     string
+		c        controller.Container
+		history  []controller.StatSample
+		restarts int
+		want     []string
+	}{
+		{"within thresholds", running, cpuHistory(10, 20, 30, 40), 0, nil},
+		{"sustained cpu", running, cpuHistory(10, 85, 90, 95, 99), 0, []string{"CPU ≥80% for 3s"}},
+		{"cpu spike shorter than window", running, cpuHistory(10, 10, 95, 99), 0, nil},
+		{"history shorter than window", running, cpuHistory(95, 99), 0, nil},
+		{"memory near limit", running, memory(95, 100), 0, []string{"memory 95% of limit"}},
+		{"memory without limit", running, memory(95, 0), 0, nil},
+		{"restart count", running, nil, 3, []string{"restarted 3 times"}},
+		{"unhealthy", controller.Container{State: "running", Health: controller.HealthUnhealthy}, nil, 0, []string{"unhealthy"}},
+		{"stopped container ignores stale stats", controller.Container{State: "exited"}, cpuHistory(99, 99, 99, 99), 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, evaluateAlerts(cfg, tt.c, tt.history, tt.restarts))
+		})
+	}
+}
+
+func TestEvaluateAlerts_zeroThresholdsDisableChecks(t *testing.T) {
+	c := controller.Container{State: "running", Health: controller.HealthUnhealthy}
+	assert.Empty(t, evaluateAlerts(config.Alerts{}, c, cpuHistory(100, 100, 100), 50))
+}
+
+func TestStatsHistoryLen_coversLongCPUWindow(t *testing.T) {
+	cfg := config.Alerts{CPUPercent: 80, CPUSeconds: 300}
+	running := controller.Container{ID: "abc", State: "running"}
+	alertsAfter := func(seconds int) []string {
+		monitor := controller.NewStatsMonitor(statsHistoryLen(cfg))
+		for _, s := range cpuHistory(slices.Repeat([]float64{95}, seconds+1)...) {
+			monitor.Record("abc", s)
+		}
+		return evaluateAlerts(cfg, running, monitor.History("abc"), 0)
+	}
+
+	assert.Empty(t, alertsAfter(299))
+	assert.Equal(t, []string{"CPU ≥80% for 300s"}, alertsAfter(300))
+	assert.Equal(t, []string{"CPU ≥80% for 300s"}, alertsAfter(900), "older samples are dropped, not the window")
+}
+
+func TestUpdateAlerts_announcesNewAlertsOnce(t *testing.T) {
+	m := InitialModel()
+	m.config.Alerts = config.Alerts{Unhealthy: true, Hook: "true"}
+	m.containers = []controller.Container{{ID: "abc", Names: "web", State: "running", Health: controller.HealthUnhealthy}}
+
+	m, cmd := m.updateAlerts()
+	assert.Equal(t, map[string][]string{"abc": {"unhealthy"}}, m.alerts)
+	assert.Equal(t, "⚠ web: unhealthy", m.statusMessage)
+	require.NotNil(t, cmd, "hook runs for a new alert")
+	assert.Contains(t, m.alertSummary(), "1 container alerting: web (unhealthy)")
+
+	m.statusMessage = ""
+	m, cmd = m.updateAlerts()
+	assert.Empty(t, m.statusMessage, "an ongoing alert is not announced again")
+	assert.Nil(t, cmd)
+
+	m.containers[0].Health = controller.HealthHealthy
+	m, _ = m.updateAlerts()
+	assert.Empty(t, m.alerts)
+	assert.Empty(t, m.alertSummary())
+}
+
+func TestRestartCounts_alertOnRecentRestartsAndClear(t *testing.T) {
+	m := InitialModel()
+	m.config.Alerts = config.Alerts{RestartCount: 2}
+	web := controller.Container{ID: "abc", Names: "web", State: "running"}
+	m.containers = []controller.Container{web}
+
+	m, _ = updateModel(t, m, restartCountsMsg{"abc": 40})
+	assert.Empty(t, m.alerts, "the first sample is only a baseline")
+
+	m, _ = updateModel(t, m, restartCountsMsg{"abc": 41})
+	assert.Empty(t, m.alerts)
+	assert.Equal(t, []string{"abc"}, m.restartWatchIDs(m.containers), "a streak keeps the container watched")
+
+	m, _ = updateModel(t, m, restartCountsMsg{"abc": 42})
+	assert.Equal(t, []string{"restarted 2 times"}, m.alerts["abc"])
+
+	m, _ = updateModel(t, m, restartCountsMsg{"abc": 42})
+	assert.Empty(t, m.alerts, "a sample without a new restart clears the alert")
+	assert.Empty(t, m.restartWatchIDs(m.containers))
+}
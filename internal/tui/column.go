@@ -30,6 +30,7 @@ type Column struct {
 var containerCols = []Column{
 	{Header: "Name", MinWidth: 20, Align: AlignLeft},
 	{Header: "Status", Fixed: 14, Align: AlignLeft},
+	{Header: "Health", Fixed: 11, Align: AlignLeft},
 	{Header: "Image", MinWidth: 20, Align: AlignLeft},
 	{Header: "Ports", Fixed: 18, Align: AlignLeft},
 	{Header: "CPU%", Fixed: 6, Align: AlignRight},
@@ -52,6 +53,36 @@ var volumeCols = []Column{
 	{Header: "Mountpoint", MinWidth: 60, Align: AlignLeft},
 }
 
+var changeCols = []Column{
+	{Header: "Kind", Fixed: 8, Align: AlignLeft},
+	{Header: "Path", MinWidth: 40, Align: AlignLeft},
+}
+
+var topCols = []Column{
+	{Header: "PID", Fixed: 8, Align: AlignRight},
+	{Header: "User", Fixed: 10, Align: AlignLeft},
+	{Header: "CPU%", Fixed: 7, Align: AlignRight},
+	{Header: "MEM%", Fixed: 7, Align: AlignRight},
+	{Header: "Command", MinWidth: 40, Align: AlignLeft},
+}
+
+var composeOpCols = []Column{
+	{Header: "Project", MinWidth: 16, Align: AlignLeft},
+	{Header: "Command", MinWidth: 30, Align: AlignLeft},
+	{Header: "Started", Fixed: 10, Align: AlignLeft},
+	{Header: "Took", Fixed: 10, Align: AlignRight},
+	{Header: "Status", Fixed: 10, Align: AlignLeft},
+}
+
+var driftCols = []Column{
+	{Header: "Service", MinWidth: 12, Align: AlignLeft},
+	{Header: "Container", MinWidth: 16, Align: AlignLeft},
+	{Header: "Status", Fixed: 12, Align: AlignLeft},
+	{Header: "Change", MinWidth: 16, Align: AlignLeft},
+	{Header: "Compose files", MinWidth: 20, Align: AlignLeft},
+	{Header: "Container value", MinWidth: 20, Align: AlignLeft},
+}
+
 var networkCols = []Column{
 	{Header: "ID", MinWidth: 20, Align: AlignLeft},
 	{Header: "Name", MinWidth: 30, Align: AlignLeft},
@@ -142,11 +173,11 @@ func RenderRow(cols []Column, values []string) []string {
 }
 
 // padHeader returns a plain-text header string padded to exactly width chars.
-// Headers are always ASCII so len() == visible width.
+// Headers carry no styling, but may end in a sort arrow.
 func padHeader(value string, width int, align AlignType) string {
-	vw := len(value)
+	vw := ansi.StringWidth(value)
 	if vw >= width {
-		return value[:width]
+		return ansi.Truncate(value, width, "")
 	}
 	pad := strings.Repeat(" ", width-vw)
 	if align == AlignRight {
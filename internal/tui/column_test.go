@@ -19,6 +19,8 @@ func TestBuildColumns_fillsWidthBudgetForAllTableSpecs(t *testing.T) {
 		"images":     imageCols,
 		"volumes":    volumeCols,
 		"networks":   networkCols,
+		"changes":    changeCols,
+		"top":        topCols,
 	} {
 		t.Run(name, func(t *testing.T) {
 			cols := BuildColumns(140, specs)
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
-	"os"
 	"strings"
 	"time"
 
@@ -94,6 +93,14 @@ func refreshTickCmd() tea.Cmd {
 	return tea.Tick(5*time.Second, func(time.Time) tea.Msg { return refreshTickMsg{} })
 }
 
+// logFrameInterval is how often the logs view re-renders while lines arrive;
+// chunks received in between are coalesced into one render.
+const logFrameInterval = 50 * time.Millisecond
+
+func logRenderTickCmd() tea.Cmd {
+	return tea.Tick(logFrameInterval, func(time.Time) tea.Msg { return logRenderMsg{} })
+}
+
 // ── Container action commands ─────────────────────────────────────────────────
 
 func startContainerCmd(idOrName string) tea.Cmd {
@@ -203,51 +210,178 @@ func inspectContainerCmd(idOrName string) tea.Cmd {
 	}
 }
 
+// ── Container filesystem commands ─────────────────────────────────────────────
+
+func fetchChangesCmd(idOrName string) tea.Cmd {
+	return func() tea.Msg {
+		slog.Debug("fetchChangesCmd", "id", idOrName)
+		changes, err := controller.GetContainerChanges(idOrName)
+		if err != nil {
+			return errMsg{err}
+		}
+		return changesMsg(changes)
+	}
+}
+
+func copyFromContainerCmd(idOrName, srcPath, hostDest string) tea.Cmd {
+	return func() tea.Msg {
+		slog.Debug("copyFromContainerCmd", "id", idOrName, "src", srcPath, "dest", hostDest)
+		written, skipped, err := controller.CopyFromContainer(idOrName, srcPath, hostDest)
+		if err != nil {
+			return errMsg{err}
+		}
+		if len(skipped) > 0 {
+			return statusMsg(fmt.Sprintf("Copied %s:%s to %s, skipping %d special file(s): %s.",
+				idOrName, srcPath, written, len(skipped), strings.Join(skipped, ", ")))
+		}
+		return statusMsg(fmt.Sprintf("Copied %s:%s to %s.", idOrName, srcPath, written))
+	}
+}
+
+func copyToContainerCmd(idOrName, hostSrc, containerDest string) tea.Cmd {
+	return func() tea.Msg {
+		slog.Debug("copyToContainerCmd", "id", idOrName, "src", hostSrc, "dest", containerDest)
+		if err := controller.CopyToContainer(idOrName, hostSrc, containerDest); err != nil {
+			return errMsg{err}
+		}
+		return statusMsg(fmt.Sprintf("Copied %s to %s:%s.", hostSrc, idOrName, containerDest))
+	}
+}
+
 // ── Log streaming ─────────────────────────────────────────────────────────────
 
-func startLogStreamCmd(id string) (chan string, context.CancelFunc, tea.Cmd) {
-	ch := make(chan string, 500)
+func startLogStreamCmd(id string, opts controller.LogOptions) (chan controller.LogEntry, context.CancelFunc, tea.Cmd) {
+	ch := make(chan controller.LogEntry, 500)
 	ctx, cancel := context.WithCancel(context.Background())
-	go controller.StreamContainerLogs(ctx, id, ch)
-	return ch, cancel, waitForLogLineCmd(ch)
+	go controller.FollowContainerLogs(ctx, id, opts, ch)
+	return ch, cancel, waitForLogChunkCmd(ch)
 }
 
-func startGroupLogStreamCmd(containers []controller.Container) (chan string, context.CancelFunc, tea.Cmd) {
-	ch := make(chan string, 500)
+func startGroupLogStreamCmd(containers []controller.Container, opts controller.LogOptions) (chan controller.LogEntry, context.CancelFunc, tea.Cmd) {
+	ch := make(chan controller.LogEntry, 500)
 	ctx, cancel := context.WithCancel(context.Background())
-	entryCh := make(chan controller.LogEntry, 500)
-	go controller.StreamMultiContainerLogs(ctx, containers, entryCh)
-	go func() {
-		defer close(ch)
-		for entry := range entryCh {
-			ch <- "[" + entry.ContainerName + "] " + entry.Line
-		}
-	}()
-	return ch, cancel, waitForLogLineCmd(ch)
+	go controller.StreamMultiContainerLogs(ctx, containers, opts, ch)
+	return ch, cancel, waitForLogChunkCmd(ch)
 }
 
-func waitForLogLineCmd(ch <-chan string) tea.Cmd {
+// maxLogChunk caps how many entries one logChunkMsg carries.
+const maxLogChunk = 1000
+
+// waitForLogChunkCmd waits for the next entry on ch, then takes whatever else
+// is already queued so a burst of lines arrives as one message.
+func waitForLogChunkCmd(ch <-chan controller.LogEntry) tea.Cmd {
 	return func() tea.Msg {
-		line, ok := <-ch
+		entry, ok := <-ch
 		if !ok {
-			return logStreamDoneMsg{}
+			return logStreamDoneMsg{ch: ch}
+		}
+		entries := []controller.LogEntry{entry}
+		for len(entries) < maxLogChunk {
+			select {
+			case entry, ok := <-ch:
+				if !ok {
+					// The next wait reports the end of the stream.
+					return logChunkMsg{ch: ch, entries: entries}
+				}
+				entries = append(entries, entry)
+			default:
+				return logChunkMsg{ch: ch, entries: entries}
+			}
+		}
+		return logChunkMsg{ch: ch, entries: entries}
+	}
+}
+
+// saveLogsCmd writes log records read from the logs view buffer.
+func saveLogsCmd(dir, name string, records []controller.LogRecord, format string) tea.Cmd {
+	return func() tea.Msg {
+		path, err := controller.SaveLogRecords(dir, name, records, format)
+		if err != nil {
+			return errMsg{err}
+		}
+		return statusMsg(fmt.Sprintf("Saved %d lines to %s.", len(records), path))
+	}
+}
+
+// fetchAndSaveLogsCmd reads the requested log range again, with streams and
+// timestamps, and writes it to a file.
+func fetchAndSaveLogsCmd(dir, name string, containers []controller.Container, opts controller.LogOptions, format string) tea.Cmd {
+	return func() tea.Msg {
+		slog.Debug("fetchAndSaveLogsCmd", "name", name, "dir", dir, "format", format)
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+		records, err := controller.FetchLogRecords(ctx, containers, opts)
+		if err != nil {
+			return errMsg{err}
 		}
-		return logChunkMsg(line)
+		return saveLogsCmd(dir, name, records, format)()
 	}
 }
 
 // ── Stats ─────────────────────────────────────────────────────────────────────
 
-func fetchStatsCmd(ids []string) tea.Cmd {
+// syncStatsCmd points the stats monitor at the running containers and reports
+// the latest sample of each.
+func syncStatsCmd(monitor *controller.StatsMonitor, ids []string) tea.Cmd {
+	return func() tea.Msg {
+		monitor.Sync(ids)
+		return containerStatsMsg(monitor.Latest())
+	}
+}
+
+// fetchRestartCountsCmd reads the restart count of each container for the
+// restart alert.
+func fetchRestartCountsCmd(ids []string) tea.Cmd {
 	return func() tea.Msg {
-		result := make(map[string]controller.ContainerStat)
-		for _, id := range ids {
-			stat, err := controller.GetContainerStats(id)
-			if err == nil {
-				result[id] = stat
+		return restartCountsMsg(controller.GetRestartCounts(ids))
+	}
+}
+
+// discoverComposeCmd reads the compose projects of targets and of the compose
+// files found in dirs. Targets whose files are gone are reported instead.
+func discoverComposeCmd(targets []controller.ComposeTarget, dirs []string) tea.Cmd {
+	return func() tea.Msg {
+		var (
+			found []controller.ComposeTarget
+			gone  []string
+		)
+		for _, t := range targets {
+			if t.Exists() {
+				found = append(found, t)
+			} else {
+				gone = append(gone, t.Project)
 			}
 		}
-		return containerStatsMsg(result)
+		projects, err := controller.DiscoverComposeProjects(found, dirs)
+		return composeProjectsMsg{projects: projects, gone: gone, err: err}
+	}
+}
+
+// composeDriftCmd compares the containers of a compose project with its files.
+func composeDriftCmd(t controller.ComposeTarget) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		drift, err := controller.ComposeDrift(ctx, t)
+		return composeDriftMsg{project: t.Project, drift: drift, err: err}
+	}
+}
+
+// runAlertHookCmd runs the user's alert hook for a container that started
+// alerting.
+func runAlertHookCmd(hook, id, name, alert string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		err := controller.RunHook(ctx, hook, []string{
+			"BERTH_CONTAINER_ID=" + id,
+			"BERTH_CONTAINER_NAME=" + name,
+			"BERTH_ALERT=" + alert,
+		})
+		if err != nil {
+			slog.Error("runAlertHookCmd", "container", name, "error", err)
+		}
+		return alertHookMsg{name: name, err: err}
 	}
 }
 
@@ -341,66 +475,150 @@ func totalCleanupCmd() tea.Cmd {
 
 // composeStreamCmd starts a compose operation and returns the first streamed line as a message.
 // Subsequent lines are self-scheduled via readNextComposeLineCmd.
-func composeStreamCmd(ctx context.Context, project, workDir string, fn func(context.Context, string, string, chan<- string) error) tea.Cmd {
+func composeStreamCmd(ctx context.Context, t controller.ComposeTarget, fn func(context.Context, controller.ComposeTarget, chan<- controller.ComposeLine) error) tea.Cmd {
+	project := t.Project
 	return func() tea.Msg {
-		ch := make(chan string, 64)
-		if err := fn(ctx, project, workDir, ch); err != nil {
+		ch := make(chan controller.ComposeLine, 64)
+		if err := fn(ctx, t, ch); err != nil {
 			return composeDoneMsg{project: project, err: err}
 		}
-		line, ok := <-ch
-		if !ok {
-			return composeDoneMsg{project: project}
-		}
-		return composeOutputMsg{project: project, line: line, ch: ch}
+		return readComposeLine(ch, 0, project)
 	}
 }
 
 // readNextComposeLineCmd reads the next line from an in-progress compose stream.
-func readNextComposeLineCmd(ch <-chan string, project string) tea.Cmd {
+func readNextComposeLineCmd(ch <-chan controller.ComposeLine, op int, project string) tea.Cmd {
 	return func() tea.Msg {
-		line, ok := <-ch
-		if !ok {
-			return composeDoneMsg{project: project}
-		}
-		return composeOutputMsg{project: project, line: line, ch: ch}
+		return readComposeLine(ch, op, project)
 	}
 }
 
-func composeUpCmd(ctx context.Context, project, workDir string) tea.Cmd {
-	return composeStreamCmd(ctx, project, workDir, controller.ComposeUp)
+// readComposeLine waits for the next line of a compose stream; the stream
+// ends when ch is closed or reports the command's exit error.
+func readComposeLine(ch <-chan controller.ComposeLine, op int, project string) tea.Msg {
+	line, ok := <-ch
+	switch {
+	case !ok:
+		return composeDoneMsg{op: op, project: project}
+	case line.Err != nil:
+		return composeDoneMsg{op: op, project: project, err: line.Err}
+	default:
+		return composeOutputMsg{op: op, project: project, line: line.Line, ch: ch}
+	}
+}
+
+func composeUpCmd(ctx context.Context, t controller.ComposeTarget) tea.Cmd {
+	return composeStreamCmd(ctx, t, func(ctx context.Context, t controller.ComposeTarget, ch chan<- controller.ComposeLine) error {
+		return controller.ComposeUp(ctx, t, ch)
+	})
+}
+
+func composeUpBuildCmd(ctx context.Context, t controller.ComposeTarget) tea.Cmd {
+	return composeStreamCmd(ctx, t, controller.ComposeUpBuild)
+}
+
+func composeRecreateCmd(ctx context.Context, t controller.ComposeTarget) tea.Cmd {
+	return composeStreamCmd(ctx, t, controller.ComposeRecreate)
 }
 
-func composeUpBuildCmd(ctx context.Context, project, workDir string) tea.Cmd {
-	return composeStreamCmd(ctx, project, workDir, controller.ComposeUpBuild)
+func composeDownCmd(ctx context.Context, t controller.ComposeTarget) tea.Cmd {
+	return composeStreamCmd(ctx, t, controller.ComposeDown)
 }
 
-func composeRecreateCmd(ctx context.Context, project, workDir string) tea.Cmd {
-	return composeStreamCmd(ctx, project, workDir, controller.ComposeRecreate)
+func composePullCmd(ctx context.Context, t controller.ComposeTarget) tea.Cmd {
+	return composeStreamCmd(ctx, t, func(ctx context.Context, t controller.ComposeTarget, ch chan<- controller.ComposeLine) error {
+		return controller.ComposePull(ctx, t, ch)
+	})
 }
 
-func composeDownCmd(ctx context.Context, project, workDir string) tea.Cmd {
-	return composeStreamCmd(ctx, project, workDir, controller.ComposeDown)
+func composeBuildCmd(ctx context.Context, t controller.ComposeTarget) tea.Cmd {
+	return composeStreamCmd(ctx, t, func(ctx context.Context, t controller.ComposeTarget, ch chan<- controller.ComposeLine) error {
+		return controller.ComposeBuild(ctx, t, ch)
+	})
 }
 
-func composePullCmd(ctx context.Context, project, workDir string) tea.Cmd {
-	return composeStreamCmd(ctx, project, workDir, controller.ComposePull)
+// composeServiceCmd runs a service-scoped compose operation (up, restart,
+// build, pull) on one service of the project.
+func composeServiceCmd(fn func(context.Context, controller.ComposeTarget, chan<- controller.ComposeLine, ...string) error, service string) composeRunFunc {
+	return func(ctx context.Context, t controller.ComposeTarget) tea.Cmd {
+		return composeStreamCmd(ctx, t, func(ctx context.Context, t controller.ComposeTarget, ch chan<- controller.ComposeLine) error {
+			return fn(ctx, t, ch, service)
+		})
+	}
 }
 
-func composeBuildCmd(ctx context.Context, project, workDir string) tea.Cmd {
-	return composeStreamCmd(ctx, project, workDir, controller.ComposeBuild)
+// composeScaleCmd runs replicas containers of service.
+func composeScaleCmd(service string, replicas int) composeRunFunc {
+	return func(ctx context.Context, t controller.ComposeTarget) tea.Cmd {
+		return composeStreamCmd(ctx, t, func(ctx context.Context, t controller.ComposeTarget, ch chan<- controller.ComposeLine) error {
+			return controller.ComposeScale(ctx, t, ch, service, replicas)
+		})
+	}
 }
 
-// ── Exec shell ────────────────────────────────────────────────────────────────
+// ── Top ───────────────────────────────────────────────────────────────────────
 
-func execShellCmd(containerID string) tea.Cmd {
-	cmd := controller.ExecShell(containerID)
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+func fetchTopCmd(id string) tea.Cmd {
+	return func() tea.Msg {
+		procs, err := controller.GetContainerProcesses(id)
+		if err != nil {
+			return statusMsg("Top failed: " + err.Error())
+		}
+		return topMsg{id: id, processes: procs}
+	}
+}
+
+func signalProcessCmd(id, pid, signal string) tea.Cmd {
+	return func() tea.Msg {
+		if err := controller.SignalProcess(id, pid, signal); err != nil {
+			return statusMsg("Signal failed: " + err.Error())
+		}
+		return statusMsg(fmt.Sprintf("Sent SIG%s to process %s.", signal, pid))
+	}
+}
+
+// ── Exec ──────────────────────────────────────────────────────────────────────
+
+func detectShellCmd(id, name, image string) tea.Cmd {
+	return func() tea.Msg {
+		return execShellDetectedMsg{id: id, name: name, image: image, shell: controller.DetectShell(id)}
+	}
+}
+
+// startRunCommandCmd runs a one-off command and returns the first streamed line
+// as a message; later lines are self-scheduled via waitForExecLineCmd.
+func startRunCommandCmd(containerID string, cfg controller.ExecConfig) (<-chan controller.ExecLine, context.CancelFunc, tea.Cmd) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan controller.ExecLine, 500)
+	done := make(chan execDoneMsg, 1)
+	go func() {
+		code, err := controller.StreamExec(ctx, containerID, cfg, ch)
+		done <- execDoneMsg{code: code, err: err}
+	}()
+	return ch, cancel, waitForExecLineCmd(ch, done)
+}
+
+func waitForExecLineCmd(ch <-chan controller.ExecLine, done <-chan execDoneMsg) tea.Cmd {
+	return func() tea.Msg {
+		line, ok := <-ch
+		if !ok {
+			result := <-done
+			result.ch = ch
+			return result
+		}
+		return execOutputMsg{line: line, ch: ch, done: done}
+	}
+}
+
+func execCmd(containerID string, cfg controller.ExecConfig) tea.Cmd {
+	session := controller.NewExecSession(containerID, cfg)
+	return tea.Exec(session, func(err error) tea.Msg {
 		if err != nil {
 			return statusMsg("Exec ended: " + err.Error())
 		}
+		if code := session.ExitCode(); code != 0 {
+			return statusMsg(fmt.Sprintf("Exec session ended with exit code %d.", code))
+		}
 		return statusMsg("Exec session ended.")
 	})
 }
@@ -0,0 +1,129 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	"charm.land/bubbles/v2/table"
+	tea "charm.land/bubbletea/v2"
+	"github.com/rluders/berth/internal/controller"
+)
+
+// openDrift shows how the containers of a compose project differ from its
+// files, comparing them in the background.
+func (m Model) openDrift(t controller.ComposeTarget) (Model, tea.Cmd) {
+	if m.driftProject != t.Project {
+		m.drift = nil
+		m.driftTable.SetRows(nil)
+	}
+	m.driftProject = t.Project
+	m.pushView(DriftView)
+	m.driftTable.GotoTop()
+	return m.refreshDrift()
+}
+
+// refreshDrift compares the project in the drift view again.
+func (m Model) refreshDrift() (Model, tea.Cmd) {
+	m.statusMessage = fmt.Sprintf("Comparing %s with its compose files...", m.driftProject)
+	m.showSpinner = true
+	return m, tea.Batch(composeDriftCmd(m.composeTarget(m.driftProject)), m.spinner.Tick)
+}
+
+// driftSummary tells whether up has anything to do.
+func driftSummary(project string, drift []controller.ServiceDrift) string {
+	pending, orphans := 0, 0
+	for _, d := range drift {
+		switch d.Status {
+		case controller.DriftUpToDate:
+		case controller.DriftOrphan:
+			orphans++
+		default:
+			pending++
+		}
+	}
+	var sb strings.Builder
+	if pending == 0 {
+		fmt.Fprintf(&sb, "%s is up to date with its compose files", project)
+	} else {
+		fmt.Fprintf(&sb, "%s: %d of %d would change on up", project, pending, len(drift)-orphans)
+	}
+	if orphans > 0 {
+		fmt.Fprintf(&sb, ", %d orphaned", orphans)
+	}
+	return sb.String() + "."
+}
+
+// refreshDriftRows lists each difference on its own row, under the service
+// and container it belongs to. Secret-looking environment values are masked.
+func (m *Model) refreshDriftRows() {
+	drift := slices.Clone(m.drift)
+	if s, ok := m.tableSortOf(DriftView); ok {
+		sortItems(drift, s, compareDrift)
+	}
+	var rows []table.Row
+	for _, d := range drift {
+		container := d.Container
+		if container == "" {
+			container = "-"
+		}
+		if len(d.Changes) == 0 {
+			rows = append(rows, table.Row{d.Service, container, d.Status, "", "", ""})
+			continue
+		}
+		for i, c := range d.Changes {
+			want, got := orDash(c.Want), orDash(c.Got)
+			if name, ok := strings.CutPrefix(c.Field, "env "); ok && isSecretKey(name) {
+				want, got = maskValue(c.Want), maskValue(c.Got)
+			}
+			if i == 0 {
+				rows = append(rows, table.Row{d.Service, container, d.Status, c.Field, want, got})
+			} else {
+				rows = append(rows, table.Row{"", "", "", c.Field, want, got})
+			}
+		}
+	}
+	m.driftTable.SetRows(rows)
+	if m.driftTable.Cursor() >= len(rows) {
+		m.driftTable.GotoTop()
+	}
+}
+
+// orDash shows a missing value as "-".
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// maskValue hides a secret value, keeping "-" for a missing one.
+func maskValue(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return "••••••••"
+}
+
+func (m Model) handleDriftKey(msg tea.KeyPressMsg) (Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, Keys.Drift.Refresh):
+		return m.refreshDrift()
+	case key.Matches(msg, Keys.Drift.Up):
+		project := m.driftProject
+		m.statusMessage = fmt.Sprintf("%s up -d  [%s]", controller.ComposeToolName(), project)
+		cmd := m.startComposeOp(m.composeTarget(project), "up -d", composeUpCmd)
+		return m, tea.Batch(cmd, m.spinner.Tick)
+	case key.Matches(msg, Keys.Table.Sort):
+		m.cycleSort()
+		return m, nil
+	case key.Matches(msg, Keys.Table.Reverse):
+		m.reverseSort()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.driftTable, cmd = m.driftTable.Update(msg)
+	return m, cmd
+}
@@ -0,0 +1,85 @@
+package tui
+
+import (
+	"fmt"
+	"testing"
+
+	"charm.land/bubbles/v2/table"
+	tea "charm.land/bubbletea/v2"
+	"github.com/rluders/berth/internal/controller"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContainersView_openDrift(t *testing.T) {
+	m := ghostModel()
+	m.moveContainerCursor(0)
+
+	m, cmd := updateModel(t, m, tea.KeyPressMsg{Code: 'D', Text: "D"})
+
+	require.Equal(t, DriftView, m.currentView)
+	assert.Equal(t, "myapp", m.driftProject)
+	assert.NotNil(t, cmd, "the comparison runs in the background")
+	assert.True(t, m.showSpinner)
+}
+
+func TestHandleComposeDriftMsg(t *testing.T) {
+	m := InitialModel()
+	m.driftProject = "shop"
+	drift := []controller.ServiceDrift{
+		{Service: "api", Container: "shop-api-1", Status: controller.DriftUpToDate},
+		{Service: "db", Status: controller.DriftNotCreated},
+		{Service: "web", Container: "shop-web-1", Status: controller.DriftOutdated, Changes: []controller.DriftChange{
+			{Field: "image", Want: "nginx:1.27", Got: "nginx:1.25"},
+			{Field: "env API_TOKEN", Want: "new", Got: "old"},
+			{Field: "port", Want: "8080:80/tcp"},
+		}},
+		{Service: "cron", Container: "shop-cron-1", Status: controller.DriftOrphan},
+	}
+
+	m, _ = updateModel(t, m, composeDriftMsg{project: "blog", drift: drift})
+	assert.Empty(t, m.driftTable.Rows(), "the drift of another project is ignored")
+
+	m, _ = updateModel(t, m, composeDriftMsg{project: "shop", drift: drift})
+
+	assert.Equal(t, "shop: 2 of 3 would change on up, 1 orphaned.", m.statusMessage)
+	rows := m.driftTable.Rows()
+	require.Len(t, rows, 6)
+	assert.Equal(t, table.Row{"api", "shop-api-1", "up to date", "", "", ""}, rows[0])
+	assert.Equal(t, table.Row{"db", "-", "not created", "", "", ""}, rows[1])
+	assert.Equal(t, table.Row{"web", "shop-web-1", "outdated", "image", "nginx:1.27", "nginx:1.25"}, rows[2])
+	assert.Equal(t, table.Row{"", "", "", "env API_TOKEN", "••••••••", "••••••••"}, rows[3], "secrets are masked")
+	assert.Equal(t, table.Row{"", "", "", "port", "8080:80/tcp", "-"}, rows[4])
+}
+
+func TestHandleComposeDriftMsg_skipsWithoutJSONConfig(t *testing.T) {
+	m := InitialModel()
+	m.driftProject = "shop"
+	pinComposeTool(t, "podman-compose")
+
+	err := fmt.Errorf("%w: podman-compose", controller.ErrNoJSONConfig)
+	m, _ = updateModel(t, m, composeDriftMsg{project: "shop", err: err})
+
+	assert.Equal(t, "Drift detection skipped: podman-compose cannot print the project's configuration as JSON.", m.statusMessage)
+	assert.Empty(t, m.driftTable.Rows())
+}
+
+func TestDriftSummary_upToDate(t *testing.T) {
+	assert.Equal(t, "shop is up to date with its compose files.",
+		driftSummary("shop", []controller.ServiceDrift{{Service: "web", Status: controller.DriftUpToDate}}))
+}
+
+func TestDriftView_up(t *testing.T) {
+	m := InitialModel()
+	pinComposeTool(t, "docker compose")
+	m.driftProject = "shop"
+	m.currentView = DriftView
+
+	m, cmd := updateModel(t, m, tea.KeyPressMsg{Code: 'u', Text: "u"})
+	t.Cleanup(func() { m.composeCancel() })
+
+	require.NotNil(t, cmd)
+	require.Len(t, m.composeOps, 1)
+	assert.Equal(t, "shop", m.composeOps[0].target.Project)
+	assert.Equal(t, "docker compose up -d  [shop]", m.statusMessage)
+}
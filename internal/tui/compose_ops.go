@@ -0,0 +1,302 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"charm.land/bubbles/v2/key"
+	"charm.land/bubbles/v2/table"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/rluders/berth/internal/controller"
+)
+
+const (
+	composeHistorySize = 50    // operations kept in the history
+	composeOpMaxLines  = 10000 // output lines kept per operation
+)
+
+// composeRunFunc starts a compose command against a project.
+type composeRunFunc func(context.Context, controller.ComposeTarget) tea.Cmd
+
+// composeOp is a compose command run from Berth, kept in the history with its
+// output so it can be read and run again after it finished.
+type composeOp struct {
+	id        int
+	target    controller.ComposeTarget
+	command   string // compose arguments after the project, e.g. "up -d web"
+	run       composeRunFunc
+	started   time.Time
+	ended     time.Time // zero while running
+	err       error
+	cancelled bool // superseded by a later operation
+	output    []string
+}
+
+// status describes how the operation ended.
+func (op composeOp) status() string {
+	switch {
+	case op.ended.IsZero():
+		return "running"
+	case op.cancelled:
+		return "cancelled"
+	case op.err != nil:
+		var exit interface{ ExitCode() int }
+		if errors.As(op.err, &exit) {
+			return fmt.Sprintf("exit %d", exit.ExitCode())
+		}
+		return "failed"
+	default:
+		return "exit 0"
+	}
+}
+
+// startComposeOp cancels any running compose op, records a new one in the
+// history and returns the cmd that runs it.
+func (m *Model) startComposeOp(t controller.ComposeTarget, command string, run composeRunFunc) tea.Cmd {
+	if m.composeCancel != nil {
+		m.composeCancel()
+		if op := m.findComposeOp(m.composeRunningOp); op != nil && op.ended.IsZero() {
+			op.cancelled = true
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.composeCancel = cancel
+	m.showSpinner = true
+
+	m.composeOpSeq++
+	id := m.composeOpSeq
+	m.composeRunningOp = id
+	m.composeOps = append(m.composeOps, composeOp{
+		id:      id,
+		target:  t,
+		command: command,
+		run:     run,
+		started: time.Now(),
+	})
+	if len(m.composeOps) > composeHistorySize {
+		m.composeOps = m.composeOps[len(m.composeOps)-composeHistorySize:]
+	}
+	m.refreshComposeOps()
+	return tagComposeOp(id, run(ctx, t))
+}
+
+// confirmComposeOp asks before running a destructive compose command. The
+// operation only starts, and enters the history, once confirmed.
+func (m *Model) confirmComposeOp(title string, t controller.ComposeTarget, command string, run composeRunFunc) {
+	m.modal = NewConfirmModal(
+		title,
+		fmt.Sprintf("%s %s\nProject: %s", controller.ComposeToolName(), command, t.Project),
+		func() tea.Msg { return composeStartMsg{target: t, command: command, run: run} },
+	)
+}
+
+// tagComposeOp marks the first message of an operation's stream with its id;
+// later messages carry it along.
+func tagComposeOp(id int, cmd tea.Cmd) tea.Cmd {
+	return func() tea.Msg {
+		switch msg := cmd().(type) {
+		case composeOutputMsg:
+			msg.op = id
+			return msg
+		case composeDoneMsg:
+			msg.op = id
+			return msg
+		default:
+			return msg
+		}
+	}
+}
+
+// findComposeOp returns the operation with id, or nil once it left the history.
+func (m *Model) findComposeOp(id int) *composeOp {
+	for i := range m.composeOps {
+		if m.composeOps[i].id == id {
+			return &m.composeOps[i]
+		}
+	}
+	return nil
+}
+
+// openComposeOps shows the compose history of project, or of every project
+// when empty.
+func (m Model) openComposeOps(project string) (Model, tea.Cmd) {
+	m.composeOpsProject = project
+	m.pushView(ComposeOpsView)
+	m.refreshComposeOps()
+	m.composeOpsTable.GotoTop()
+	return m, nil
+}
+
+// composeOpsTitle names the projects the history lists.
+func (m Model) composeOpsTitle() string {
+	if m.composeOpsProject == "" {
+		return "all projects"
+	}
+	return m.composeOpsProject
+}
+
+// visibleComposeOps returns the operations listed in the history view, most
+// recent first unless the view is sorted.
+func (m Model) visibleComposeOps() []composeOp {
+	var ops []composeOp
+	for i := len(m.composeOps) - 1; i >= 0; i-- {
+		op := m.composeOps[i]
+		if m.composeOpsProject == "" || op.target.Project == m.composeOpsProject {
+			ops = append(ops, op)
+		}
+	}
+	if s, ok := m.tableSortOf(ComposeOpsView); ok {
+		sortItems(ops, s, compareComposeOps)
+	}
+	return ops
+}
+
+// refreshComposeOps rebuilds the history table rows.
+func (m *Model) refreshComposeOps() {
+	ops := m.visibleComposeOps()
+	rows := make([]table.Row, len(ops))
+	for i, op := range ops {
+		duration := "-"
+		if !op.ended.IsZero() {
+			duration = op.ended.Sub(op.started).Round(100 * time.Millisecond).String()
+		}
+		rows[i] = table.Row{
+			op.target.Project,
+			op.command,
+			op.started.Format(time.TimeOnly),
+			duration,
+			op.status(),
+		}
+	}
+	m.composeOpsTable.SetRows(rows)
+	if m.composeOpsTable.Cursor() >= len(rows) {
+		m.composeOpsTable.GotoTop()
+	}
+}
+
+// selectedComposeOp returns the operation under the history cursor.
+func (m Model) selectedComposeOp() (composeOp, bool) {
+	ops := m.visibleComposeOps()
+	i := m.composeOpsTable.Cursor()
+	if i < 0 || i >= len(ops) {
+		return composeOp{}, false
+	}
+	return ops[i], true
+}
+
+// openComposeOutput shows the captured output of an operation.
+func (m Model) openComposeOutput(id int) (Model, tea.Cmd) {
+	m.composeOutputOp = id
+	m.pushView(ComposeOutputView)
+	m.refreshComposeOutput()
+	m.composeOutputVP.GotoBottom()
+	return m, nil
+}
+
+// refreshComposeOutput renders the output of the operation in view, keeping
+// the tail in sight when it was already there.
+func (m *Model) refreshComposeOutput() {
+	op := m.findComposeOp(m.composeOutputOp)
+	if op == nil {
+		m.composeOutputVP.SetContent("")
+		return
+	}
+	atBottom := m.composeOutputVP.AtBottom()
+	m.composeOutputVP.SetContent(strings.Join(op.output, "\n"))
+	if atBottom {
+		m.composeOutputVP.GotoBottom()
+	}
+}
+
+// rerunComposeOp runs a past operation again with the same target.
+func (m Model) rerunComposeOp(op composeOp) (Model, tea.Cmd) {
+	m.statusMessage = fmt.Sprintf("%s %s  [%s]", controller.ComposeToolName(), op.command, op.target.Project)
+	cmd := m.startComposeOp(op.target, op.command, op.run)
+	return m, tea.Batch(cmd, m.spinner.Tick)
+}
+
+func (m Model) handleComposeOpsKey(msg tea.KeyPressMsg) (Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, Keys.ComposeOps.Output):
+		if op, ok := m.selectedComposeOp(); ok {
+			return m.openComposeOutput(op.id)
+		}
+		return m, nil
+	case key.Matches(msg, Keys.ComposeOps.Rerun):
+		if op, ok := m.selectedComposeOp(); ok {
+			return m.rerunComposeOp(op)
+		}
+		return m, nil
+	case key.Matches(msg, Keys.ComposeOps.All):
+		m.composeOpsProject = ""
+		m.refreshComposeOps()
+		return m, nil
+	case key.Matches(msg, Keys.Table.Sort):
+		m.cycleSort()
+		return m, nil
+	case key.Matches(msg, Keys.Table.Reverse):
+		m.reverseSort()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.composeOpsTable, cmd = m.composeOpsTable.Update(msg)
+	return m, cmd
+}
+
+func (m Model) handleComposeOutputKey(msg tea.KeyPressMsg) (Model, tea.Cmd) {
+	if key.Matches(msg, Keys.ComposeOps.Rerun) {
+		op := m.findComposeOp(m.composeOutputOp)
+		if op == nil {
+			return m, nil
+		}
+		m, cmd := m.rerunComposeOp(*op)
+		m.composeOutputOp = m.composeRunningOp
+		m.refreshComposeOutput()
+		return m, cmd
+	}
+
+	var cmd tea.Cmd
+	m.composeOutputVP, cmd = m.composeOutputVP.Update(msg)
+	return m, cmd
+}
+
+// renderComposeOutputView renders an operation's output under its command and
+// status.
+func (m Model) renderComposeOutputView() string {
+	th := currentTheme
+	op := m.findComposeOp(m.composeOutputOp)
+	if op == nil {
+		return lipgloss.NewStyle().Padding(0, 1).Render("This operation is no longer in the history.")
+	}
+
+	titleBar := lipgloss.NewStyle().
+		Padding(0, 1).
+		Bold(true).
+		Render(fmt.Sprintf("$ %s -p %s %s", controller.ComposeToolName(), op.target.Project, op.command))
+
+	var badge string
+	switch status := op.status(); status {
+	case "running":
+		badge = th.LogFollowStyle.Render("▶ RUNNING")
+	case "cancelled":
+		badge = th.LogPausedStyle.Render("⏹ CANCELLED")
+	case "exit 0":
+		badge = th.LogFollowStyle.Render("✓ exit 0")
+	default:
+		badge = th.LogErrorStyle.Render("✗ " + status + ": " + op.err.Error())
+	}
+	if len(op.output) >= composeOpMaxLines {
+		badge += th.LogPausedStyle.Render(fmt.Sprintf("  first lines dropped, last %d kept", composeOpMaxLines))
+	}
+
+	indicator := lipgloss.NewStyle().
+		Padding(0, 1).
+		Render(badge)
+
+	return lipgloss.JoinVertical(lipgloss.Left, titleBar, indicator, m.composeOutputVP.View())
+}
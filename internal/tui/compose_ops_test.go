@@ -0,0 +1,142 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/rluders/berth/internal/controller"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exitError mimics *exec.ExitError.
+type exitError int
+
+func (e exitError) Error() string { return "exit status" }
+func (e exitError) ExitCode() int { return int(e) }
+
+// fakeComposeRun records the targets it was run against instead of running
+// compose.
+func fakeComposeRun(runs *[]controller.ComposeTarget) composeRunFunc {
+	return func(_ context.Context, t controller.ComposeTarget) tea.Cmd {
+		*runs = append(*runs, t)
+		return func() tea.Msg { return composeDoneMsg{project: t.Project} }
+	}
+}
+
+// pinComposeTool makes compose commands display as tool, whatever the
+// configuration or installed tools.
+func pinComposeTool(t *testing.T, tool string) {
+	t.Helper()
+	controller.SetComposeTool(tool)
+	t.Cleanup(func() { controller.SetComposeTool("") })
+}
+
+func TestComposeOp_status(t *testing.T) {
+	assert.Equal(t, "running", composeOp{}.status())
+
+	ended := composeOp{}
+	ended.ended = ended.started.Add(1)
+	assert.Equal(t, "exit 0", ended.status())
+
+	ended.err = exitError(2)
+	assert.Equal(t, "exit 2", ended.status())
+
+	ended.err = errors.New("no docker")
+	assert.Equal(t, "failed", ended.status())
+
+	ended.cancelled = true
+	assert.Equal(t, "cancelled", ended.status())
+}
+
+func TestStartComposeOp_recordsHistory(t *testing.T) {
+	m := InitialModel()
+	var runs []controller.ComposeTarget
+	shop := controller.ComposeTarget{Project: "shop"}
+
+	cmd := m.startComposeOp(shop, "pull", fakeComposeRun(&runs))
+	msg := cmd()
+	assert.Equal(t, composeDoneMsg{op: 1, project: "shop"}, msg, "messages carry the operation id")
+
+	m.startComposeOp(shop, "up -d", fakeComposeRun(&runs))
+	require.Len(t, m.composeOps, 2)
+	assert.True(t, m.composeOps[0].cancelled, "a new operation supersedes the running one")
+	assert.Equal(t, 2, m.composeRunningOp)
+
+	m.showSpinner = true
+	m, _ = updateModel(t, m, composeDoneMsg{op: 1, project: "shop"})
+	assert.True(t, m.showSpinner, "the superseded operation does not end the running one")
+	assert.Equal(t, "cancelled", m.composeOps[0].status())
+
+	m, _ = updateModel(t, m, composeDoneMsg{op: 2, project: "shop", err: exitError(1)})
+	assert.False(t, m.showSpinner)
+	assert.Equal(t, "exit 1", m.composeOps[1].status())
+}
+
+func TestStartComposeOp_keepsRecentHistory(t *testing.T) {
+	m := InitialModel()
+	var runs []controller.ComposeTarget
+	for range composeHistorySize + 5 {
+		m.startComposeOp(controller.ComposeTarget{Project: "shop"}, "pull", fakeComposeRun(&runs))
+	}
+	require.Len(t, m.composeOps, composeHistorySize)
+	assert.Equal(t, 6, m.composeOps[0].id)
+}
+
+func TestComposeStartMsg_startsConfirmedOperation(t *testing.T) {
+	m := InitialModel()
+	pinComposeTool(t, "docker compose")
+	var runs []controller.ComposeTarget
+
+	m, cmd := updateModel(t, m, composeStartMsg{
+		target:  controller.ComposeTarget{Project: "shop"},
+		command: "down",
+		run:     fakeComposeRun(&runs),
+	})
+
+	require.NotNil(t, cmd)
+	require.Len(t, m.composeOps, 1)
+	assert.Equal(t, "down", m.composeOps[0].command)
+	assert.Equal(t, "docker compose down  [shop]", m.statusMessage)
+}
+
+func TestComposeOpsView_outputAndRerun(t *testing.T) {
+	m := InitialModel()
+	pinComposeTool(t, "podman-compose")
+	m.containers = []controller.Container{makeContainer("a", "web", "nginx", "running", "shop")}
+	m.recomputeRows()
+	var runs []controller.ComposeTarget
+	shop := controller.ComposeTarget{Project: "shop", WorkDir: "/src/shop"}
+	m.startComposeOp(controller.ComposeTarget{Project: "blog"}, "pull", fakeComposeRun(&runs))
+	m.startComposeOp(shop, "build", fakeComposeRun(&runs))
+	m.composeOps[1].output = []string{"Building web", "Built"}
+	m, _ = updateModel(t, m, composeDoneMsg{op: 2, project: "shop"})
+
+	m = typeKeys(t, m, "h")
+	require.Equal(t, ComposeOpsView, m.currentView)
+	assert.Equal(t, "shop", m.composeOpsTitle(), "opened from a project lists only its operations")
+	require.Len(t, m.composeOpsTable.Rows(), 1)
+	assert.Equal(t, "build", m.composeOpsTable.Rows()[0][1])
+	assert.Equal(t, "exit 0", m.composeOpsTable.Rows()[0][4])
+
+	m, _ = updateModel(t, m, tea.KeyPressMsg{Code: tea.KeyEnter})
+	require.Equal(t, ComposeOutputView, m.currentView)
+	m.composeOutputVP.SetWidth(80)
+	m.composeOutputVP.SetHeight(10)
+	m.refreshComposeOutput()
+	view := ansi.Strip(m.renderComposeOutputView())
+	assert.Contains(t, view, "$ podman-compose -p shop build")
+	assert.Contains(t, view, "Built")
+
+	m, cmd := updateModel(t, m, tea.KeyPressMsg{Code: 'r', Text: "r"})
+	require.NotNil(t, cmd)
+	assert.Equal(t, []controller.ComposeTarget{{Project: "blog"}, shop, shop}, runs)
+	assert.Equal(t, 3, m.composeOutputOp, "the output view follows the new run")
+
+	m, _ = updateModel(t, m, tea.KeyPressMsg{Code: tea.KeyEscape})
+	m = typeKeys(t, m, "a")
+	assert.Len(t, m.composeOpsTable.Rows(), 3)
+}
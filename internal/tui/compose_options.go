@@ -0,0 +1,97 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/rluders/berth/internal/controller"
+)
+
+// composeOptions are the profiles and env files chosen for a compose project.
+type composeOptions struct {
+	Profiles []string `json:"profiles,omitempty"`
+	// EnvFiles are read after those the project was started with.
+	EnvFiles []string `json:"envFiles,omitempty"`
+}
+
+// apply returns t with the options' profiles enabled and env files added.
+// Compose stops reading the project's .env once any env file is given, so
+// it is listed first when t has no env files of its own.
+func (o composeOptions) apply(t controller.ComposeTarget) controller.ComposeTarget {
+	t.Profiles = slices.Clone(o.Profiles)
+	t.EnvFiles = slices.Clone(t.EnvFiles)
+	if len(t.EnvFiles) == 0 && len(o.EnvFiles) > 0 {
+		if dotEnv := filepath.Join(t.WorkDir, ".env"); fileExists(dotEnv) {
+			t.EnvFiles = append(t.EnvFiles, dotEnv)
+		}
+	}
+	for _, f := range o.EnvFiles {
+		if !slices.Contains(t.EnvFiles, f) {
+			t.EnvFiles = append(t.EnvFiles, f)
+		}
+	}
+	return t
+}
+
+// fileExists reports whether path is a regular file.
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
+
+// splitList parses a comma-separated list, dropping empty items.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// composeProfiles returns the profiles the services of project use, sorted.
+func (m Model) composeProfiles(project string) []string {
+	var profiles []string
+	for _, svc := range m.composeProjects[project].Services {
+		for _, p := range svc.Profiles {
+			if !slices.Contains(profiles, p) {
+				profiles = append(profiles, p)
+			}
+		}
+	}
+	sort.Strings(profiles)
+	return profiles
+}
+
+// newComposeOptionsForm asks for the profiles and extra env files of project,
+// remembers them and runs up with them.
+func newComposeOptionsForm(project string, opts composeOptions, profiles []string) *Form {
+	profilesLabel := "Profiles"
+	if len(profiles) > 0 {
+		profilesLabel = fmt.Sprintf("Profiles (%s)", strings.Join(profiles, ", "))
+	}
+	return NewForm(
+		fmt.Sprintf("Compose up  %s", project),
+		func(m Model, values []string) (Model, tea.Cmd) {
+			opts := composeOptions{Profiles: splitList(values[0]), EnvFiles: splitList(values[1])}
+			if len(opts.Profiles) == 0 && len(opts.EnvFiles) == 0 {
+				delete(m.composeOptions, project)
+			} else {
+				m.composeOptions[project] = opts
+			}
+			m.persistState()
+
+			m.statusMessage = fmt.Sprintf("%s up -d  [%s]", controller.ComposeToolName(), project)
+			cmd := m.startComposeOp(m.composeTarget(project), "up -d", composeUpCmd)
+			return m, tea.Batch(cmd, m.spinner.Tick)
+		},
+		NewFormField(profilesLabel, strings.Join(opts.Profiles, ", "), "none"),
+		NewFormField("Env files", strings.Join(opts.EnvFiles, ", "), ".env.local"),
+	)
+}
@@ -0,0 +1,97 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/rluders/berth/internal/controller"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComposeOptions_apply(t *testing.T) {
+	labels := controller.ComposeTarget{Project: "shop", EnvFiles: []string{".env"}}
+	opts := composeOptions{Profiles: []string{"debug"}, EnvFiles: []string{".env", ".env.local"}}
+
+	got := opts.apply(labels)
+
+	assert.Equal(t, []string{".env", ".env.local"}, got.EnvFiles, "extra env files follow those of the labels once")
+	assert.Equal(t, []string{"debug"}, got.Profiles)
+	assert.Equal(t, []string{".env"}, labels.EnvFiles, "the original target is left alone")
+}
+
+func TestComposeOptions_applyKeepsDefaultDotEnv(t *testing.T) {
+	dir := t.TempDir()
+	opts := composeOptions{EnvFiles: []string{".env.local"}}
+
+	got := opts.apply(controller.ComposeTarget{Project: "shop", WorkDir: dir})
+	assert.Equal(t, []string{".env.local"}, got.EnvFiles, "no .env to keep")
+
+	dotEnv := filepath.Join(dir, ".env")
+	require.NoError(t, os.WriteFile(dotEnv, []byte("TAG=1\n"), 0o600))
+	got = opts.apply(controller.ComposeTarget{Project: "shop", WorkDir: dir})
+	assert.Equal(t, []string{dotEnv, ".env.local"}, got.EnvFiles, "the default .env is still read first")
+
+	got = composeOptions{}.apply(controller.ComposeTarget{Project: "shop", WorkDir: dir})
+	assert.Empty(t, got.EnvFiles, "without extra files compose finds .env itself")
+}
+
+func TestBuildCommandPreview_matchesComposeArgs(t *testing.T) {
+	m := ghostModel()
+	pinComposeTool(t, "docker compose")
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".env"), []byte("TAG=1\n"), 0o600))
+	p := m.composeProjects["myapp"]
+	p.WorkDir, p.Files = dir, []string{"compose.yml"}
+	m.composeProjects["myapp"] = p
+	m.composeOptions["myapp"] = composeOptions{EnvFiles: []string{".env.local"}}
+	m.moveContainerCursor(0)
+
+	want := "docker compose -p myapp -f compose.yml --env-file " + filepath.Join(dir, ".env") + " --env-file .env.local up -d"
+	assert.Equal(t, want, m.BuildCommandPreview(), "the project's .env stays ahead of the extra env file")
+
+	labelled := InitialModel()
+	web := makeContainer("a", "web", "nginx", "running", "shop")
+	web.Labels["com.docker.compose.project.environment_file"] = "/src/shop/prod.env"
+	labelled.containers = []controller.Container{web}
+	labelled.recomputeRows()
+	labelled.moveContainerCursor(0)
+	assert.Equal(t, "docker compose -p shop --env-file /src/shop/prod.env up -d", labelled.BuildCommandPreview())
+}
+
+func TestContainersView_upWithOptions(t *testing.T) {
+	useStateFile(t)
+	t.Setenv("HOME", t.TempDir())
+
+	m := ghostModel()
+	pinComposeTool(t, "docker compose")
+	p := m.composeProjects["myapp"]
+	p.Services[0].Profiles = []string{"debug", "admin"}
+	m.composeProjects["myapp"] = p
+	m.moveContainerCursor(0)
+
+	m = typeKeys(t, m, "E")
+	require.NotNil(t, m.form)
+	assert.Equal(t, "Profiles (admin, debug)", m.form.Fields[0].Label)
+
+	m.form.Fields[0].Input.SetValue("debug")
+	m.form.Fields[1].Input.SetValue(".env.local, ")
+	m, cmd := updateModel(t, m, tea.KeyPressMsg{Code: tea.KeyEnter})
+	t.Cleanup(func() { m.composeCancel() })
+
+	require.NotNil(t, cmd)
+	want := composeOptions{Profiles: []string{"debug"}, EnvFiles: []string{".env.local"}}
+	assert.Equal(t, want, m.composeOptions["myapp"])
+	assert.Equal(t, m.composeOptions, loadState().ComposeOptions, "the choice is remembered")
+	require.Len(t, m.composeOps, 1)
+	assert.Equal(t, []string{"debug"}, m.composeOps[0].target.Profiles)
+	assert.Equal(t, []string{".env.local"}, m.composeOps[0].target.EnvFiles)
+	assert.Equal(t, "docker compose -p myapp --env-file .env.local --profile debug up -d", m.BuildCommandPreview())
+
+	m.moveContainerCursor(2)
+	m = typeKeys(t, m, "u")
+	require.Len(t, m.composeOps, 2)
+	assert.Equal(t, []string{"debug"}, m.composeOps[1].target.Profiles, "later operations use the choice too")
+}
@@ -0,0 +1,113 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/rluders/berth/internal/controller"
+)
+
+// serviceContainers returns the containers of a compose service, in list order.
+func (m Model) serviceContainers(project, service string) []controller.Container {
+	var result []controller.Container
+	for _, c := range m.containers {
+		if p, s, ok := controller.ComposeServiceOf(c); ok && p == project && s == service {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// openServiceMenu opens the service actions of the compose container c.
+func (m Model) openServiceMenu(c controller.Container) (Model, tea.Cmd) {
+	project, service, ok := controller.ComposeServiceOf(c)
+	if !ok {
+		m.statusMessage = fmt.Sprintf("%s is not part of a compose service", c.Names)
+		return m, nil
+	}
+	m.quickMenu = NewServiceQuickMenu(project, service)
+	return m, nil
+}
+
+// runServiceOp starts a compose operation on one service of project.
+func (m Model) runServiceOp(project, command string, fn composeRunFunc) (Model, tea.Cmd) {
+	m.statusMessage = fmt.Sprintf("%s %s  [%s]", controller.ComposeToolName(), command, project)
+	cmd := m.startComposeOp(m.composeTarget(project), command, fn)
+	return m, tea.Batch(cmd, m.spinner.Tick)
+}
+
+// NewServiceQuickMenu builds the actions menu for a compose service.
+func NewServiceQuickMenu(project, service string) *QuickMenu {
+	return &QuickMenu{
+		Title: fmt.Sprintf("Service  %s/%s", project, service),
+		Items: []QuickMenuItem{
+			{
+				Label: "Up (create and start)",
+				Key:   "u",
+				Action: func(m Model) (Model, tea.Cmd) {
+					return m.runServiceOp(project, "up -d "+service, composeServiceCmd(controller.ComposeUp, service))
+				},
+			},
+			{
+				Label: "Restart",
+				Key:   "r",
+				Action: func(m Model) (Model, tea.Cmd) {
+					return m.runServiceOp(project, "restart "+service, composeServiceCmd(controller.ComposeRestart, service))
+				},
+			},
+			{
+				Label: "Build",
+				Key:   "b",
+				Action: func(m Model) (Model, tea.Cmd) {
+					return m.runServiceOp(project, "build "+service, composeServiceCmd(controller.ComposeBuild, service))
+				},
+			},
+			{
+				Label: "Pull",
+				Key:   "p",
+				Action: func(m Model) (Model, tea.Cmd) {
+					return m.runServiceOp(project, "pull "+service, composeServiceCmd(controller.ComposePull, service))
+				},
+			},
+			{
+				Label: "Scale",
+				Key:   "n",
+				Action: func(m Model) (Model, tea.Cmd) {
+					m.form = newScaleForm(project, service, len(m.serviceContainers(project, service)))
+					return m, nil
+				},
+			},
+			{
+				Label: "Logs of all replicas",
+				Key:   "l",
+				Action: func(m Model) (Model, tea.Cmd) {
+					containers := m.serviceContainers(project, service)
+					if len(containers) == 0 {
+						m.statusMessage = fmt.Sprintf("%s has no containers", service)
+						return m, nil
+					}
+					return m.openSourceLogs(project+"/"+service, containers)
+				},
+			},
+		},
+	}
+}
+
+// newScaleForm prompts for the number of containers of a service.
+func newScaleForm(project, service string, current int) *Form {
+	return NewForm(
+		fmt.Sprintf("Scale  %s/%s", project, service),
+		func(m Model, values []string) (Model, tea.Cmd) {
+			replicas, err := strconv.Atoi(strings.TrimSpace(values[0]))
+			if err != nil || replicas < 0 {
+				m.statusMessage = fmt.Sprintf("Invalid replica count %q.", values[0])
+				return m, nil
+			}
+			command := fmt.Sprintf("up -d --no-recreate --scale %s=%d %s", service, replicas, service)
+			return m.runServiceOp(project, command, composeScaleCmd(service, replicas))
+		},
+		NewFormField("Replicas", strconv.Itoa(current), "1"),
+	)
+}
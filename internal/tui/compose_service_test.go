@@ -0,0 +1,86 @@
+package tui
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/rluders/berth/internal/controller"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serviceModel() Model {
+	m := InitialModel()
+	replica := func(id, name, service string) controller.Container {
+		c := makeContainer(id, name, "nginx", "running", "shop")
+		c.Labels["com.docker.compose.service"] = service
+		return c
+	}
+	m.containers = []controller.Container{
+		replica("a", "shop-web-1", "web"),
+		replica("b", "shop-web-2", "web"),
+		replica("c", "shop-db-1", "db"),
+		makeContainer("d", "solo", "redis", "running", ""),
+	}
+	m.recomputeRows()
+	return m
+}
+
+func TestContainersView_serviceMenu(t *testing.T) {
+	m := serviceModel()
+	m.moveContainerCursor(1) // shop-web-1
+
+	m = typeKeys(t, m, "a")
+	require.NotNil(t, m.quickMenu)
+	assert.Equal(t, "Service  shop/web", m.quickMenu.Title)
+
+	m, _ = updateModel(t, m, tea.KeyPressMsg{Code: 'l', Text: "l"})
+	t.Cleanup(m.stopLogStream)
+	assert.Equal(t, LogsView, m.currentView)
+	assert.Equal(t, "shop/web", m.logTitle())
+	assert.Equal(t, []string{"shop-web-1", "shop-web-2"}, m.logSourceNames())
+}
+
+func TestContainersView_serviceMenuOutsideCompose(t *testing.T) {
+	m := serviceModel()
+	m.moveContainerCursor(4) // solo
+
+	m = typeKeys(t, m, "a")
+	assert.Nil(t, m.quickMenu)
+	assert.Equal(t, "solo is not part of a compose service", m.statusMessage)
+}
+
+func TestServiceMenu_restart(t *testing.T) {
+	m := serviceModel()
+	pinComposeTool(t, "docker compose")
+	m.moveContainerCursor(3) // shop-db-1
+
+	m = typeKeys(t, m, "a")
+	m, cmd := updateModel(t, m, tea.KeyPressMsg{Code: 'r', Text: "r"})
+	t.Cleanup(func() { m.composeCancel() })
+
+	require.NotNil(t, cmd)
+	assert.Equal(t, "docker compose restart db  [shop]", m.statusMessage)
+}
+
+func TestServiceMenu_scale(t *testing.T) {
+	m := serviceModel()
+	pinComposeTool(t, "docker compose")
+	m.moveContainerCursor(1)
+
+	m = typeKeys(t, m, "an")
+	require.NotNil(t, m.form)
+	assert.Equal(t, "2", m.form.Fields[0].Input.Value(), "defaults to the current replica count")
+
+	m.form.Fields[0].Input.SetValue("many")
+	m, _ = updateModel(t, m, tea.KeyPressMsg{Code: tea.KeyEnter})
+	assert.Equal(t, `Invalid replica count "many".`, m.statusMessage)
+
+	m = typeKeys(t, m, "an")
+	m.form.Fields[0].Input.SetValue("3")
+	m, cmd := updateModel(t, m, tea.KeyPressMsg{Code: tea.KeyEnter})
+	t.Cleanup(func() { m.composeCancel() })
+
+	require.NotNil(t, cmd)
+	assert.Equal(t, "docker compose up -d --no-recreate --scale web=3 web  [shop]", m.statusMessage)
+}
@@ -1,55 +1,182 @@
 package tui
 
 import (
+	"sort"
+	"strings"
+
 	"github.com/rluders/berth/internal/controller"
 )
 
 type RowType int
 
 const (
-	RowTypeGroup     RowType = iota // compose project header row
+	RowTypeGroup     RowType = iota // group header row
 	RowTypeContainer                // individual container row
+	RowTypeGhost                    // compose service defined on disk without a container
 )
 
 // Row is the canonical unit of the visible containers list.
 type Row struct {
 	Type       RowType
-	GroupID    string // project name; empty for standalone containers
+	GroupID    string // group name, e.g. the compose project; empty for ungrouped containers
 	Name       string
-	Collapsed  bool                   // group rows: current collapse state
-	Containers []controller.Container // group rows: member containers
-	Container  *controller.Container  // container rows: the container
+	Collapsed  bool                        // group rows: current collapse state
+	Containers []controller.Container      // group rows: member containers
+	Ghosts     []controller.ComposeService // group rows: services without a container
+	Container  *controller.Container       // container rows: the container
+	Service    *controller.ComposeService  // ghost rows: the service
+}
+
+// GroupKind is what the containers view groups containers by.
+type GroupKind string
+
+const (
+	GroupByCompose GroupKind = "compose" // compose project label
+	GroupByLabel   GroupKind = "label"   // value of a label key
+	GroupByImage   GroupKind = "image"
+	GroupByNetwork GroupKind = "network" // a container is listed under each of its networks
+	GroupByState   GroupKind = "state"
+	GroupByNone    GroupKind = "none"
+)
+
+// Grouping selects how the containers view groups containers.
+type Grouping struct {
+	Kind  GroupKind
+	Label string // label key for GroupByLabel
+}
+
+// String encodes the grouping as "compose", "label:<key>", "image", and so on.
+func (g Grouping) String() string {
+	if g.Kind == GroupByLabel {
+		return string(GroupByLabel) + ":" + g.Label
+	}
+	return string(g.Kind)
 }
 
-type composeGroup struct {
-	project    string
+// ParseGrouping decodes a Grouping.String. Anything else groups by compose
+// project, the default.
+func ParseGrouping(s string) Grouping {
+	if key, ok := strings.CutPrefix(s, string(GroupByLabel)+":"); ok && key != "" {
+		return Grouping{Kind: GroupByLabel, Label: key}
+	}
+	switch kind := GroupKind(s); kind {
+	case GroupByImage, GroupByNetwork, GroupByState, GroupByNone:
+		return Grouping{Kind: kind}
+	}
+	return Grouping{Kind: GroupByCompose}
+}
+
+// Describe names the grouping for display.
+func (g Grouping) Describe() string {
+	switch g.Kind {
+	case GroupByCompose:
+		return "compose project"
+	case GroupByLabel:
+		return "label " + g.Label
+	case GroupByNone:
+		return "nothing"
+	}
+	return string(g.Kind)
+}
+
+// groupsOf returns the groups c belongs to; none leaves it ungrouped.
+func (g Grouping) groupsOf(c controller.Container) []string {
+	var value string
+	switch g.Kind {
+	case GroupByCompose:
+		value = c.Labels["com.docker.compose.project"]
+	case GroupByLabel:
+		value = c.Labels[g.Label]
+	case GroupByImage:
+		value = c.Image
+	case GroupByNetwork:
+		return c.Networks
+	case GroupByState:
+		value = c.State
+	}
+	if value == "" {
+		return nil
+	}
+	return []string{value}
+}
+
+// composeProjectOf returns the compose project of row: its group when
+// grouping by compose project, or else the project of its container.
+func (m Model) composeProjectOf(row Row) string {
+	switch {
+	case row.Type == RowTypeContainer:
+		return row.Container.Labels["com.docker.compose.project"]
+	case m.grouping.Kind == GroupByCompose:
+		return row.GroupID
+	}
+	return ""
+}
+
+// collapsedFor returns the collapsed groups of g in all, adding an empty set
+// when g has none yet.
+func collapsedFor(all map[string]map[string]bool, g Grouping) map[string]bool {
+	collapsed, ok := all[g.String()]
+	if !ok || collapsed == nil {
+		collapsed = make(map[string]bool)
+		all[g.String()] = collapsed
+	}
+	return collapsed
+}
+
+type containerGroup struct {
+	name       string
 	containers []controller.Container
 }
 
-// BuildRows computes the flat visible row list from containers and collapse state.
-func BuildRows(containers []controller.Container, collapsed map[string]bool) []Row {
-	groups, standalone := buildComposeGroups(containers)
+// BuildRows computes the flat visible row list from containers, grouped by
+// grouping, and collapse state. ghosts holds, per compose project, the
+// services defined on disk that have no container; they follow the project's
+// containers, and projects with nothing but ghosts are listed after the
+// others. ghosts only apply when grouping by compose project.
+func BuildRows(containers []controller.Container, grouping Grouping, collapsed map[string]bool, ghosts map[string][]controller.ComposeService) []Row {
+	if grouping.Kind != GroupByCompose {
+		ghosts = nil
+	}
+	groups, standalone := buildGroups(containers, grouping)
+	var ghostOnly []containerGroup
+	for project := range ghosts {
+		if findGroup(groups, project) < 0 {
+			ghostOnly = append(ghostOnly, containerGroup{name: project})
+		}
+	}
+	sort.Slice(ghostOnly, func(i, j int) bool { return ghostOnly[i].name < ghostOnly[j].name })
+	groups = append(groups, ghostOnly...)
 
 	var rows []Row
 	for _, g := range groups {
-		isCollapsed := collapsed[g.project]
+		isCollapsed := collapsed[g.name]
 		rows = append(rows, Row{
 			Type:       RowTypeGroup,
-			GroupID:    g.project,
-			Name:       g.project,
+			GroupID:    g.name,
+			Name:       g.name,
 			Collapsed:  isCollapsed,
 			Containers: g.containers,
+			Ghosts:     ghosts[g.name],
 		})
 		if !isCollapsed {
 			for _, c := range g.containers {
 				c := c
 				rows = append(rows, Row{
 					Type:      RowTypeContainer,
-					GroupID:   g.project,
+					GroupID:   g.name,
 					Name:      c.Names,
 					Container: &c,
 				})
 			}
+			for _, svc := range ghosts[g.name] {
+				svc := svc
+				rows = append(rows, Row{
+					Type:    RowTypeGhost,
+					GroupID: g.name,
+					Name:    svc.Name,
+					Service: &svc,
+				})
+			}
 		}
 	}
 	for _, c := range standalone {
@@ -64,26 +191,56 @@ func BuildRows(containers []controller.Container, collapsed map[string]bool) []R
 	return rows
 }
 
-// buildComposeGroups partitions containers into ordered compose groups and a
-// standalone slice (containers without the compose project label).
-func buildComposeGroups(containers []controller.Container) (groups []composeGroup, standalone []controller.Container) {
-	projectIndex := map[string]int{}
+// buildGroups partitions containers into groups, in order of first
+// appearance, and a standalone slice of the containers in no group.
+func buildGroups(containers []controller.Container, grouping Grouping) (groups []containerGroup, standalone []controller.Container) {
+	index := map[string]int{}
 	for _, c := range containers {
-		project := c.Labels["com.docker.compose.project"]
-		if project == "" {
+		names := grouping.groupsOf(c)
+		if len(names) == 0 {
 			standalone = append(standalone, c)
 			continue
 		}
-		if idx, ok := projectIndex[project]; ok {
-			groups[idx].containers = append(groups[idx].containers, c)
-		} else {
-			projectIndex[project] = len(groups)
-			groups = append(groups, composeGroup{project: project, containers: []controller.Container{c}})
+		for _, name := range names {
+			if idx, ok := index[name]; ok {
+				groups[idx].containers = append(groups[idx].containers, c)
+			} else {
+				index[name] = len(groups)
+				groups = append(groups, containerGroup{name: name, containers: []controller.Container{c}})
+			}
 		}
 	}
 	return
 }
 
+// findGroup returns the index of the group called name in groups, or -1.
+func findGroup(groups []containerGroup, name string) int {
+	for i, g := range groups {
+		if g.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// composeGhosts returns, per project, the services of projects that have no
+// container among containers, stopped ones included.
+func composeGhosts(projects map[string]controller.ComposeProject, containers []controller.Container) map[string][]controller.ComposeService {
+	created := map[[2]string]bool{}
+	for _, c := range containers {
+		created[[2]string{c.Labels["com.docker.compose.project"], c.Labels["com.docker.compose.service"]}] = true
+	}
+	ghosts := map[string][]controller.ComposeService{}
+	for name, p := range projects {
+		for _, svc := range p.Services {
+			if !created[[2]string{name, svc.Name}] {
+				ghosts[name] = append(ghosts[name], svc)
+			}
+		}
+	}
+	return ghosts
+}
+
 // groupAggStatus counts running vs total containers in a group.
 func groupAggStatus(containers []controller.Container) (running, total int) {
 	for _, c := range containers {
@@ -0,0 +1,358 @@
+package tui
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/rluders/berth/internal/controller"
+	"github.com/rluders/berth/internal/utils"
+)
+
+// detailsSection is one collapsible card of the details view.
+type detailsSection struct {
+	title string
+	lines []string
+}
+
+// secretKeyMarkers are substrings of environment variable names whose values
+// are masked until revealed.
+var secretKeyMarkers = []string{"PASSWORD", "PASSWD", "SECRET", "TOKEN", "KEY", "CREDENTIAL", "PRIVATE"}
+
+// isSecretKey reports whether an environment variable name looks like it
+// holds a secret.
+func isSecretKey(name string) bool {
+	upper := strings.ToUpper(name)
+	for _, marker := range secretKeyMarkers {
+		if strings.Contains(upper, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildDetailsSections formats ContainerDetails and the container's recent
+// stats into the details view sections, in display order. Secret-looking
+// environment values are masked unless reveal is set.
+func buildDetailsSections(d controller.ContainerDetails, stats []controller.StatSample, reveal bool) []detailsSection {
+	th := currentTheme
+	muted := th.CardValueStyle.Foreground(lipgloss.Color(colorMuted))
+	none := []string{"  " + muted.Render("(none)")}
+
+	field := func(label, value string) string {
+		l := th.CardTitleStyle.Render(fmt.Sprintf("%-12s", label))
+		v := th.CardValueStyle.Render(value)
+		return "  " + l + "  " + v
+	}
+	optional := func(lines []string, label, value string) []string {
+		if value == "" {
+			return lines
+		}
+		return append(lines, field(label, value))
+	}
+
+	// ── Container section ──────────────────────────────────────────────────
+	infoLines := []string{
+		field("ID", d.ID),
+		field("Name", d.Name),
+		field("Image", d.Image),
+	}
+	infoLines = optional(infoLines, "Entrypoint", d.Entrypoint)
+	infoLines = optional(infoLines, "Command", d.Command)
+	infoLines = optional(infoLines, "Working dir", d.WorkingDir)
+	infoLines = optional(infoLines, "User", d.User)
+	infoLines = append(infoLines, field("Created", d.Created))
+
+	// ── State section ──────────────────────────────────────────────────────
+	stateLines := []string{field("State", StatusBadge(d.State))}
+	stateLines = optional(stateLines, "Started", d.StartedAt)
+	if d.State != "running" {
+		stateLines = optional(stateLines, "Finished", d.FinishedAt)
+		exit := fmt.Sprintf("%d", d.ExitCode)
+		if d.ExitCode != 0 {
+			exit = styleStatusDead.Render(exit)
+		}
+		stateLines = append(stateLines, field("Exit code", exit))
+	}
+	if d.OOMKilled {
+		stateLines = append(stateLines, field("OOM killed", styleStatusDead.Render("yes")))
+	}
+	if d.Error != "" {
+		stateLines = append(stateLines, field("Error", styleStatusDead.Render(d.Error)))
+	}
+	stateLines = optional(stateLines, "Restart", d.RestartPolicy)
+	stateLines = append(stateLines, field("Restarts", fmt.Sprintf("%d", d.RestartCount)))
+
+	// ── Limits section ─────────────────────────────────────────────────────
+	lim := d.Limits
+	limitLines := []string{
+		field("Memory", formatLimitBytes(lim.Memory)),
+		field("Reservation", formatLimitBytes(lim.MemoryReservation)),
+		field("Swap", formatLimitBytes(lim.MemorySwap)),
+		field("CPUs", formatCPUs(lim.NanoCPUs)),
+	}
+	if lim.CPUShares > 0 {
+		limitLines = append(limitLines, field("CPU shares", fmt.Sprintf("%d", lim.CPUShares)))
+	}
+	limitLines = optional(limitLines, "CPU set", lim.CPUSetCPUs)
+	pids := "unlimited"
+	if lim.PidsLimit > 0 {
+		pids = fmt.Sprintf("%d", lim.PidsLimit)
+	}
+	limitLines = append(limitLines, field("PIDs", pids))
+
+	// ── Environment section ────────────────────────────────────────────────
+	envLines := none
+	if len(d.Env) > 0 {
+		envLines = nil
+		for _, e := range d.Env {
+			name, value, ok := strings.Cut(e, "=")
+			switch {
+			case !ok:
+				envLines = append(envLines, "  "+e)
+			case !reveal && isSecretKey(name):
+				envLines = append(envLines, field(name, muted.Render("••••••••")))
+			default:
+				envLines = append(envLines, field(name, value))
+			}
+		}
+	}
+
+	// ── Labels section ─────────────────────────────────────────────────────
+	labelLines := none
+	if len(d.Labels) > 0 {
+		labelLines = nil
+		for _, k := range slices.Sorted(maps.Keys(d.Labels)) {
+			labelLines = append(labelLines, "  "+th.CardTitleStyle.Render(k)+"  "+th.CardValueStyle.Render(d.Labels[k]))
+		}
+	}
+
+	// ── Ports section ──────────────────────────────────────────────────────
+	portLines := none
+	if len(d.Ports) > 0 {
+		portLines = nil
+		for _, p := range d.Ports {
+			hostIP := p.HostIP
+			if hostIP == "" {
+				hostIP = "0.0.0.0"
+			}
+			line := fmt.Sprintf("%s/%s → %s:%s", p.ContainerPort, p.Protocol, hostIP, p.HostPort)
+			portLines = append(portLines, "  "+th.CardValueStyle.Render(line))
+		}
+	}
+
+	// ── Mounts section ─────────────────────────────────────────────────────
+	mountLines := none
+	if len(d.Mounts) > 0 {
+		mountLines = nil
+		for _, mt := range d.Mounts {
+			rw := th.LogDebugStyle.Render("ro")
+			if mt.RW {
+				rw = th.LogInfoStyle.Render("rw")
+			}
+			line := fmt.Sprintf("[%s] %s → %s (%s)", mt.Type, mt.Source, mt.Destination, rw)
+			mountLines = append(mountLines, "  "+th.CardValueStyle.Render(line))
+		}
+	}
+
+	// ── Networks section ───────────────────────────────────────────────────
+	netLines := none
+	if len(d.Networks) > 0 {
+		netLines = nil
+		for _, n := range d.Networks {
+			line := fmt.Sprintf("%s  IP: %s  GW: %s", n.Name, n.IPAddress, n.Gateway)
+			netLines = append(netLines, "  "+th.CardValueStyle.Render(line))
+		}
+	}
+
+	// ── Logging section ────────────────────────────────────────────────────
+	logLines := none
+	if d.LogDriver != "" {
+		logLines = []string{field("Driver", d.LogDriver)}
+		for _, k := range slices.Sorted(maps.Keys(d.LogOpts)) {
+			logLines = append(logLines, field(k, d.LogOpts[k]))
+		}
+	}
+
+	return []detailsSection{
+		{"Container", infoLines},
+		{"State", stateLines},
+		{"Health", renderHealthLines(d.Health)},
+		{"Resources", renderStatLines(stats)},
+		{"Limits", limitLines},
+		{"Environment", envLines},
+		{"Labels", labelLines},
+		{"Ports", portLines},
+		{"Mounts", mountLines},
+		{"Networks", netLines},
+		{"Logging", logLines},
+	}
+}
+
+// formatLimitBytes renders a memory limit, where 0 means unlimited and -1
+// (swap only) means unlimited swap.
+func formatLimitBytes(n int64) string {
+	if n <= 0 {
+		return "unlimited"
+	}
+	return utils.FormatBytes(uint64(n))
+}
+
+// formatCPUs renders a NanoCPUs limit as a CPU count.
+func formatCPUs(nano int64) string {
+	if nano <= 0 {
+		return "unlimited"
+	}
+	return fmt.Sprintf("%g", float64(nano)/1e9)
+}
+
+// renderDetailsContent renders the sections as cards. Collapsed sections show
+// only their header and the selected section's header is highlighted. It also
+// returns the line each card starts on, for scrolling to a section.
+func renderDetailsContent(sections []detailsSection, collapsed map[string]bool, selected int) (string, []int) {
+	th := currentTheme
+	cards := make([]string, len(sections))
+	offsets := make([]int, len(sections))
+	line := 0
+	for i, s := range sections {
+		marker := "▾ "
+		if collapsed[s.title] {
+			marker = "▸ "
+		}
+		header := th.SectionStyle.Render(marker + s.title)
+		if i == selected {
+			header = th.SectionStyle.Inherit(th.TableSelectedStyle).Render(marker + s.title)
+		}
+		body := header
+		if !collapsed[s.title] {
+			body += "\n" + strings.Join(s.lines, "\n")
+		}
+		cards[i] = th.CardStyle.Render(body)
+		offsets[i] = line
+		line += lipgloss.Height(cards[i])
+	}
+	return strings.Join(cards, "\n"), offsets
+}
+
+// refreshDetails re-renders the details view, keeping the scroll position.
+func (m *Model) refreshDetails() {
+	sections := buildDetailsSections(m.currentDetails, m.statsMonitor.History(m.currentDetailsID), m.detailsReveal)
+	m.detailsSection = min(max(m.detailsSection, 0), len(sections)-1)
+	content, offsets := renderDetailsContent(sections, m.detailsCollapsed, m.detailsSection)
+	m.detailsViewPort.SetContent(content)
+	m.detailsOffsets = offsets
+}
+
+// scrollToDetailsSection scrolls the details view so the selected section's
+// header is visible.
+func (m *Model) scrollToDetailsSection() {
+	if m.detailsSection >= len(m.detailsOffsets) {
+		return
+	}
+	top := m.detailsOffsets[m.detailsSection]
+	if top < m.detailsViewPort.YOffset() || top >= m.detailsViewPort.YOffset()+m.detailsViewPort.Height()-2 {
+		m.detailsViewPort.SetYOffset(top)
+	}
+}
+
+func (m Model) handleDetailsKey(msg tea.KeyPressMsg) (Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, Keys.Details.Next):
+		m.detailsSection = min(m.detailsSection+1, len(m.detailsOffsets)-1)
+	case key.Matches(msg, Keys.Details.Prev):
+		m.detailsSection = max(m.detailsSection-1, 0)
+	case key.Matches(msg, Keys.Details.Toggle):
+		sections := buildDetailsSections(m.currentDetails, nil, false)
+		if m.detailsSection < len(sections) {
+			title := sections[m.detailsSection].title
+			m.detailsCollapsed[title] = !m.detailsCollapsed[title]
+			if !m.detailsCollapsed[title] {
+				delete(m.detailsCollapsed, title)
+			}
+			m.persistState()
+		}
+	case key.Matches(msg, Keys.Details.Reveal):
+		m.detailsReveal = !m.detailsReveal
+		if m.detailsReveal {
+			m.statusMessage = "Secrets revealed (v to hide)"
+		} else {
+			m.statusMessage = ""
+		}
+	default:
+		var cmd tea.Cmd
+		m.detailsViewPort, cmd = m.detailsViewPort.Update(msg)
+		return m, cmd
+	}
+	m.refreshDetails()
+	m.scrollToDetailsSection()
+	return m, nil
+}
+
+// maxProbeOutputLines caps the output shown per health probe.
+const maxProbeOutputLines = 3
+
+// renderHealthLines formats a container's health check configuration and its
+// latest probe results for the details view.
+func renderHealthLines(hc *controller.HealthCheck) []string {
+	th := currentTheme
+	muted := lipgloss.NewStyle().Foreground(lipgloss.Color(colorMuted))
+	if hc == nil {
+		return []string{"  " + muted.Render("(no health check)")}
+	}
+
+	field := func(label, value string) string {
+		return "  " + th.CardTitleStyle.Render(fmt.Sprintf("%-12s", label)) + "  " + th.CardValueStyle.Render(value)
+	}
+
+	status := FormatHealth(hc.Status)
+	if hc.FailingStreak > 0 {
+		status += muted.Render(fmt.Sprintf("  (failing streak %d)", hc.FailingStreak))
+	}
+	lines := []string{field("Status", status)}
+	if len(hc.Test) > 0 {
+		lines = append(lines, field("Test", strings.Join(hc.Test, " ")))
+	}
+	retries := hc.Retries
+	if retries == 0 {
+		retries = 3 // the engine default
+	}
+	lines = append(lines, field("Schedule", fmt.Sprintf("every %s, timeout %s, %d retries, start period %s",
+		orDefault(hc.Interval, "30s"), orDefault(hc.Timeout, "30s"), retries, orDefault(hc.StartPeriod, "0s"))))
+
+	if len(hc.Probes) == 0 {
+		return append(lines, "  "+muted.Render("(no probes yet)"))
+	}
+	for _, p := range hc.Probes {
+		exit := styleStatusRunning.Render("exit 0")
+		if p.ExitCode != 0 {
+			exit = styleStatusDead.Render(fmt.Sprintf("exit %d", p.ExitCode))
+		}
+		lines = append(lines, fmt.Sprintf("  %s  %s  %s",
+			th.LogTimestampStyle.Render(p.Start.Local().Format("15:04:05")),
+			exit,
+			muted.Render(p.End.Sub(p.Start).Round(time.Millisecond).String())))
+		output := strings.Split(p.Output, "\n")
+		if len(output) > maxProbeOutputLines {
+			output = append(output[:maxProbeOutputLines], "…")
+		}
+		for _, l := range output {
+			if l != "" {
+				lines = append(lines, "      "+th.CardValueStyle.Render(l))
+			}
+		}
+	}
+	return lines
+}
+
+// orDefault formats d, or returns def when d is zero (the engine default).
+func orDefault(d time.Duration, def string) string {
+	if d == 0 {
+		return def
+	}
+	return d.String()
+}
@@ -0,0 +1,112 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/rluders/berth/internal/controller"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsSecretKey(t *testing.T) {
+	for _, name := range []string{"POSTGRES_PASSWORD", "GITHUB_TOKEN", "aws_secret_access_key", "API_KEY", "SSH_PRIVATE"} {
+		assert.True(t, isSecretKey(name), name)
+	}
+	for _, name := range []string{"PATH", "HOME", "NODE_ENV", "PORT"} {
+		assert.False(t, isSecretKey(name), name)
+	}
+}
+
+// sectionText returns the plain text of the named section.
+func sectionText(t *testing.T, sections []detailsSection, title string) string {
+	t.Helper()
+	for _, s := range sections {
+		if s.title == title {
+			return ansi.Strip(strings.Join(s.lines, "\n"))
+		}
+	}
+	require.Failf(t, "section not found", "%s", title)
+	return ""
+}
+
+func TestBuildDetailsSections_masksSecretEnvUntilRevealed(t *testing.T) {
+	d := controller.ContainerDetails{Env: []string{"NODE_ENV=production", "DB_PASSWORD=hunter2"}}
+
+	masked := sectionText(t, buildDetailsSections(d, nil, false), "Environment")
+	assert.Contains(t, masked, "production")
+	assert.NotContains(t, masked, "hunter2")
+	assert.Contains(t, masked, "••••••••")
+
+	revealed := sectionText(t, buildDetailsSections(d, nil, true), "Environment")
+	assert.Contains(t, revealed, "hunter2")
+}
+
+func TestBuildDetailsSections_stateAndLimits(t *testing.T) {
+	d := controller.ContainerDetails{
+		State:         "exited",
+		ExitCode:      137,
+		OOMKilled:     true,
+		RestartPolicy: "on-failure:3",
+		RestartCount:  2,
+		Limits:        controller.ResourceLimits{Memory: 512 << 20, NanoCPUs: 1_500_000_000},
+		Labels:        map[string]string{"b": "2", "a": "1"},
+	}
+	sections := buildDetailsSections(d, nil, false)
+
+	state := sectionText(t, sections, "State")
+	assert.Contains(t, state, "137")
+	assert.Contains(t, state, "OOM killed")
+	assert.Contains(t, state, "on-failure:3")
+
+	limits := sectionText(t, sections, "Limits")
+	assert.Contains(t, limits, "1.5")
+	assert.Contains(t, limits, "unlimited") // swap and PIDs
+
+	labels := sectionText(t, sections, "Labels")
+	assert.Less(t, strings.Index(labels, "a"), strings.Index(labels, "b"), "labels are sorted")
+}
+
+func TestRenderDetailsContent_collapsedSectionHidesBody(t *testing.T) {
+	sections := []detailsSection{{"One", []string{"first body"}}, {"Two", []string{"second body"}}}
+
+	content, offsets := renderDetailsContent(sections, map[string]bool{"One": true}, 0)
+
+	plain := ansi.Strip(content)
+	assert.Contains(t, plain, "▸ One")
+	assert.NotContains(t, plain, "first body")
+	assert.Contains(t, plain, "▾ Two")
+	assert.Contains(t, plain, "second body")
+	require.Len(t, offsets, 2)
+	assert.Equal(t, 0, offsets[0])
+	assert.Greater(t, offsets[1], offsets[0])
+}
+
+func TestHandleDetailsKey_navigatesTogglesAndReveals(t *testing.T) {
+	useStateFile(t)
+	t.Setenv("HOME", t.TempDir())
+	m := InitialModel()
+	m.currentView = DetailsView
+	m.detailsCollapsed = map[string]bool{}
+	m.currentDetails = controller.ContainerDetails{ID: "abc", Env: []string{"API_TOKEN=s3cret"}}
+	m.refreshDetails()
+
+	m, _ = updateModel(t, m, tea.KeyPressMsg{Code: ']', Text: "]"})
+	assert.Equal(t, 1, m.detailsSection)
+
+	m, _ = updateModel(t, m, tea.KeyPressMsg{Code: tea.KeyEnter})
+	assert.True(t, m.detailsCollapsed["State"])
+	m, _ = updateModel(t, m, tea.KeyPressMsg{Code: tea.KeyEnter})
+	assert.False(t, m.detailsCollapsed["State"])
+
+	m, _ = updateModel(t, m, tea.KeyPressMsg{Code: '[', Text: "["})
+	m, _ = updateModel(t, m, tea.KeyPressMsg{Code: '[', Text: "["})
+	assert.Equal(t, 0, m.detailsSection, "selection stops at the first section")
+
+	assert.NotContains(t, ansi.Strip(m.detailsViewPort.GetContent()), "s3cret")
+	m, _ = updateModel(t, m, tea.KeyPressMsg{Code: 'v', Text: "v"})
+	assert.True(t, m.detailsReveal)
+	assert.Contains(t, ansi.Strip(m.detailsViewPort.GetContent()), "s3cret")
+}
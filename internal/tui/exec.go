@@ -0,0 +1,316 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/rluders/berth/internal/controller"
+)
+
+// execPrefs are the exec dialog choices remembered for an image.
+type execPrefs struct {
+	Command    string   `json:"command"`
+	User       string   `json:"user,omitempty"`
+	WorkingDir string   `json:"workingDir,omitempty"`
+	Env        []string `json:"env,omitempty"`
+	TTY        bool     `json:"tty"`
+}
+
+// config converts the preferences into an exec configuration.
+func (p execPrefs) config() controller.ExecConfig {
+	return controller.ExecConfig{
+		Cmd:        splitArgs(p.Command),
+		User:       p.User,
+		WorkingDir: p.WorkingDir,
+		Env:        p.Env,
+		TTY:        p.TTY,
+	}
+}
+
+// defaultExecPrefs returns the preferences used when an image has none saved.
+func defaultExecPrefs(shell string) execPrefs {
+	if shell == "" {
+		shell = "/bin/sh"
+	}
+	return execPrefs{Command: shell, TTY: true}
+}
+
+// findContainer returns the container with the given ID from the last listing.
+func (m Model) findContainer(id string) (controller.Container, bool) {
+	for _, c := range m.containers {
+		if c.ID == id {
+			return c, true
+		}
+	}
+	return controller.Container{}, false
+}
+
+// openExecForm shows the exec dialog for a container. Saved preferences for
+// the container's image are used as-is; otherwise the shell is detected first.
+func (m Model) openExecForm(id, name string) (Model, tea.Cmd) {
+	c, ok := m.findContainer(id)
+	if ok && c.State != "running" {
+		m.statusMessage = "Container must be running to exec"
+		return m, nil
+	}
+	if prefs, saved := m.execPrefs[c.Image]; ok && saved {
+		m.form = newExecForm(id, name, c.Image, prefs)
+		return m, nil
+	}
+	m.statusMessage = fmt.Sprintf("Detecting shell in %s...", name)
+	m.showSpinner = true
+	return m, tea.Batch(detectShellCmd(id, name, c.Image), m.spinner.Tick)
+}
+
+// handleExecShellDetectedMsg opens the exec dialog once shell detection finishes.
+func (m Model) handleExecShellDetectedMsg(msg execShellDetectedMsg) (Model, tea.Cmd) {
+	m.showSpinner = false
+	m.statusMessage = ""
+	m.form = newExecForm(msg.id, msg.name, msg.image, defaultExecPrefs(msg.shell))
+	return m, nil
+}
+
+// newExecForm prompts for the command, user, working directory, environment
+// and TTY flag of an exec session.
+func newExecForm(id, name, image string, prefs execPrefs) *Form {
+	tty := "n"
+	if prefs.TTY {
+		tty = "y"
+	}
+	return NewForm(
+		"Exec  "+name,
+		func(m Model, values []string) (Model, tea.Cmd) {
+			prefs := execPrefs{
+				Command:    values[0],
+				User:       values[1],
+				WorkingDir: values[2],
+				Env:        splitEnv(values[3]),
+				TTY:        !strings.EqualFold(values[4], "n") && !strings.EqualFold(values[4], "no"),
+			}
+			if len(splitArgs(prefs.Command)) == 0 {
+				m.statusMessage = "Exec cancelled: a command is required."
+				return m, nil
+			}
+			if image != "" {
+				m.execPrefs[image] = prefs
+				m.persistState()
+			}
+			m.lastActionKey = "e"
+			m.statusMessage = execPreview(name, prefs)
+			return m, execCmd(id, prefs.config())
+		},
+		NewFormField("Command", prefs.Command, "/bin/sh"),
+		NewFormField("User", prefs.User, "root"),
+		NewFormField("Working dir", prefs.WorkingDir, "/app"),
+		NewFormField("Env", strings.Join(prefs.Env, ", "), "KEY=value, OTHER=value"),
+		NewFormField("TTY (y/n)", tty, "y"),
+	)
+}
+
+// execPreview returns the docker CLI equivalent of an exec session.
+func execPreview(name string, prefs execPrefs) string {
+	parts := []string{"docker", "exec"}
+	if prefs.TTY {
+		parts = append(parts, "-it")
+	} else {
+		parts = append(parts, "-i")
+	}
+	if prefs.User != "" {
+		parts = append(parts, "-u", prefs.User)
+	}
+	if prefs.WorkingDir != "" {
+		parts = append(parts, "-w", prefs.WorkingDir)
+	}
+	for _, e := range prefs.Env {
+		parts = append(parts, "-e", e)
+	}
+	parts = append(parts, name, prefs.Command)
+	return strings.Join(parts, " ")
+}
+
+// splitEnv parses a comma-separated list of KEY=value pairs.
+func splitEnv(s string) []string {
+	var env []string
+	for _, e := range strings.Split(s, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			env = append(env, e)
+		}
+	}
+	return env
+}
+
+// splitArgs splits a command line into arguments, honouring single and double
+// quotes and backslash escapes outside single quotes.
+func splitArgs(s string) []string {
+	var (
+		args    []string
+		cur     strings.Builder
+		inArg   bool
+		quote   rune
+		escaped bool
+	)
+	for _, r := range s {
+		switch {
+		case escaped:
+			cur.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inArg = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, cur.String())
+				cur.Reset()
+				inArg = false
+			}
+		default:
+			cur.WriteRune(r)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, cur.String())
+	}
+	return args
+}
+
+// maxExecHistory caps the remembered one-off commands per container.
+const maxExecHistory = 20
+
+// newRunCommandForm prompts for a one-off command to run in a container.
+func newRunCommandForm(id, name string, history []string) *Form {
+	return NewForm(
+		"Run command  "+name,
+		func(m Model, values []string) (Model, tea.Cmd) {
+			if len(splitArgs(values[0])) == 0 {
+				m.statusMessage = "Run cancelled: a command is required."
+				return m, nil
+			}
+			return m.runCommand(id, name, values[0])
+		},
+		NewFormField("Command", "", "ps aux").WithHistory(history),
+	)
+}
+
+// openRunCommandForm shows the run-command prompt for a running container.
+func (m Model) openRunCommandForm(id, name string) (Model, tea.Cmd) {
+	if c, ok := m.findContainer(id); ok && c.State != "running" {
+		m.statusMessage = "Container must be running to run a command"
+		return m, nil
+	}
+	m.form = newRunCommandForm(id, name, m.execHistory[name])
+	return m, nil
+}
+
+// runCommand records command in the container's history and streams its
+// output into the exec output view.
+func (m Model) runCommand(id, name, command string) (Model, tea.Cmd) {
+	m.execHistory[name] = pushHistory(m.execHistory[name], command, maxExecHistory)
+	m.persistState()
+
+	m.stopExecCommand()
+	if m.currentView != ExecOutputView {
+		m.pushView(ExecOutputView)
+	}
+	m.execOutputID = id
+	m.execOutputName = name
+	m.execOutputCmd = command
+	m.execOutput = nil
+	m.execResult = nil
+	m.execRunning = true
+	m.refreshExecOutput()
+
+	ch, cancel, waitCmd := startRunCommandCmd(id, controller.ExecConfig{Cmd: splitArgs(command)})
+	m.execOutputCh = ch
+	m.execOutputCancel = cancel
+	return m, waitCmd
+}
+
+// stopExecCommand cancels the running one-off command, if any.
+func (m *Model) stopExecCommand() {
+	if m.execOutputCancel != nil {
+		m.execOutputCancel()
+		m.execOutputCancel = nil
+	}
+	m.execOutputCh = nil
+	m.execRunning = false
+}
+
+// refreshExecOutput re-renders the exec output viewport, following the tail
+// while the viewport is already at the bottom.
+func (m *Model) refreshExecOutput() {
+	atBottom := m.execOutputVP.AtBottom()
+	m.execOutputVP.SetContent(buildExecOutputContent(m.execOutput, m.showLineNumbers))
+	if atBottom {
+		m.execOutputVP.GotoBottom()
+	}
+}
+
+func (m Model) handleExecOutputMsg(msg execOutputMsg) (Model, tea.Cmd) {
+	if msg.ch != m.execOutputCh {
+		return m, nil // output of a command that was replaced or cancelled
+	}
+	m.execOutput = append(m.execOutput, msg.line)
+	if len(m.execOutput) > 10000 {
+		m.execOutput = m.execOutput[len(m.execOutput)-5000:]
+	}
+	m.refreshExecOutput()
+	return m, waitForExecLineCmd(msg.ch, msg.done)
+}
+
+func (m Model) handleExecDoneMsg(msg execDoneMsg) (Model, tea.Cmd) {
+	if msg.ch != m.execOutputCh {
+		return m, nil
+	}
+	m.execOutputCancel = nil
+	m.execOutputCh = nil
+	m.execRunning = false
+	m.execResult = &msg
+	if msg.err != nil {
+		m.statusMessage = fmt.Sprintf("%s failed: %v", m.execOutputCmd, msg.err)
+	} else {
+		m.statusMessage = fmt.Sprintf("%s exited with code %d", m.execOutputCmd, msg.code)
+	}
+	return m, nil
+}
+
+// newExecHistoryMenu lists the container's previous commands for re-running.
+func newExecHistoryMenu(id, name string, history []string) *QuickMenu {
+	menu := &QuickMenu{Title: "History  " + name}
+	for i, command := range history {
+		if i == 9 {
+			break
+		}
+		command := command
+		menu.Items = append(menu.Items, QuickMenuItem{
+			Label: command,
+			Key:   fmt.Sprintf("%d", i+1),
+			Action: func(m Model) (Model, tea.Cmd) {
+				return m.runCommand(id, name, command)
+			},
+		})
+	}
+	return menu
+}
+
+// pushHistory moves entry to the front of history, dropping duplicates and
+// keeping at most limit entries.
+func pushHistory(history []string, entry string, limit int) []string {
+	out := []string{entry}
+	for _, h := range history {
+		if h != entry && len(out) < limit {
+			out = append(out, h)
+		}
+	}
+	return out
+}
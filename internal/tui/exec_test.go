@@ -0,0 +1,154 @@
+package tui
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/rluders/berth/internal/controller"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{"  /bin/bash  ", []string{"/bin/bash"}},
+		{"sh -c 'echo hi there'", []string{"sh", "-c", "echo hi there"}},
+		{`sh -c "echo \"quoted\""`, []string{"sh", "-c", `echo "quoted"`}},
+		{`cat my\ file ''`, []string{"cat", "my file", ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			assert.Equal(t, tt.want, splitArgs(tt.in))
+		})
+	}
+}
+
+func TestSplitEnv(t *testing.T) {
+	assert.Equal(t, []string{"A=1", "B=two"}, splitEnv(" A=1, ,B=two "))
+	assert.Nil(t, splitEnv(""))
+}
+
+func TestExecPreview(t *testing.T) {
+	prefs := execPrefs{Command: "/bin/bash", User: "root", WorkingDir: "/app", Env: []string{"A=1"}, TTY: true}
+	assert.Equal(t, "docker exec -it -u root -w /app -e A=1 web /bin/bash", execPreview("web", prefs))
+	assert.Equal(t, "docker exec -i web env", execPreview("web", execPrefs{Command: "env"}))
+}
+
+func TestOpenExecForm_usesSavedPrefs(t *testing.T) {
+	m := InitialModel()
+	m.containers = []controller.Container{{ID: "abc", Names: "web", Image: "nginx", State: "running"}}
+	m.execPrefs["nginx"] = execPrefs{Command: "/bin/bash", User: "www", TTY: true}
+
+	result, cmd := m.openExecForm("abc", "web")
+
+	assert.Nil(t, cmd)
+	require.NotNil(t, result.form)
+	assert.Equal(t, []string{"/bin/bash", "www", "", "", "y"}, result.form.Values())
+}
+
+func TestOpenExecForm_rejectsStoppedContainer(t *testing.T) {
+	m := InitialModel()
+	m.containers = []controller.Container{{ID: "abc", Names: "web", Image: "nginx", State: "exited"}}
+
+	result, cmd := m.openExecForm("abc", "web")
+
+	assert.Nil(t, cmd)
+	assert.Nil(t, result.form)
+	assert.Equal(t, "Container must be running to exec", result.statusMessage)
+}
+
+func TestHandleExecShellDetectedMsg_opensFormWithShell(t *testing.T) {
+	m := InitialModel()
+
+	result, _ := updateModel(t, m, execShellDetectedMsg{id: "abc", name: "web", image: "nginx", shell: "/bin/bash"})
+
+	require.NotNil(t, result.form)
+	assert.Equal(t, []string{"/bin/bash", "", "", "", "y"}, result.form.Values())
+}
+
+func TestExecForm_submitRemembersPrefs(t *testing.T) {
+	useStateFile(t)
+	t.Setenv("HOME", t.TempDir())
+
+	m := InitialModel()
+	m.form = newExecForm("abc", "web", "nginx", defaultExecPrefs("/bin/sh"))
+	m.form.Fields[1].Input.SetValue("root")
+	m.form.Fields[3].Input.SetValue("A=1, B=2")
+	m.form.Fields[4].Input.SetValue("n")
+
+	result, cmd := updateModel(t, m, tea.KeyPressMsg{Code: tea.KeyEnter})
+
+	assert.NotNil(t, cmd)
+	assert.Nil(t, result.form)
+	assert.Equal(t, execPrefs{Command: "/bin/sh", User: "root", Env: []string{"A=1", "B=2"}}, result.execPrefs["nginx"])
+	assert.Equal(t, result.execPrefs, loadState().ExecPrefs)
+}
+
+func TestPushHistory(t *testing.T) {
+	assert.Equal(t, []string{"env"}, pushHistory(nil, "env", 3))
+	assert.Equal(t, []string{"ps aux", "env", "id"}, pushHistory([]string{"env", "ps aux", "id"}, "ps aux", 3))
+	assert.Equal(t, []string{"ls", "env", "ps aux"}, pushHistory([]string{"env", "ps aux", "id"}, "ls", 3))
+}
+
+func TestHandleExecOutputMsg_appendsLinesFromCurrentCommand(t *testing.T) {
+	m := InitialModel()
+	ch := make(chan controller.ExecLine)
+	done := make(chan execDoneMsg)
+	m.execOutputCh = ch
+
+	result, cmd := updateModel(t, m, execOutputMsg{line: controller.ExecLine{Line: "PATH=/usr/bin"}, ch: ch, done: done})
+
+	assert.NotNil(t, cmd)
+	assert.Equal(t, []controller.ExecLine{{Line: "PATH=/usr/bin"}}, result.execOutput)
+}
+
+func TestHandleExecOutputMsg_ignoresReplacedCommand(t *testing.T) {
+	m := InitialModel()
+	m.execOutputCh = make(chan controller.ExecLine)
+
+	result, cmd := updateModel(t, m, execOutputMsg{line: controller.ExecLine{Line: "stale"}, ch: make(chan controller.ExecLine)})
+
+	assert.Nil(t, cmd)
+	assert.Empty(t, result.execOutput)
+}
+
+func TestHandleExecDoneMsg_recordsExitCode(t *testing.T) {
+	m := InitialModel()
+	ch := make(chan controller.ExecLine)
+	m.execOutputCh = ch
+	m.execOutputCmd = "false"
+	m.execRunning = true
+
+	result, _ := updateModel(t, m, execDoneMsg{code: 1, ch: ch})
+
+	assert.False(t, result.execRunning)
+	require.NotNil(t, result.execResult)
+	assert.Equal(t, 1, result.execResult.code)
+	assert.Equal(t, "false exited with code 1", result.statusMessage)
+}
+
+func TestHandleExecOutputKey_backStopsCommand(t *testing.T) {
+	m := InitialModel()
+	m.pushView(ExecOutputView)
+	cancelled := false
+	m.execOutputCancel = func() { cancelled = true }
+	m.execRunning = true
+
+	result, _ := updateModel(t, m, tea.KeyPressMsg{Code: tea.KeyEscape})
+
+	assert.True(t, cancelled)
+	assert.False(t, result.execRunning)
+	assert.Equal(t, ContainersView, result.currentView)
+}
+
+func TestNewExecHistoryMenu_numbersEntries(t *testing.T) {
+	menu := newExecHistoryMenu("abc", "web", []string{"env", "ps aux"})
+
+	require.Len(t, menu.Items, 2)
+	assert.Equal(t, "1", menu.Items[0].Key)
+	assert.Equal(t, "ps aux", menu.Items[1].Label)
+}
@@ -0,0 +1,247 @@
+package tui
+
+import (
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+)
+
+// FormField is one labelled text input in a Form.
+type FormField struct {
+	Label string
+	Input textinput.Model
+	// History holds previous values, most recent first, recalled with ctrl+p/ctrl+n.
+	History []string
+
+	histPos int    // 0 while editing, i while showing History[i-1]
+	draft   string // value being edited before history was recalled
+}
+
+// NewFormField creates a field pre-filled with value.
+func NewFormField(label, value, placeholder string) FormField {
+	in := textinput.New()
+	in.Prompt = ""
+	in.Placeholder = placeholder
+	in.CharLimit = 512
+	in.SetValue(value)
+	return FormField{Label: label, Input: in}
+}
+
+// WithHistory returns the field with previous values to recall.
+func (f FormField) WithHistory(history []string) FormField {
+	f.History = history
+	return f
+}
+
+// Form is a centered overlay that collects one or more text values.
+type Form struct {
+	Title  string
+	Fields []FormField
+	// Submit receives the field values (in field order) when the form is confirmed.
+	Submit  func(m Model, values []string) (Model, tea.Cmd)
+	focused int
+}
+
+// NewForm creates a form with focus on its first field.
+func NewForm(title string, submit func(m Model, values []string) (Model, tea.Cmd), fields ...FormField) *Form {
+	f := &Form{Title: title, Fields: fields, Submit: submit}
+	f.focus(0)
+	return f
+}
+
+// focus moves input focus to field i.
+func (f *Form) focus(i int) {
+	for j := range f.Fields {
+		f.Fields[j].Input.Blur()
+	}
+	f.focused = i
+	if i >= 0 && i < len(f.Fields) {
+		f.Fields[i].Input.Focus()
+		f.Fields[i].Input.CursorEnd()
+	}
+}
+
+// FocusNext moves focus to the next field (wraps).
+func (f *Form) FocusNext() {
+	f.focus((f.focused + 1) % len(f.Fields))
+}
+
+// FocusPrev moves focus to the previous field (wraps).
+func (f *Form) FocusPrev() {
+	f.focus((f.focused - 1 + len(f.Fields)) % len(f.Fields))
+}
+
+// HistoryStep replaces the focused field's value with an older (delta > 0)
+// or newer (delta < 0) history entry, returning to the draft past the newest.
+func (f *Form) HistoryStep(delta int) {
+	field := &f.Fields[f.focused]
+	pos := field.histPos + delta
+	if pos < 0 || pos > len(field.History) || pos == field.histPos {
+		return
+	}
+	if field.histPos == 0 {
+		field.draft = field.Input.Value()
+	}
+	field.histPos = pos
+	if pos == 0 {
+		field.Input.SetValue(field.draft)
+	} else {
+		field.Input.SetValue(field.History[pos-1])
+	}
+	field.Input.CursorEnd()
+}
+
+// hasHistory reports whether any field has history to recall.
+func (f Form) hasHistory() bool {
+	for _, field := range f.Fields {
+		if len(field.History) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Values returns the trimmed value of every field in order.
+func (f *Form) Values() []string {
+	values := make([]string, len(f.Fields))
+	for i, field := range f.Fields {
+		values[i] = strings.TrimSpace(field.Input.Value())
+	}
+	return values
+}
+
+// View renders the form box.
+func (f Form) View(width int) string {
+	th := currentTheme
+
+	labelW := 0
+	for _, field := range f.Fields {
+		labelW = max(labelW, lipgloss.Width(field.Label))
+	}
+
+	var lines []string
+	for i, field := range f.Fields {
+		label := th.CardTitleStyle.Render(field.Label + strings.Repeat(" ", labelW-lipgloss.Width(field.Label)))
+		style := th.FilterStyle
+		if i != f.focused {
+			style = style.Background(lipgloss.Color(colorMantle))
+		}
+		lines = append(lines, label+"  "+style.Render(field.Input.View()))
+	}
+
+	hintText := "tab/↑↓ switch field  enter submit  esc cancel"
+	if f.hasHistory() {
+		hintText += "  ctrl+p/n history"
+	}
+	hint := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(colorMuted)).
+		Render(hintText)
+
+	inner := lipgloss.JoinVertical(
+		lipgloss.Left,
+		th.ModalTitleStyle.Render(f.Title),
+		strings.Join(lines, "\n"),
+		"",
+		hint,
+	)
+
+	boxW := width - 8
+	if boxW < 40 {
+		boxW = 40
+	}
+	if boxW > 80 {
+		boxW = 80
+	}
+
+	box := th.ModalBoxStyle.Width(boxW).Render(inner)
+
+	leftPad := (width - lipgloss.Width(box)) / 2
+	if leftPad < 0 {
+		leftPad = 0
+	}
+	return lipgloss.NewStyle().PaddingLeft(leftPad).Render(box)
+}
+
+// formKeys are the key bindings active while a form is open.
+var formKeys = struct {
+	Next        key.Binding
+	Prev        key.Binding
+	Submit      key.Binding
+	Cancel      key.Binding
+	HistoryPrev key.Binding
+	HistoryNext key.Binding
+}{
+	Next:        key.NewBinding(key.WithKeys("tab", "down")),
+	Prev:        key.NewBinding(key.WithKeys("shift+tab", "up")),
+	Submit:      key.NewBinding(key.WithKeys("enter")),
+	Cancel:      key.NewBinding(key.WithKeys("esc")),
+	HistoryPrev: key.NewBinding(key.WithKeys("ctrl+p")),
+	HistoryNext: key.NewBinding(key.WithKeys("ctrl+n")),
+}
+
+// handleFormKey processes key input when a form is open.
+func (m Model) handleFormKey(msg tea.KeyPressMsg) (Model, tea.Cmd) {
+	f := m.form
+	if f == nil {
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(msg, formKeys.Cancel):
+		m.form = nil
+		m.statusMessage = "Cancelled."
+		return m, nil
+	case key.Matches(msg, formKeys.Submit):
+		m.form = nil
+		if f.Submit == nil {
+			return m, nil
+		}
+		return f.Submit(m, f.Values())
+	case key.Matches(msg, formKeys.Next):
+		f.FocusNext()
+		return m, nil
+	case key.Matches(msg, formKeys.Prev):
+		f.FocusPrev()
+		return m, nil
+	case key.Matches(msg, formKeys.HistoryPrev):
+		f.HistoryStep(1)
+		return m, nil
+	case key.Matches(msg, formKeys.HistoryNext):
+		f.HistoryStep(-1)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	f.Fields[f.focused].Input, cmd = f.Fields[f.focused].Input.Update(msg)
+	return m, cmd
+}
+
+// renderForm overlays the form centered on a background string.
+func (m Model) renderForm(bg string) string {
+	if m.form == nil {
+		return bg
+	}
+	formView := m.form.View(m.width)
+
+	bgLines := strings.Split(bg, "\n")
+	formLines := strings.Split(formView, "\n")
+
+	startY := (len(bgLines) - len(formLines)) / 2
+	if startY < 0 {
+		startY = 0
+	}
+
+	for i, line := range formLines {
+		idx := startY + i
+		if idx < len(bgLines) {
+			bgLines[idx] = line
+		} else {
+			bgLines = append(bgLines, line)
+		}
+	}
+
+	return strings.Join(bgLines, "\n")
+}
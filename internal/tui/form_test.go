@@ -0,0 +1,96 @@
+package tui
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForm_focusCyclesFields(t *testing.T) {
+	f := NewForm("Test", nil,
+		NewFormField("A", "", ""),
+		NewFormField("B", "", ""),
+	)
+
+	assert.True(t, f.Fields[0].Input.Focused())
+	f.FocusNext()
+	assert.True(t, f.Fields[1].Input.Focused())
+	assert.False(t, f.Fields[0].Input.Focused())
+	f.FocusNext()
+	assert.True(t, f.Fields[0].Input.Focused())
+	f.FocusPrev()
+	assert.True(t, f.Fields[1].Input.Focused())
+}
+
+func TestForm_valuesAreTrimmed(t *testing.T) {
+	f := NewForm("Test", nil, NewFormField("Path", "  /etc/hosts ", ""))
+
+	assert.Equal(t, []string{"/etc/hosts"}, f.Values())
+}
+
+func TestHandleFormKey_submitPassesValues(t *testing.T) {
+	m := InitialModel()
+	var got []string
+	m.form = NewForm("Test", func(m Model, values []string) (Model, tea.Cmd) {
+		got = values
+		m.statusMessage = "submitted"
+		return m, nil
+	}, NewFormField("Path", "/etc", ""))
+
+	result, _ := updateModel(t, m, tea.KeyPressMsg{Code: tea.KeyEnter})
+
+	assert.Nil(t, result.form)
+	require.Equal(t, []string{"/etc"}, got)
+	assert.Equal(t, "submitted", result.statusMessage)
+}
+
+func TestHandleFormKey_escCancels(t *testing.T) {
+	m := InitialModel()
+	called := false
+	m.form = NewForm("Test", func(m Model, values []string) (Model, tea.Cmd) {
+		called = true
+		return m, nil
+	}, NewFormField("Path", "", ""))
+
+	result, _ := updateModel(t, m, tea.KeyPressMsg{Code: tea.KeyEscape})
+
+	assert.Nil(t, result.form)
+	assert.False(t, called)
+	assert.Equal(t, "Cancelled.", result.statusMessage)
+}
+
+func TestHandleFormKey_typingUpdatesFocusedField(t *testing.T) {
+	m := InitialModel()
+	m.form = NewForm("Test", nil, NewFormField("Path", "/et", ""))
+
+	result, _ := updateModel(t, m, tea.KeyPressMsg{Code: 'c', Text: "c"})
+
+	require.NotNil(t, result.form)
+	assert.Equal(t, "/etc", result.form.Fields[0].Input.Value())
+}
+
+func TestForm_historyStepRecallsAndRestoresDraft(t *testing.T) {
+	f := NewForm("Test", nil, NewFormField("Command", "ec", "").WithHistory([]string{"env", "ps aux"}))
+
+	f.HistoryStep(1)
+	assert.Equal(t, "env", f.Fields[0].Input.Value())
+	f.HistoryStep(1)
+	assert.Equal(t, "ps aux", f.Fields[0].Input.Value())
+	f.HistoryStep(1) // past the oldest entry: unchanged
+	assert.Equal(t, "ps aux", f.Fields[0].Input.Value())
+	f.HistoryStep(-1)
+	f.HistoryStep(-1)
+	assert.Equal(t, "ec", f.Fields[0].Input.Value())
+}
+
+func TestHandleFormKey_ctrlPRecallsHistory(t *testing.T) {
+	m := InitialModel()
+	m.form = NewForm("Test", nil, NewFormField("Command", "", "").WithHistory([]string{"env"}))
+
+	result, _ := updateModel(t, m, tea.KeyPressMsg{Code: 'p', Mod: tea.ModCtrl})
+
+	require.NotNil(t, result.form)
+	assert.Equal(t, "env", result.form.Fields[0].Input.Value())
+}
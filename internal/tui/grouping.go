@@ -0,0 +1,72 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+// setGrouping regroups the containers view, switching to the collapse state
+// remembered for the new grouping.
+func (m Model) setGrouping(g Grouping) (Model, tea.Cmd) {
+	m.grouping = g
+	m.collapsedGroups = collapsedFor(m.groupCollapsed, g)
+	m.containerCursor = 0
+	m.recomputeRows()
+	m.persistState()
+	m.statusMessage = fmt.Sprintf("Grouped by %s.", g.Describe())
+	return m, nil
+}
+
+// NewGroupingQuickMenu builds the menu choosing how containers are grouped;
+// current is marked.
+func NewGroupingQuickMenu(current Grouping) *QuickMenu {
+	item := func(label, key string, g Grouping) QuickMenuItem {
+		if g.Kind == current.Kind {
+			label += "  ✓"
+		}
+		return QuickMenuItem{
+			Label: label,
+			Key:   key,
+			Action: func(m Model) (Model, tea.Cmd) {
+				return m.setGrouping(g)
+			},
+		}
+	}
+	label := item("Label…", "l", Grouping{Kind: GroupByLabel})
+	if current.Kind == GroupByLabel {
+		label.Label = fmt.Sprintf("Label %s…  ✓", current.Label)
+	}
+	label.Action = func(m Model) (Model, tea.Cmd) {
+		m.form = newGroupLabelForm(current.Label)
+		return m, nil
+	}
+	return &QuickMenu{
+		Title: "Group by",
+		Items: []QuickMenuItem{
+			item("Compose project", "c", Grouping{Kind: GroupByCompose}),
+			label,
+			item("Image", "i", Grouping{Kind: GroupByImage}),
+			item("Network", "n", Grouping{Kind: GroupByNetwork}),
+			item("State", "s", Grouping{Kind: GroupByState}),
+			item("None", "x", Grouping{Kind: GroupByNone}),
+		},
+	}
+}
+
+// newGroupLabelForm asks for the label key to group containers by.
+func newGroupLabelForm(current string) *Form {
+	return NewForm(
+		"Group by label",
+		func(m Model, values []string) (Model, tea.Cmd) {
+			key := strings.TrimSpace(values[0])
+			if key == "" {
+				m.statusMessage = "A label key is required."
+				return m, nil
+			}
+			return m.setGrouping(Grouping{Kind: GroupByLabel, Label: key})
+		},
+		NewFormField("Label key", current, "com.docker.stack.namespace"),
+	)
+}
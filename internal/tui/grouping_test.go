@@ -0,0 +1,76 @@
+package tui
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/rluders/berth/internal/controller"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func groupingModel(t *testing.T) Model {
+	t.Helper()
+	useStateFile(t)
+	t.Setenv("HOME", t.TempDir())
+
+	m := InitialModel()
+	web := makeContainer("a", "web", "nginx", "running", "shop")
+	web.Labels["team"] = "front"
+	m.containers = []controller.Container{
+		web,
+		makeContainer("b", "db", "postgres", "exited", "shop"),
+		makeContainer("c", "solo", "nginx", "running", ""),
+	}
+	m.recomputeRows()
+	return m
+}
+
+func TestGroupingMenu_collapseStatePerGrouping(t *testing.T) {
+	m := groupingModel(t)
+	m.moveContainerCursor(0)
+	m, _ = updateModel(t, m, tea.KeyPressMsg{Code: tea.KeyLeft})
+	require.True(t, m.collapsedGroups["shop"])
+
+	m = typeKeys(t, m, "gi")
+	assert.Equal(t, Grouping{Kind: GroupByImage}, m.grouping)
+	assert.Equal(t, "Grouped by image.", m.statusMessage)
+	require.Len(t, m.rows, 5)
+	assert.Equal(t, "nginx", m.rows[0].GroupID)
+	assert.False(t, m.rows[0].Collapsed, "each grouping has its own collapse state")
+
+	m.moveContainerCursor(-10)
+	m, _ = updateModel(t, m, tea.KeyPressMsg{Code: tea.KeyLeft})
+	require.True(t, m.collapsedGroups["nginx"])
+
+	m = typeKeys(t, m, "gc")
+	assert.True(t, m.rows[0].Collapsed, "the compose collapse state is back")
+
+	state := loadState()
+	assert.Equal(t, "compose", state.Grouping)
+	assert.Equal(t, map[string]bool{"shop": true}, state.CollapsedGroups)
+	assert.Equal(t, map[string]map[string]bool{"image": {"nginx": true}}, state.GroupCollapsed)
+
+	restored := InitialModel()
+	restored.containers = m.containers
+	restored = typeKeys(t, restored, "gi")
+	assert.True(t, restored.rows[0].Collapsed, "collapse state survives a restart")
+}
+
+func TestGroupingMenu_byLabel(t *testing.T) {
+	m := groupingModel(t)
+
+	m = typeKeys(t, m, "gl")
+	require.NotNil(t, m.form)
+	m.form.Fields[0].Input.SetValue("team")
+	m, _ = updateModel(t, m, tea.KeyPressMsg{Code: tea.KeyEnter})
+
+	assert.Equal(t, Grouping{Kind: GroupByLabel, Label: "team"}, m.grouping)
+	require.Len(t, m.rows, 4)
+	assert.Equal(t, "front", m.rows[0].GroupID)
+	assert.Equal(t, "label:team", loadState().Grouping)
+
+	m.moveContainerCursor(-10)
+	m = typeKeys(t, m, "u")
+	assert.Empty(t, m.composeOps, "compose actions only apply to compose projects")
+}
@@ -30,6 +30,14 @@ type ContainerKeys struct {
 	Expand       key.Binding
 	Collapse     key.Binding
 	QuickActions key.Binding
+	Changes      key.Binding
+	Run          key.Binding
+	Top          key.Binding
+	Stats        key.Binding
+	Unhealthy    key.Binding
+	Mark         key.Binding
+	MultiLogs    key.Binding
+	Group        key.Binding
 }
 
 // ComposeKeys holds key bindings for compose project-level actions.
@@ -40,6 +48,59 @@ type ComposeKeys struct {
 	Down     key.Binding
 	Pull     key.Binding
 	Build    key.Binding
+	Service  key.Binding // actions on the service of a compose container
+	History  key.Binding
+	Options  key.Binding // up after choosing profiles and env files
+	Drift    key.Binding
+}
+
+// DriftKeys holds key bindings for the compose drift view.
+type DriftKeys struct {
+	Refresh key.Binding
+	Up      key.Binding
+}
+
+// ComposeOpsKeys holds key bindings for the compose operations history.
+type ComposeOpsKeys struct {
+	Output key.Binding
+	Rerun  key.Binding
+	All    key.Binding
+}
+
+// ChangesKeys holds key bindings for the container changes view.
+type ChangesKeys struct {
+	CopyOut key.Binding
+	CopyIn  key.Binding
+	Refresh key.Binding
+}
+
+// ExecOutputKeys holds key bindings for the one-off command output view.
+type ExecOutputKeys struct {
+	Rerun       key.Binding
+	New         key.Binding
+	History     key.Binding
+	Stop        key.Binding
+	LineNumbers key.Binding
+}
+
+// DetailsKeys holds key bindings for the container details view.
+type DetailsKeys struct {
+	Next   key.Binding
+	Prev   key.Binding
+	Toggle key.Binding
+	Reveal key.Binding
+}
+
+// TopKeys holds key bindings for the container process list view.
+type TopKeys struct {
+	Signal key.Binding
+	Filter key.Binding
+}
+
+// TableKeys holds the sort key bindings shared by the table views.
+type TableKeys struct {
+	Sort    key.Binding
+	Reverse key.Binding
 }
 
 // ImageKeys holds key bindings for the images view.
@@ -72,6 +133,19 @@ type LogsKeys struct {
 	Pause       key.Binding
 	Follow      key.Binding
 	LineNumbers key.Binding
+	Search      key.Binding
+	NextMatch   key.Binding
+	PrevMatch   key.Binding
+	Filter      key.Binding
+	Range       key.Binding
+	Timestamps  key.Binding
+	Reload      key.Binding
+	Save        key.Binding
+	Streams     key.Binding
+	Level       key.Binding
+	FieldFilter key.Binding
+	Columns     key.Binding
+	Mute        key.Binding
 }
 
 // ConfirmKeys holds key bindings for the confirm dialog.
@@ -87,16 +161,23 @@ type FilterKeys struct {
 
 // Keys is the global key binding registry.
 var Keys = struct {
-	Global    GlobalKeys
-	Container ContainerKeys
-	Compose   ComposeKeys
-	Image     ImageKeys
-	Volume    VolumeKeys
-	Network   NetworkKeys
-	System    SystemKeys
-	Logs      LogsKeys
-	Confirm   ConfirmKeys
-	Filter    FilterKeys
+	Global     GlobalKeys
+	Container  ContainerKeys
+	Compose    ComposeKeys
+	ComposeOps ComposeOpsKeys
+	Drift      DriftKeys
+	Changes    ChangesKeys
+	Exec       ExecOutputKeys
+	Top        TopKeys
+	Table      TableKeys
+	Details    DetailsKeys
+	Image      ImageKeys
+	Volume     VolumeKeys
+	Network    NetworkKeys
+	System     SystemKeys
+	Logs       LogsKeys
+	Confirm    ConfirmKeys
+	Filter     FilterKeys
 }{
 	Global: GlobalKeys{
 		Quit: key.NewBinding(
@@ -171,7 +252,7 @@ var Keys = struct {
 		),
 		Exec: key.NewBinding(
 			key.WithKeys("e"),
-			key.WithHelp("e", "exec shell"),
+			key.WithHelp("e", "exec"),
 		),
 		Filter: key.NewBinding(
 			key.WithKeys("/"),
@@ -189,6 +270,38 @@ var Keys = struct {
 			key.WithKeys("space"),
 			key.WithHelp("space", "actions"),
 		),
+		Changes: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "fs changes"),
+		),
+		Run: key.NewBinding(
+			key.WithKeys("!"),
+			key.WithHelp("!", "run command"),
+		),
+		Top: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "processes"),
+		),
+		Stats: key.NewBinding(
+			key.WithKeys("S"),
+			key.WithHelp("S", "stats"),
+		),
+		Unhealthy: key.NewBinding(
+			key.WithKeys("H"),
+			key.WithHelp("H", "unhealthy only"),
+		),
+		Mark: key.NewBinding(
+			key.WithKeys("m"),
+			key.WithHelp("m", "mark for logs"),
+		),
+		Group: key.NewBinding(
+			key.WithKeys("g"),
+			key.WithHelp("g", "group by"),
+		),
+		MultiLogs: key.NewBinding(
+			key.WithKeys("L"),
+			key.WithHelp("L", "logs of marked/filtered"),
+		),
 	},
 	Compose: ComposeKeys{
 		Up: key.NewBinding(
@@ -215,6 +328,120 @@ var Keys = struct {
 			key.WithKeys("b"),
 			key.WithHelp("b", "compose build"),
 		),
+		Service: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "service actions"),
+		),
+		History: key.NewBinding(
+			key.WithKeys("h"),
+			key.WithHelp("h", "compose history"),
+		),
+		Options: key.NewBinding(
+			key.WithKeys("E"),
+			key.WithHelp("E", "compose up with profiles/env files"),
+		),
+		Drift: key.NewBinding(
+			key.WithKeys("D"),
+			key.WithHelp("D", "compose drift"),
+		),
+	},
+	ComposeOps: ComposeOpsKeys{
+		Output: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "output"),
+		),
+		Rerun: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "re-run"),
+		),
+		All: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "all projects"),
+		),
+	},
+	Drift: DriftKeys{
+		Refresh: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "refresh"),
+		),
+		Up: key.NewBinding(
+			key.WithKeys("u"),
+			key.WithHelp("u", "compose up"),
+		),
+	},
+	Changes: ChangesKeys{
+		CopyOut: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "copy to host"),
+		),
+		CopyIn: key.NewBinding(
+			key.WithKeys("i"),
+			key.WithHelp("i", "copy into container"),
+		),
+		Refresh: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "refresh"),
+		),
+	},
+	Exec: ExecOutputKeys{
+		Rerun: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "re-run"),
+		),
+		New: key.NewBinding(
+			key.WithKeys("!"),
+			key.WithHelp("!", "new command"),
+		),
+		History: key.NewBinding(
+			key.WithKeys("h"),
+			key.WithHelp("h", "history"),
+		),
+		Stop: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "stop"),
+		),
+		LineNumbers: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "line numbers"),
+		),
+	},
+	Top: TopKeys{
+		Signal: key.NewBinding(
+			key.WithKeys("K"),
+			key.WithHelp("K", "send signal"),
+		),
+		Filter: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "filter"),
+		),
+	},
+	Table: TableKeys{
+		Sort: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "sort column"),
+		),
+		Reverse: key.NewBinding(
+			key.WithKeys("O"),
+			key.WithHelp("O", "reverse sort"),
+		),
+	},
+	Details: DetailsKeys{
+		Next: key.NewBinding(
+			key.WithKeys("]"),
+			key.WithHelp("]", "next section"),
+		),
+		Prev: key.NewBinding(
+			key.WithKeys("["),
+			key.WithHelp("[", "prev section"),
+		),
+		Toggle: key.NewBinding(
+			key.WithKeys("enter", "space"),
+			key.WithHelp("enter", "collapse/expand"),
+		),
+		Reveal: key.NewBinding(
+			key.WithKeys("v"),
+			key.WithHelp("v", "reveal secrets"),
+		),
 	},
 	Image: ImageKeys{
 		Delete: key.NewBinding(
@@ -270,8 +497,60 @@ var Keys = struct {
 			key.WithHelp("f", "follow"),
 		),
 		LineNumbers: key.NewBinding(
+			key.WithKeys("#"),
+			key.WithHelp("#", "line numbers"),
+		),
+		Search: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "search"),
+		),
+		NextMatch: key.NewBinding(
 			key.WithKeys("n"),
-			key.WithHelp("n", "line numbers"),
+			key.WithHelp("n", "next match"),
+		),
+		PrevMatch: key.NewBinding(
+			key.WithKeys("N"),
+			key.WithHelp("N", "prev match"),
+		),
+		Filter: key.NewBinding(
+			key.WithKeys("&"),
+			key.WithHelp("&", "filter lines (regex, !inverts)"),
+		),
+		Range: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "tail/since/until"),
+		),
+		Timestamps: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "timestamps"),
+		),
+		Reload: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "reload"),
+		),
+		Save: key.NewBinding(
+			key.WithKeys("w"),
+			key.WithHelp("w", "save to file"),
+		),
+		Streams: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "stdout/stderr/both"),
+		),
+		Level: key.NewBinding(
+			key.WithKeys("l"),
+			key.WithHelp("l", "minimum level"),
+		),
+		FieldFilter: key.NewBinding(
+			key.WithKeys("="),
+			key.WithHelp("=", "filter by field (k=v, k!=v)"),
+		),
+		Columns: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "field columns"),
+		),
+		Mute: key.NewBinding(
+			key.WithKeys("m"),
+			key.WithHelp("m", "mute sources"),
 		),
 	},
 	Confirm: ConfirmKeys{
@@ -20,11 +20,13 @@ func (containersKeyMap) ShortHelp() []key.Binding {
 
 func (containersKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{Keys.Container.QuickActions, Keys.Container.Details, Keys.Container.Logs, Keys.Container.Inspect, Keys.Container.Exec},
+		{Keys.Container.QuickActions, Keys.Container.Details, Keys.Container.Logs, Keys.Container.Inspect, Keys.Container.Exec, Keys.Container.Run, Keys.Container.Top, Keys.Container.Stats, Keys.Container.Changes},
 		{Keys.Container.Start, Keys.Container.Stop, Keys.Container.Restart, Keys.Container.Delete},
-		{Keys.Container.Filter, Keys.Container.Expand, Keys.Container.Collapse},
-		{Keys.Compose.Up, Keys.Compose.UpBuild, Keys.Compose.Recreate, Keys.Compose.Down},
-		{Keys.Compose.Pull, Keys.Compose.Build},
+		{Keys.Container.Filter, Keys.Container.Unhealthy, Keys.Container.Group, Keys.Container.Expand, Keys.Container.Collapse},
+		{Keys.Table.Sort, Keys.Table.Reverse},
+		{Keys.Container.Mark, Keys.Container.MultiLogs},
+		{Keys.Compose.Up, Keys.Compose.UpBuild, Keys.Compose.Options, Keys.Compose.Recreate, Keys.Compose.Down},
+		{Keys.Compose.Pull, Keys.Compose.Build, Keys.Compose.Service, Keys.Compose.History, Keys.Compose.Drift},
 		{Keys.Global.Tab1, Keys.Global.Tab2, Keys.Global.Tab3, Keys.Global.Tab4, Keys.Global.Tab5},
 		{Keys.Global.Help, Keys.Global.Back},
 	}
@@ -40,6 +42,7 @@ func (imagesKeyMap) ShortHelp() []key.Binding {
 func (imagesKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{Keys.Image.Delete, Keys.Image.Prune, Keys.Image.Filter},
+		{Keys.Table.Sort, Keys.Table.Reverse},
 		{Keys.Global.Tab1, Keys.Global.Tab2, Keys.Global.Tab3, Keys.Global.Tab4, Keys.Global.Tab5},
 		{Keys.Global.Help, Keys.Global.Quit},
 	}
@@ -55,6 +58,7 @@ func (volumesKeyMap) ShortHelp() []key.Binding {
 func (volumesKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{Keys.Volume.Delete, Keys.Volume.Filter},
+		{Keys.Table.Sort, Keys.Table.Reverse},
 		{Keys.Global.Tab1, Keys.Global.Tab2, Keys.Global.Tab3, Keys.Global.Tab4, Keys.Global.Tab5},
 		{Keys.Global.Help, Keys.Global.Quit},
 	}
@@ -69,7 +73,7 @@ func (networksKeyMap) ShortHelp() []key.Binding {
 
 func (networksKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{Keys.Network.Inspect},
+		{Keys.Network.Inspect, Keys.Table.Sort, Keys.Table.Reverse},
 		{Keys.Global.Tab1, Keys.Global.Tab2, Keys.Global.Tab3, Keys.Global.Tab4, Keys.Global.Tab5},
 		{Keys.Global.Help, Keys.Global.Quit},
 	}
@@ -93,12 +97,116 @@ func (systemKeyMap) FullHelp() [][]key.Binding {
 type logsKeyMap struct{}
 
 func (logsKeyMap) ShortHelp() []key.Binding {
-	return []key.Binding{Keys.Logs.Pause, Keys.Logs.Follow, Keys.Global.Back}
+	return []key.Binding{Keys.Logs.Pause, Keys.Logs.Follow, Keys.Logs.Search, Keys.Logs.Filter, Keys.Global.Back}
 }
 
 func (logsKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{Keys.Logs.Pause, Keys.Logs.Follow},
+		{Keys.Logs.Pause, Keys.Logs.Follow, Keys.Logs.LineNumbers, Keys.Logs.Streams},
+		{Keys.Logs.Search, Keys.Logs.NextMatch, Keys.Logs.PrevMatch, Keys.Logs.Filter},
+		{Keys.Logs.Level, Keys.Logs.FieldFilter, Keys.Logs.Columns, Keys.Logs.Mute},
+		{Keys.Logs.Range, Keys.Logs.Timestamps, Keys.Logs.Reload, Keys.Logs.Save},
+		{Keys.Global.Back, Keys.Global.Help},
+	}
+}
+
+// changesKeyMap implements help.KeyMap for the container changes view.
+type changesKeyMap struct{}
+
+func (changesKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{Keys.Changes.CopyOut, Keys.Changes.CopyIn, Keys.Global.Back}
+}
+
+func (changesKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{Keys.Changes.CopyOut, Keys.Changes.CopyIn, Keys.Changes.Refresh},
+		{Keys.Table.Sort, Keys.Table.Reverse},
+		{Keys.Global.Back, Keys.Global.Help},
+	}
+}
+
+// execOutputKeyMap implements help.KeyMap for the one-off command output view.
+type execOutputKeyMap struct{}
+
+func (execOutputKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{Keys.Exec.Rerun, Keys.Exec.New, Keys.Exec.History, Keys.Global.Back}
+}
+
+func (execOutputKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{Keys.Exec.Rerun, Keys.Exec.New, Keys.Exec.History, Keys.Exec.Stop, Keys.Exec.LineNumbers},
+		{Keys.Global.Back, Keys.Global.Help},
+	}
+}
+
+// topKeyMap implements help.KeyMap for the container process list view.
+type topKeyMap struct{}
+
+func (topKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{Keys.Table.Sort, Keys.Top.Signal, Keys.Top.Filter, Keys.Global.Back}
+}
+
+func (topKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{Keys.Top.Signal, Keys.Top.Filter},
+		{Keys.Table.Sort, Keys.Table.Reverse},
+		{Keys.Global.Back, Keys.Global.Help},
+	}
+}
+
+// composeOpsKeyMap implements help.KeyMap for the compose history view.
+type composeOpsKeyMap struct{}
+
+func (composeOpsKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{Keys.ComposeOps.Output, Keys.ComposeOps.Rerun, Keys.ComposeOps.All, Keys.Global.Back}
+}
+
+func (composeOpsKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{Keys.ComposeOps.Output, Keys.ComposeOps.Rerun, Keys.ComposeOps.All},
+		{Keys.Table.Sort, Keys.Table.Reverse},
+		{Keys.Global.Back, Keys.Global.Help},
+	}
+}
+
+// composeOutputKeyMap implements help.KeyMap for the compose output view.
+type composeOutputKeyMap struct{}
+
+func (composeOutputKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{Keys.ComposeOps.Rerun, Keys.Global.Back}
+}
+
+func (composeOutputKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{Keys.ComposeOps.Rerun},
+		{Keys.Global.Back, Keys.Global.Help},
+	}
+}
+
+// driftKeyMap implements help.KeyMap for the compose drift view.
+type driftKeyMap struct{}
+
+func (driftKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{Keys.Drift.Up, Keys.Drift.Refresh, Keys.Global.Back}
+}
+
+func (driftKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{Keys.Drift.Up, Keys.Drift.Refresh, Keys.Table.Sort, Keys.Table.Reverse},
+		{Keys.Global.Back, Keys.Global.Help},
+	}
+}
+
+// detailsKeyMap implements help.KeyMap for the container details view.
+type detailsKeyMap struct{}
+
+func (detailsKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{Keys.Details.Next, Keys.Details.Toggle, Keys.Details.Reveal, Keys.Global.Back}
+}
+
+func (detailsKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{Keys.Details.Next, Keys.Details.Prev, Keys.Details.Toggle, Keys.Details.Reveal},
 		{Keys.Global.Back, Keys.Global.Help},
 	}
 }
@@ -134,8 +242,22 @@ func (m Model) currentKeyMap() interface {
 		return systemKeyMap{}
 	case LogsView:
 		return logsKeyMap{}
-	case InspectView, DetailsView:
+	case InspectView, StatsView:
 		return viewportKeyMap{}
+	case DetailsView:
+		return detailsKeyMap{}
+	case ChangesView:
+		return changesKeyMap{}
+	case ExecOutputView:
+		return execOutputKeyMap{}
+	case TopView:
+		return topKeyMap{}
+	case ComposeOpsView:
+		return composeOpsKeyMap{}
+	case ComposeOutputView:
+		return composeOutputKeyMap{}
+	case DriftView:
+		return driftKeyMap{}
 	}
 	return containersKeyMap{}
 }
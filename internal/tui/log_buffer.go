@@ -0,0 +1,61 @@
+package tui
+
+import "github.com/rluders/berth/internal/config"
+
+// logBuffer is a fixed-capacity ring of log lines. Once full, each new line
+// replaces the oldest one.
+type logBuffer struct {
+	lines   []logLine // grows up to capacity, then used as a ring
+	start   int       // index of the oldest line once the ring is full
+	limit   int
+	dropped int // lines dropped since the last reset
+}
+
+// newLogBuffer creates a buffer keeping at most limit lines; a limit that is
+// not positive uses the default.
+func newLogBuffer(limit int) *logBuffer {
+	if limit <= 0 {
+		limit = config.DefaultLogMaxLines
+	}
+	return &logBuffer{limit: limit}
+}
+
+// Push appends a line, dropping the oldest when the buffer is full.
+func (b *logBuffer) Push(l logLine) {
+	if len(b.lines) < b.limit {
+		b.lines = append(b.lines, l)
+		return
+	}
+	b.lines[b.start] = l
+	b.start = (b.start + 1) % b.limit
+	b.dropped++
+}
+
+// Len returns the number of lines held.
+func (b *logBuffer) Len() int {
+	return len(b.lines)
+}
+
+// At returns line i, counted from the oldest line held.
+func (b *logBuffer) At(i int) logLine {
+	return b.lines[(b.start+i)%len(b.lines)]
+}
+
+// Dropped returns how many lines were dropped since the last reset, so line
+// numbers stay stable as the buffer wraps.
+func (b *logBuffer) Dropped() int {
+	return b.dropped
+}
+
+// Total returns how many lines were received since the last reset: the
+// number the next line will have, counting from zero.
+func (b *logBuffer) Total() int {
+	return b.dropped + len(b.lines)
+}
+
+// Reset empties the buffer, releasing its lines.
+func (b *logBuffer) Reset() {
+	b.lines = nil
+	b.start = 0
+	b.dropped = 0
+}
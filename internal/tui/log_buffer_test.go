@@ -0,0 +1,127 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/rluders/berth/internal/controller"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogBuffer_wrapsAtLimit(t *testing.T) {
+	buf := newLogBuffer(3)
+	for i := range 5 {
+		buf.Push(newLogLine(controller.LogEntry{Line: fmt.Sprint(i)}))
+	}
+
+	require.Equal(t, 3, buf.Len())
+	assert.Equal(t, 2, buf.Dropped())
+	assert.Equal(t, "2", buf.At(0).Line, "oldest line kept")
+	assert.Equal(t, "4", buf.At(2).Line)
+
+	buf.Reset()
+	assert.Zero(t, buf.Len())
+	assert.Zero(t, buf.Dropped())
+}
+
+func TestLogBuffer_defaultLimit(t *testing.T) {
+	assert.Equal(t, 10000, newLogBuffer(0).limit)
+	assert.Equal(t, 10000, newLogBuffer(-1).limit)
+}
+
+func TestRenderLogRows_lineNumbersSurviveWrap(t *testing.T) {
+	buf := newLogBuffer(2)
+	for _, l := range []string{"one", "two", "three"} {
+		buf.Push(newLogLine(controller.LogEntry{Line: l}))
+	}
+
+	content, _ := renderAllLogs(buf, logRenderOptions{showLineNumbers: true, currentMatch: -1})
+
+	assert.Equal(t, "   2 │ two\n   3 │ three\n", ansi.Strip(content))
+}
+
+func TestLogsView_rendersOnlyVisibleRows(t *testing.T) {
+	m := logsViewModel()
+	m.logViewPort.SetHeight(3)
+	lines := make([]string, 100)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i)
+	}
+	m.logLines = stdoutLines(lines...)
+	m.refreshLogView()
+
+	assert.Len(t, m.logRows, 100)
+	assert.Equal(t, "line 97\nline 98\nline 99\n", ansi.Strip(m.logViewPort.GetContent()), "following shows the tail")
+
+	m, _ = updateModel(t, m, tea.KeyPressMsg{Code: tea.KeyUp})
+	assert.False(t, m.logFollowing, "scrolling up stops following")
+	assert.Equal(t, "line 96\nline 97\nline 98\n", ansi.Strip(m.logViewPort.GetContent()))
+
+	m.scrollLogs(-1000)
+	assert.Equal(t, 0, m.logTop, "scrolling stops at the top")
+
+	m = typeKeys(t, m, "f")
+	assert.Equal(t, 97, m.logTop)
+}
+
+func TestLogsView_scrolledWindowStaysPutAsBufferWraps(t *testing.T) {
+	m := logsViewModel()
+	m.logViewPort.SetHeight(2)
+	m.logLines = newLogBuffer(6)
+	for i := range 6 {
+		m.logLines.Push(newLogLine(controller.LogEntry{Line: fmt.Sprintf("line %d", i)}))
+	}
+	m.logSearch = "3"
+	m.refreshLogView()
+	m.scrollLogs(-2)
+	require.Equal(t, "line 2\nline 3\n", ansi.Strip(m.logViewPort.GetContent()))
+	require.Equal(t, []int{3}, m.logMatches)
+
+	m, _ = updateModel(t, m, logChunkMsg{entries: []controller.LogEntry{{Line: "line 6"}, {Line: "line 7"}}})
+	m, _ = updateModel(t, m, logRenderMsg{})
+
+	assert.Equal(t, "line 2\nline 3\n", ansi.Strip(m.logViewPort.GetContent()), "dropping old lines does not move the window")
+	rows, matches, _ := filterLogRows(m.logLines, m.logRenderOptions())
+	assert.Equal(t, rows, m.logRows, "new lines are filtered in, dropped ones forgotten")
+	assert.Equal(t, matches, m.logMatches)
+	assert.Equal(t, []int{1}, m.logMatches)
+}
+
+func TestWaitForLogChunkCmd_coalescesQueuedEntries(t *testing.T) {
+	ch := make(chan controller.LogEntry, 10)
+	for i := range 3 {
+		ch <- controller.LogEntry{Line: fmt.Sprint(i)}
+	}
+	close(ch)
+
+	msg := waitForLogChunkCmd(ch)()
+	chunk, ok := msg.(logChunkMsg)
+	require.True(t, ok, "got %T", msg)
+	assert.Len(t, chunk.entries, 3)
+
+	_, ok = waitForLogChunkCmd(ch)().(logStreamDoneMsg)
+	assert.True(t, ok, "the closed channel is reported next")
+}
+
+func TestHandleLogChunkMsg_coalescesRenders(t *testing.T) {
+	m := logsViewModel()
+	m.logViewPort.SetHeight(10)
+	chunk := logChunkMsg{entries: []controller.LogEntry{{Line: "a"}, {Line: "b"}}}
+
+	m, cmd := updateModel(t, m, chunk)
+	require.NotNil(t, cmd)
+	assert.True(t, m.logRenderPending)
+	assert.Equal(t, 2, m.logLines.Len())
+	assert.NotContains(t, m.logViewPort.GetContent(), "a\n", "rendering waits for the frame tick")
+
+	m, cmd = updateModel(t, m, chunk)
+	assert.Nil(t, cmd, "a render is already scheduled")
+
+	m, _ = updateModel(t, m, logRenderMsg{})
+	assert.False(t, m.logRenderPending)
+	assert.Equal(t, 4, strings.Count(ansi.Strip(m.logViewPort.GetContent()), "\n"))
+}
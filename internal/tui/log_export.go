@@ -0,0 +1,98 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/rluders/berth/internal/controller"
+)
+
+// Sources a logs view export can be read from.
+const (
+	logSourceBuffer = "buffer"
+	logSourceFull   = "full"
+)
+
+// logSource returns the name and containers of the logs in view.
+func (m Model) logSource() (string, []controller.Container) {
+	if m.currentLogSources != nil {
+		return m.currentLogSourcesTitle, m.currentLogSources
+	}
+	if m.currentLogGroupName != "" {
+		return m.currentLogGroupName, findGroupContainers(m.containers, m.currentLogGroupName)
+	}
+	c, ok := m.findContainer(m.currentLogContainerID)
+	if !ok {
+		c = controller.Container{ID: m.currentLogContainerID, Names: m.currentLogContainerID}
+	}
+	return c.Names, []controller.Container{c}
+}
+
+// bufferLogRecords converts the lines read so far into records, recovering
+// the time from engine timestamps.
+func (m Model) bufferLogRecords() []controller.LogRecord {
+	name, _ := m.logSource()
+	records := make([]controller.LogRecord, 0, m.logLines.Len())
+	for i := range m.logLines.Len() {
+		e := m.logLines.At(i)
+		if e.Marker {
+			continue
+		}
+		r := controller.LogRecord{Container: e.ContainerName, Stream: e.Stream, Line: e.Line}
+		if r.Container == "" {
+			r.Container = name
+		}
+		if m.logOptions.Timestamps {
+			if ts, text, ok := controller.SplitLogTimestamp(r.Line); ok {
+				r.Time, r.Line = ts, text
+			}
+		}
+		if r.Time.IsZero() {
+			r.Time = e.Time
+		}
+		records = append(records, r)
+	}
+	return records
+}
+
+// newSaveLogsForm prompts for where and how to save the logs in view.
+func newSaveLogsForm(dir string) *Form {
+	if dir == "" {
+		dir = "."
+	}
+	return NewForm(
+		"Save logs",
+		func(m Model, values []string) (Model, tea.Cmd) {
+			dir := strings.TrimSpace(values[0])
+			format := strings.ToLower(strings.TrimSpace(values[1]))
+			source := strings.ToLower(strings.TrimSpace(values[2]))
+			if dir == "" {
+				m.statusMessage = "Save cancelled: a directory is required."
+				return m, nil
+			}
+			if format != controller.LogFormatText && format != controller.LogFormatJSONL {
+				m.statusMessage = fmt.Sprintf("Save cancelled: format must be %s or %s.", controller.LogFormatText, controller.LogFormatJSONL)
+				return m, nil
+			}
+			if source != logSourceBuffer && source != logSourceFull {
+				m.statusMessage = fmt.Sprintf("Save cancelled: source must be %s or %s.", logSourceBuffer, logSourceFull)
+				return m, nil
+			}
+			m.logExportDir = dir
+			m.persistState()
+
+			name, containers := m.logSource()
+			m.showSpinner = true
+			if source == logSourceFull {
+				m.statusMessage = fmt.Sprintf("Fetching logs of %s (%s)...", name, m.logOptions.Summary())
+				return m, tea.Batch(fetchAndSaveLogsCmd(dir, name, containers, m.logOptions, format), m.spinner.Tick)
+			}
+			m.statusMessage = fmt.Sprintf("Saving logs of %s...", name)
+			return m, tea.Batch(saveLogsCmd(dir, name, m.bufferLogRecords(), format), m.spinner.Tick)
+		},
+		NewFormField("Directory", dir, "./logs"),
+		NewFormField("Format (text/jsonl)", controller.LogFormatText, controller.LogFormatText),
+		NewFormField("Source (buffer/full)", logSourceBuffer, logSourceBuffer),
+	)
+}
@@ -0,0 +1,97 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/rluders/berth/internal/controller"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBufferLogRecords_groupWithTimestamps(t *testing.T) {
+	m := InitialModel()
+	m.currentLogGroupName = "shop"
+	m.logOptions.Timestamps = true
+	m.logLines = toLogLines(
+		controller.LogEntry{ContainerName: "shop-web-1", Stream: controller.StreamStdout, Line: "2024-05-01T10:00:01Z listening"},
+		controller.LogEntry{ContainerName: "shop-db-1", Stream: controller.StreamStderr, Line: "ready"},
+	)
+
+	records := m.bufferLogRecords()
+
+	assert.Equal(t, []controller.LogRecord{
+		{Container: "shop-web-1", Stream: "stdout", Time: time.Date(2024, 5, 1, 10, 0, 1, 0, time.UTC), Line: "listening"},
+		{Container: "shop-db-1", Stream: "stderr", Line: "ready"},
+	}, records)
+}
+
+func TestBufferLogRecords_singleContainer(t *testing.T) {
+	m := InitialModel()
+	m.containers = []controller.Container{{ID: "abc123", Names: "web"}}
+	m.currentLogContainerID = "abc123"
+	m.logLines = stdoutLines("[not a group] line")
+
+	records := m.bufferLogRecords()
+
+	assert.Equal(t, []controller.LogRecord{{Container: "web", Stream: "stdout", Line: "[not a group] line"}}, records)
+}
+
+func TestBufferLogRecords_skipsRestartMarkers(t *testing.T) {
+	m := InitialModel()
+	m.currentLogContainerID = "abc123"
+	m.logLines = toLogLines(
+		controller.LogEntry{Line: "before"},
+		controller.LogEntry{Line: "--- container restarted at 10:05:00 ---", Marker: true},
+		controller.LogEntry{Line: "after"},
+	)
+
+	records := m.bufferLogRecords()
+
+	require.Len(t, records, 2)
+	assert.Equal(t, "after", records[1].Line)
+}
+
+func TestSaveLogsForm_savesBufferAndRemembersDir(t *testing.T) {
+	useStateFile(t)
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+
+	m := logsViewModel()
+	m.logLines = toLogLines(controller.LogEntry{Line: "hello"}, controller.LogEntry{Line: "world"})
+	m, _ = updateModel(t, m, tea.KeyPressMsg{Code: 'w', Text: "w"})
+	require.NotNil(t, m.form)
+	m.form.Fields[0].Input.SetValue(dir)
+	m.form.Fields[1].Input.SetValue("jsonl")
+
+	result, cmd := updateModel(t, m, tea.KeyPressMsg{Code: tea.KeyEnter})
+
+	assert.NotNil(t, cmd)
+	assert.Nil(t, result.form)
+	assert.Equal(t, dir, result.logExportDir)
+	assert.Equal(t, dir, loadState().LogExportDir)
+
+	msg := saveLogsCmd(dir, "abc123", result.bufferLogRecords(), controller.LogFormatJSONL)()
+	require.IsType(t, statusMsg(""), msg)
+	files, err := filepath.Glob(filepath.Join(dir, "abc123-*.jsonl"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.Equal(t, `{"container":"abc123","line":"hello"}`+"\n"+`{"container":"abc123","line":"world"}`+"\n", string(data))
+}
+
+func TestSaveLogsForm_rejectsUnknownFormat(t *testing.T) {
+	m := logsViewModel()
+	m.form = newSaveLogsForm("")
+	m.form.Fields[1].Input.SetValue("xml")
+
+	result, cmd := updateModel(t, m, tea.KeyPressMsg{Code: tea.KeyEnter})
+
+	assert.Nil(t, cmd)
+	assert.Contains(t, result.statusMessage, "format must be text or jsonl")
+	assert.Empty(t, result.logExportDir)
+}
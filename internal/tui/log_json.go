@@ -0,0 +1,421 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/rluders/berth/internal/controller"
+)
+
+// logLevel is the severity of a log line; logLevelUnknown marks lines
+// without one.
+type logLevel int
+
+const (
+	logLevelUnknown logLevel = iota
+	logLevelTrace
+	logLevelDebug
+	logLevelInfo
+	logLevelWarn
+	logLevelError
+	logLevelFatal
+)
+
+func (l logLevel) String() string {
+	switch l {
+	case logLevelTrace:
+		return "TRACE"
+	case logLevelDebug:
+		return "DEBUG"
+	case logLevelInfo:
+		return "INFO"
+	case logLevelWarn:
+		return "WARN"
+	case logLevelError:
+		return "ERROR"
+	case logLevelFatal:
+		return "FATAL"
+	default:
+		return ""
+	}
+}
+
+// nextMinLevel cycles the minimum level filter: off → debug → info → warn →
+// error → off.
+func (l logLevel) nextMinLevel() logLevel {
+	switch l {
+	case logLevelUnknown:
+		return logLevelDebug
+	case logLevelError, logLevelFatal:
+		return logLevelUnknown
+	default:
+		return l + 1
+	}
+}
+
+// parseLogLevel reads a level name ("warning", "ERR") or a numeric level as
+// used by pino and bunyan (10 trace … 60 fatal).
+func parseLogLevel(v string) logLevel {
+	if n, err := strconv.Atoi(v); err == nil {
+		switch {
+		case n <= 10:
+			return logLevelTrace
+		case n <= 20:
+			return logLevelDebug
+		case n <= 30:
+			return logLevelInfo
+		case n <= 40:
+			return logLevelWarn
+		case n <= 50:
+			return logLevelError
+		default:
+			return logLevelFatal
+		}
+	}
+	v = strings.ToLower(v)
+	switch {
+	case strings.HasPrefix(v, "trace"):
+		return logLevelTrace
+	case strings.HasPrefix(v, "debug"), v == "dbg":
+		return logLevelDebug
+	case strings.HasPrefix(v, "info"), v == "notice":
+		return logLevelInfo
+	case strings.HasPrefix(v, "warn"):
+		return logLevelWarn
+	case strings.HasPrefix(v, "err"):
+		return logLevelError
+	case v == "fatal", v == "panic", strings.HasPrefix(v, "crit"), strings.HasPrefix(v, "emerg"), v == "alert":
+		return logLevelFatal
+	default:
+		return logLevelUnknown
+	}
+}
+
+// plainLogLevel guesses the level of a plain-text line from its keywords.
+func plainLogLevel(line string) logLevel {
+	lower := strings.ToLower(line)
+	switch {
+	case strings.Contains(lower, "error") || strings.Contains(lower, "fatal") || strings.Contains(lower, "panic") || strings.Contains(lower, "critical"):
+		return logLevelError
+	case strings.Contains(lower, "warn"):
+		return logLevelWarn
+	case strings.Contains(lower, "info") || strings.Contains(lower, "notice"):
+		return logLevelInfo
+	case strings.Contains(lower, "debug") || strings.Contains(lower, "trace"):
+		return logLevelDebug
+	default:
+		return logLevelUnknown
+	}
+}
+
+// levelStyle returns the colour of a level; ok is false for unknown levels.
+func levelStyle(l logLevel) (style lipgloss.Style, ok bool) {
+	th := currentTheme
+	switch l {
+	case logLevelError, logLevelFatal:
+		return th.LogErrorStyle, true
+	case logLevelWarn:
+		return th.LogWarnStyle, true
+	case logLevelInfo:
+		return th.LogInfoStyle, true
+	case logLevelTrace, logLevelDebug:
+		return th.LogDebugStyle, true
+	default:
+		return lipgloss.Style{}, false
+	}
+}
+
+// Keys holding the time, level and message of a JSON log line, in order of
+// preference.
+var (
+	jsonTimeKeys  = []string{"time", "ts", "timestamp", "@timestamp", "t"}
+	jsonLevelKeys = []string{"level", "lvl", "severity", "log.level", "loglevel"}
+	jsonMsgKeys   = []string{"msg", "message", "@message", "event"}
+)
+
+// maxLogColumnWidth caps the width of a field column.
+const maxLogColumnWidth = 24
+
+// jsonLog is a log line holding a JSON object, flattened to dotted keys
+// ({"http":{"status":500}} becomes "http.status").
+type jsonLog struct {
+	time   string
+	level  logLevel
+	msg    string
+	fields map[string]string // every key, including time, level and message
+	extra  []string          // sorted keys other than time, level and message
+}
+
+// parseJSONLog parses a line holding a JSON object; it returns nil for
+// anything else.
+func parseJSONLog(line string) *jsonLog {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, "{") || !strings.HasSuffix(trimmed, "}") {
+		return nil
+	}
+	dec := json.NewDecoder(strings.NewReader(trimmed))
+	dec.UseNumber()
+	var obj map[string]any
+	if err := dec.Decode(&obj); err != nil {
+		return nil
+	}
+
+	j := &jsonLog{fields: make(map[string]string, len(obj))}
+	flattenJSON("", obj, j.fields)
+	known := map[string]bool{}
+	pick := func(keys []string) string {
+		for _, k := range keys {
+			if v, ok := j.fields[k]; ok {
+				known[k] = true
+				return v
+			}
+		}
+		return ""
+	}
+	j.time = pick(jsonTimeKeys)
+	j.msg = pick(jsonMsgKeys)
+	for _, k := range jsonLevelKeys {
+		if v, ok := j.fields[k]; ok {
+			// An unrecognised level stays visible as a plain field.
+			if j.level = parseLogLevel(v); j.level != logLevelUnknown {
+				known[k] = true
+			}
+			break
+		}
+	}
+	for k := range j.fields {
+		if !known[k] {
+			j.extra = append(j.extra, k)
+		}
+	}
+	sort.Strings(j.extra)
+	return j
+}
+
+// flattenJSON stores the scalar values of v under dotted keys. Arrays are
+// kept as compact JSON.
+func flattenJSON(prefix string, v any, out map[string]string) {
+	obj, ok := v.(map[string]any)
+	if !ok {
+		out[prefix] = jsonValueString(v)
+		return
+	}
+	if len(obj) == 0 && prefix != "" {
+		out[prefix] = "{}"
+		return
+	}
+	for k, child := range obj {
+		if prefix != "" {
+			k = prefix + "." + k
+		}
+		flattenJSON(k, child, out)
+	}
+}
+
+// jsonValueString renders a decoded JSON value without quotes around strings.
+func jsonValueString(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(v); err != nil {
+			return fmt.Sprint(v)
+		}
+		return strings.TrimSpace(buf.String())
+	}
+}
+
+// quoteLogValue quotes values that would be ambiguous in key=val form.
+// Compact JSON arrays and objects are left as they are.
+func quoteLogValue(v string) string {
+	if strings.HasPrefix(v, "[") || strings.HasPrefix(v, "{") {
+		return v
+	}
+	if v == "" || strings.ContainsAny(v, " \t\"=") {
+		return strconv.Quote(v)
+	}
+	return v
+}
+
+// logJSONPart is one piece of a rendered JSON line with its colour.
+type logJSONPart struct {
+	text  string
+	style *lipgloss.Style
+}
+
+// parts lays a JSON line out as `time LEVEL [columns] msg key=val …`, leaving
+// out keys shown as columns.
+func (j *jsonLog) parts(columns []string, widths []int) []logJSONPart {
+	th := currentTheme
+	muted := lipgloss.NewStyle().Foreground(lipgloss.Color(colorMuted))
+	var parts []logJSONPart
+	add := func(text string, style *lipgloss.Style) {
+		if len(parts) > 0 {
+			parts = append(parts, logJSONPart{text: " "})
+		}
+		parts = append(parts, logJSONPart{text: text, style: style})
+	}
+
+	if j.time != "" {
+		add(j.time, &th.LogTimestampStyle)
+	}
+	if j.level != logLevelUnknown {
+		style, _ := levelStyle(j.level)
+		add(fmt.Sprintf("%-5s", j.level), &style)
+	}
+	shown := map[string]bool{}
+	for i, col := range columns {
+		shown[col] = true
+		v := ansi.Truncate(j.fields[col], widths[i], "…")
+		add(v+strings.Repeat(" ", max(widths[i]-ansi.StringWidth(v), 0)), &th.LogColumnStyle)
+	}
+	if j.msg != "" {
+		add(j.msg, nil)
+	}
+	for _, k := range j.extra {
+		if shown[k] {
+			continue
+		}
+		add(k+"=", &muted)
+		parts = append(parts, logJSONPart{text: quoteLogValue(j.fields[k])})
+	}
+	return parts
+}
+
+// text renders the line as plain text, as searched and filtered.
+func (j *jsonLog) text(columns []string, widths []int) string {
+	var sb strings.Builder
+	for _, p := range j.parts(columns, widths) {
+		sb.WriteString(p.text)
+	}
+	return sb.String()
+}
+
+// render renders the line with the time muted and the level coloured.
+func (j *jsonLog) render(columns []string, widths []int) string {
+	var sb strings.Builder
+	for _, p := range j.parts(columns, widths) {
+		if p.style != nil {
+			sb.WriteString(p.style.Render(p.text))
+		} else {
+			sb.WriteString(p.text)
+		}
+	}
+	return sb.String()
+}
+
+// logLine is a buffered log entry with its JSON form parsed once on arrival.
+type logLine struct {
+	controller.LogEntry
+	json *jsonLog // nil for plain text
+}
+
+// newLogLine parses e once. A JSON line read with engine timestamps is parsed
+// past the timestamp, which then stands as the line's time.
+func newLogLine(e controller.LogEntry) logLine {
+	if j := parseJSONLog(e.Line); j != nil {
+		return logLine{LogEntry: e, json: j}
+	}
+	if _, rest, ok := controller.SplitLogTimestamp(e.Line); ok {
+		if j := parseJSONLog(rest); j != nil {
+			j.time = strings.TrimSpace(strings.TrimSuffix(e.Line, rest))
+			return logLine{LogEntry: e, json: j}
+		}
+	}
+	return logLine{LogEntry: e}
+}
+
+// level returns the line's JSON level, or the one guessed from its keywords.
+func (l logLine) level() logLevel {
+	if l.json != nil {
+		return l.json.level
+	}
+	return plainLogLevel(l.Line)
+}
+
+// logColumnWidths sizes each field column to its widest value in the buffer.
+func logColumnWidths(buf *logBuffer, columns []string) []int {
+	widths := make([]int, len(columns))
+	if len(columns) == 0 {
+		return widths
+	}
+	for i := range buf.Len() {
+		widenLogColumns(widths, buf.At(i), columns)
+	}
+	return widths
+}
+
+// widenLogColumns grows widths to fit the fields of l.
+func widenLogColumns(widths []int, l logLine, columns []string) {
+	if l.json == nil {
+		return
+	}
+	for i, col := range columns {
+		widths[i] = max(widths[i], min(ansi.StringWidth(l.json.fields[col]), maxLogColumnWidth))
+	}
+}
+
+// parseLogColumns splits a comma-separated list of field names.
+func parseLogColumns(text string) []string {
+	var cols []string
+	for _, c := range strings.Split(text, ",") {
+		if c = strings.TrimSpace(c); c != "" {
+			cols = append(cols, c)
+		}
+	}
+	return cols
+}
+
+// logFieldFilter keeps JSON lines whose field equals (or, negated, hides
+// those whose field equals) a value, compared case-insensitively.
+type logFieldFilter struct {
+	text   string // as typed
+	key    string
+	value  string
+	negate bool
+}
+
+// parseLogFieldFilter parses "key=value" or "key!=value"; an empty
+// expression means no filter.
+func parseLogFieldFilter(text string) (*logFieldFilter, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, nil
+	}
+	k, v, ok := strings.Cut(text, "=")
+	if !ok || strings.TrimSpace(strings.TrimSuffix(k, "!")) == "" {
+		return nil, fmt.Errorf("invalid field filter %q: want field=value or field!=value", text)
+	}
+	f := &logFieldFilter{text: text, value: strings.TrimSpace(v)}
+	f.key, f.negate = strings.CutSuffix(strings.TrimSpace(k), "!")
+	f.key = strings.TrimSpace(f.key)
+	return f, nil
+}
+
+// keep reports whether l passes the filter. A nil filter keeps every line.
+func (f *logFieldFilter) keep(l logLine) bool {
+	if f == nil {
+		return true
+	}
+	match := false
+	if l.json != nil {
+		v, ok := l.json.fields[f.key]
+		match = ok && strings.EqualFold(v, f.value)
+	}
+	return match != f.negate
+}
@@ -0,0 +1,145 @@
+package tui
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLogLevel(t *testing.T) {
+	tests := []struct {
+		in   string
+		want logLevel
+	}{
+		{"debug", logLevelDebug},
+		{"INFO", logLevelInfo},
+		{"warning", logLevelWarn},
+		{"ERR", logLevelError},
+		{"critical", logLevelFatal},
+		{"30", logLevelInfo},
+		{"50", logLevelError},
+		{"verbose", logLevelUnknown},
+		{"", logLevelUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseLogLevel(tt.in))
+		})
+	}
+}
+
+func TestParseJSONLog(t *testing.T) {
+	j := parseJSONLog(`{"ts":"2024-05-01T10:00:01Z","level":"error","msg":"request failed","http":{"status":500,"path":"/api"},"retry":true,"tags":["a","b"]}`)
+	require.NotNil(t, j)
+	assert.Equal(t, "2024-05-01T10:00:01Z", j.time)
+	assert.Equal(t, logLevelError, j.level)
+	assert.Equal(t, "request failed", j.msg)
+	assert.Equal(t, []string{"http.path", "http.status", "retry", "tags"}, j.extra)
+	assert.Equal(t, "500", j.fields["http.status"])
+	assert.Equal(t, `["a","b"]`, j.fields["tags"])
+	assert.Equal(t, `2024-05-01T10:00:01Z ERROR request failed http.path=/api http.status=500 retry=true tags=["a","b"]`, j.text(nil, nil))
+
+	assert.Nil(t, parseJSONLog("plain text line"))
+	assert.Nil(t, parseJSONLog(`{"truncated":`))
+}
+
+func TestParseJSONLog_unknownLevelStaysAField(t *testing.T) {
+	j := parseJSONLog(`{"level":"verbose","message":"hi"}`)
+	require.NotNil(t, j)
+	assert.Equal(t, "hi level=verbose", j.text(nil, nil))
+}
+
+func TestJSONLogText_columns(t *testing.T) {
+	lines := stdoutLines(
+		`{"level":"info","msg":"a","user":"ann","status":200}`,
+		`{"level":"warn","msg":"b","user":"bartholomew","status":404}`,
+	)
+	cols := []string{"user"}
+	widths := logColumnWidths(lines, cols)
+
+	assert.Equal(t, []int{11}, widths)
+	assert.Equal(t, "INFO  ann         a status=200", lines.At(0).json.text(cols, widths))
+	assert.Equal(t, "WARN  bartholomew b status=404", lines.At(1).json.text(cols, widths))
+}
+
+func TestNewLogLine_jsonAfterEngineTimestamp(t *testing.T) {
+	lines := stdoutLines(
+		`2026-03-01T10:00:00.123456789Z {"level":"error","msg":"boom","time":"10:00","status":500}`,
+		`2026-03-01T10:00:01Z plain text`,
+	)
+
+	l := lines.At(0)
+	require.NotNil(t, l.json)
+	assert.Equal(t, logLevelError, l.level())
+	assert.Equal(t, "500", l.json.fields["status"])
+	assert.Equal(t, "2026-03-01T10:00:00.123456789Z ERROR boom status=500", l.json.text(nil, nil))
+	assert.Nil(t, lines.At(1).json)
+}
+
+func TestParseLogFieldFilter(t *testing.T) {
+	f, err := parseLogFieldFilter("")
+	require.NoError(t, err)
+	assert.Nil(t, f)
+
+	f, err = parseLogFieldFilter("status = 500")
+	require.NoError(t, err)
+	assert.Equal(t, "status", f.key)
+	assert.Equal(t, "500", f.value)
+	assert.False(t, f.negate)
+
+	f, err = parseLogFieldFilter("level!=debug")
+	require.NoError(t, err)
+	assert.Equal(t, "level", f.key)
+	assert.True(t, f.negate)
+
+	_, err = parseLogFieldFilter("status")
+	assert.ErrorContains(t, err, "invalid field filter")
+}
+
+func TestBuildColorizedLogContent_structuredFilters(t *testing.T) {
+	lines := stdoutLines(
+		`{"level":"debug","msg":"cache miss","status":200}`,
+		`{"level":"info","msg":"served","status":200}`,
+		`{"level":"error","msg":"upstream down","status":502}`,
+		"WARN plain text warning",
+		"no level here",
+	)
+
+	content, _ := renderAllLogs(lines, logRenderOptions{minLevel: logLevelWarn, currentMatch: -1})
+	assert.Equal(t, "ERROR upstream down status=502\nWARN plain text warning\n", ansi.Strip(content))
+
+	f, err := parseLogFieldFilter("status=200")
+	require.NoError(t, err)
+	content, _ = renderAllLogs(lines, logRenderOptions{field: f, columns: []string{"status"}, currentMatch: -1})
+	assert.Equal(t, "DEBUG 200 cache miss\nINFO  200 served\n", ansi.Strip(content))
+}
+
+func TestLogsView_levelFieldAndColumnKeys(t *testing.T) {
+	m := InitialModel()
+	m.currentView = LogsView
+	m.logLines = stdoutLines(`{"level":"info","msg":"ok","user":"ann"}`, `{"level":"error","msg":"boom","user":"bob"}`)
+	m.refreshLogView()
+
+	m = typeKeys(t, m, "ll")
+	assert.Equal(t, logLevelInfo, m.logMinLevel)
+
+	m = typeKeys(t, m, "=user=bob")
+	m, _ = updateModel(t, m, tea.KeyPressMsg{Code: tea.KeyEnter})
+	require.NotNil(t, m.logFieldFilter)
+
+	m = typeKeys(t, m, "cuser")
+	m, _ = updateModel(t, m, tea.KeyPressMsg{Code: tea.KeyEnter})
+	assert.Equal(t, []string{"user"}, m.logColumns)
+
+	content := ansi.Strip(m.logViewPort.GetContent())
+	assert.Contains(t, content, "ERROR bob boom")
+	assert.NotContains(t, content, "ann")
+
+	m.resetLogView()
+	assert.Equal(t, logLevelUnknown, m.logMinLevel)
+	assert.Nil(t, m.logFieldFilter)
+	assert.Nil(t, m.logColumns)
+}
@@ -0,0 +1,247 @@
+package tui
+
+import (
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/rluders/berth/internal/controller"
+)
+
+// logSourcePalette colours the container prefixes of multiplexed logs. Red is
+// left out so a prefix is never mistaken for an error.
+var logSourcePalette = []string{
+	colorBlue, colorGreen, colorPeach, colorMauve,
+	colorSky, colorYellow, colorTeal, colorLavend,
+}
+
+// logSourceStyle returns the colour of a container's prefix. It is derived
+// from the name, so a container keeps its colour across streams and reloads.
+func logSourceStyle(name string) lipgloss.Style {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	color := logSourcePalette[h.Sum32()%uint32(len(logSourcePalette))]
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(color))
+}
+
+// renderLogEntryPrefix renders the container name prefix in its colour.
+func renderLogEntryPrefix(e controller.LogEntry) string {
+	if e.ContainerName == "" {
+		return ""
+	}
+	return logSourceStyle(e.ContainerName).Render(logEntryPrefix(e))
+}
+
+// labelSelector matches containers by label: "key=value" requires the value,
+// a bare "key" only the label's presence.
+type labelSelector map[string]*string
+
+// parseLabelSelector parses comma-separated key=value or key terms.
+func parseLabelSelector(text string) (labelSelector, error) {
+	sel := labelSelector{}
+	for _, term := range strings.Split(text, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		k, v, hasValue := strings.Cut(term, "=")
+		k = strings.TrimSpace(k)
+		if k == "" {
+			return nil, fmt.Errorf("invalid label selector %q: want key=value or key", term)
+		}
+		if hasValue {
+			v = strings.TrimSpace(v)
+			sel[k] = &v
+		} else {
+			sel[k] = nil
+		}
+	}
+	return sel, nil
+}
+
+// matches reports whether c carries every label of the selector.
+func (s labelSelector) matches(c controller.Container) bool {
+	for k, want := range s {
+		got, ok := c.Labels[k]
+		if !ok || (want != nil && got != *want) {
+			return false
+		}
+	}
+	return true
+}
+
+// toggleMark marks or unmarks the container under the cursor for multiplexed
+// logs. On a group row every container of the group follows the first one.
+func (m *Model) toggleMark(row Row) {
+	containers := row.Containers
+	if row.Type == RowTypeContainer {
+		containers = []controller.Container{*row.Container}
+	}
+	if len(containers) == 0 {
+		return
+	}
+	mark := !m.markedContainers[containers[0].ID]
+	for _, c := range containers {
+		if mark {
+			m.markedContainers[c.ID] = true
+		} else {
+			delete(m.markedContainers, c.ID)
+		}
+	}
+	m.statusMessage = fmt.Sprintf("%d container(s) marked for logs", len(m.markedContainers))
+	m.syncContainerViewport()
+}
+
+// marked returns the marked containers that still exist, in list order.
+func (m Model) marked() []controller.Container {
+	var result []controller.Container
+	for _, c := range m.containers {
+		if m.markedContainers[c.ID] {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// openMultiLogs opens the marked containers' logs, or prompts for a label
+// selector when none are marked.
+func (m Model) openMultiLogs() (Model, tea.Cmd) {
+	if marked := m.marked(); len(marked) > 0 {
+		return m.openSourceLogs(fmt.Sprintf("%d marked", len(marked)), marked)
+	}
+	m.form = newMultiLogsForm()
+	return m, nil
+}
+
+// openSourceLogs switches to the logs view and streams a hand-picked set of
+// containers interleaved in one view.
+func (m Model) openSourceLogs(title string, containers []controller.Container) (Model, tea.Cmd) {
+	m.stopLogStream()
+	m.resetLogView()
+	m.currentLogContainerID = ""
+	m.currentLogGroupName = ""
+	m.currentLogSources = containers
+	m.currentLogSourcesTitle = title
+	m.pushView(LogsView)
+	m.logReady = true
+	return m.restartLogStream()
+}
+
+// newMultiLogsForm prompts for the label selector of a multiplexed log view.
+// Leaving it empty streams every container the current filter shows.
+func newMultiLogsForm() *Form {
+	return NewForm(
+		"Multiplexed logs",
+		func(m Model, values []string) (Model, tea.Cmd) {
+			text := strings.TrimSpace(values[0])
+			sel, err := parseLabelSelector(text)
+			if err != nil {
+				m.statusMessage = err.Error()
+				return m, nil
+			}
+			var containers []controller.Container
+			for _, c := range m.filteredContainers() {
+				if sel.matches(c) {
+					containers = append(containers, c)
+				}
+			}
+			if len(containers) == 0 {
+				m.statusMessage = "No containers match."
+				return m, nil
+			}
+			title := text
+			if title == "" {
+				title = "all containers"
+				if f := m.filterInput.Value(); f != "" {
+					title = "filter " + f
+				}
+			}
+			return m.openSourceLogs(title, containers)
+		},
+		NewFormField("Label selector", "", "app=web,tier (empty for the containers listed)"),
+	)
+}
+
+// logTitle names the logs in view.
+func (m Model) logTitle() string {
+	switch {
+	case m.currentLogSources != nil:
+		return m.currentLogSourcesTitle
+	case m.currentLogGroupName != "":
+		return m.currentLogGroupName
+	default:
+		return m.currentLogContainerID
+	}
+}
+
+// logSourceNames returns the container names of a multi-container stream, in
+// the order they are numbered for muting; nil for a single container.
+func (m Model) logSourceNames() []string {
+	if m.currentLogSources == nil && m.currentLogGroupName == "" {
+		return nil
+	}
+	_, containers := m.logSource()
+	names := make([]string, len(containers))
+	for i, c := range containers {
+		names[i] = c.Names
+	}
+	return names
+}
+
+// parseMutedSources reads the sources to mute as numbers (as listed in the
+// indicator) or names, comma separated.
+func parseMutedSources(text string, names []string) (map[string]bool, error) {
+	muted := map[string]bool{}
+	for _, term := range strings.Split(text, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		if n, err := strconv.Atoi(term); err == nil {
+			if n < 1 || n > len(names) {
+				return nil, fmt.Errorf("no source %d: there are %d", n, len(names))
+			}
+			muted[names[n-1]] = true
+			continue
+		}
+		if !slices.Contains(names, term) {
+			return nil, fmt.Errorf("unknown source %q", term)
+		}
+		muted[term] = true
+	}
+	return muted, nil
+}
+
+// mutedSourceList returns the muted sources in listing order, for the prompt.
+func (m Model) mutedSourceList() []string {
+	var list []string
+	for _, name := range m.logSourceNames() {
+		if m.logMuted[name] {
+			list = append(list, name)
+		}
+	}
+	return list
+}
+
+// renderLogSources lists the sources of a multi-container stream with their
+// numbers and colours; muted ones are struck through.
+func (m Model) renderLogSources() string {
+	names := m.logSourceNames()
+	if len(names) < 2 {
+		return ""
+	}
+	muted := lipgloss.NewStyle().Foreground(lipgloss.Color(colorMuted)).Strikethrough(true)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		style := logSourceStyle(name)
+		if m.logMuted[name] {
+			style = muted
+		}
+		parts[i] = fmt.Sprintf("%d ", i+1) + style.Render(name)
+	}
+	return strings.Join(parts, "  ")
+}
@@ -0,0 +1,113 @@
+package tui
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/rluders/berth/internal/controller"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLabelSelector(t *testing.T) {
+	sel, err := parseLabelSelector("app=web, tier")
+	require.NoError(t, err)
+
+	assert.True(t, sel.matches(controller.Container{Labels: map[string]string{"app": "web", "tier": "front"}}))
+	assert.False(t, sel.matches(controller.Container{Labels: map[string]string{"app": "db", "tier": "back"}}))
+	assert.False(t, sel.matches(controller.Container{Labels: map[string]string{"app": "web"}}), "bare key must be present")
+
+	empty, err := parseLabelSelector("")
+	require.NoError(t, err)
+	assert.True(t, empty.matches(controller.Container{}), "empty selector matches everything")
+
+	_, err = parseLabelSelector("=web")
+	assert.Error(t, err)
+}
+
+func TestParseMutedSources(t *testing.T) {
+	names := []string{"web", "db", "cache"}
+
+	muted, err := parseMutedSources("1, cache", names)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"web": true, "cache": true}, muted)
+
+	_, err = parseMutedSources("4", names)
+	assert.Error(t, err)
+	_, err = parseMutedSources("worker", names)
+	assert.Error(t, err)
+}
+
+func TestLogSourceStyle_isStable(t *testing.T) {
+	assert.Equal(t, logSourceStyle("web").GetForeground(), logSourceStyle("web").GetForeground())
+}
+
+func multiLogsModel() Model {
+	m := InitialModel()
+	m.containers = []controller.Container{
+		makeContainer("a", "web", "nginx", "running", ""),
+		makeContainer("b", "db", "postgres", "running", ""),
+		makeContainer("c", "cache", "redis", "running", ""),
+	}
+	m.containers[0].Labels["tier"] = "front"
+	m.containers[2].Labels["tier"] = "back"
+	m.recomputeRows()
+	return m
+}
+
+func TestContainersView_markAndOpenMultiLogs(t *testing.T) {
+	m := multiLogsModel()
+
+	m = typeKeys(t, m, "m")
+	m.moveContainerCursor(2)
+	m = typeKeys(t, m, "m")
+	assert.Equal(t, map[string]bool{"a": true, "c": true}, m.markedContainers)
+
+	m, cmd := updateModel(t, m, tea.KeyPressMsg{Code: 'L', Text: "L"})
+	t.Cleanup(m.stopLogStream)
+
+	require.NotNil(t, cmd)
+	assert.Equal(t, LogsView, m.currentView)
+	assert.Equal(t, "2 marked", m.logTitle())
+	assert.Equal(t, []string{"web", "cache"}, m.logSourceNames())
+	assert.NotNil(t, m.logCh)
+}
+
+func TestContainersView_multiLogsByLabelSelector(t *testing.T) {
+	m := multiLogsModel()
+
+	m, _ = updateModel(t, m, tea.KeyPressMsg{Code: 'L', Text: "L"})
+	require.NotNil(t, m.form, "no marks prompts for a selector")
+	m.form.Fields[0].Input.SetValue("tier")
+
+	m, _ = updateModel(t, m, tea.KeyPressMsg{Code: tea.KeyEnter})
+	t.Cleanup(m.stopLogStream)
+
+	assert.Equal(t, LogsView, m.currentView)
+	assert.Equal(t, []string{"web", "cache"}, m.logSourceNames())
+}
+
+func TestLogsView_muteHidesSources(t *testing.T) {
+	m := logsViewModel()
+	m.logViewPort.SetHeight(10)
+	m.currentLogSources = []controller.Container{{ID: "a", Names: "web"}, {ID: "b", Names: "db"}}
+	m.logLines = toLogLines(
+		controller.LogEntry{ContainerName: "web", Line: "GET /"},
+		controller.LogEntry{ContainerName: "db", Line: "checkpoint"},
+	)
+	m.refreshLogView()
+
+	m = typeKeys(t, m, "m2")
+	m, _ = updateModel(t, m, tea.KeyPressMsg{Code: tea.KeyEnter})
+
+	assert.Equal(t, map[string]bool{"db": true}, m.logMuted)
+	assert.Equal(t, "[web] GET /\n", ansi.Strip(m.logViewPort.GetContent()))
+
+	m = typeKeys(t, m, "m")
+	assert.Equal(t, "db", m.logInput.Value(), "prompt lists muted sources")
+	m.logInput.SetValue("")
+	m, _ = updateModel(t, m, tea.KeyPressMsg{Code: tea.KeyEnter})
+	assert.Empty(t, m.logMuted)
+	assert.Contains(t, ansi.Strip(m.logViewPort.GetContent()), "[db] checkpoint")
+}
@@ -0,0 +1,99 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/rluders/berth/internal/controller"
+)
+
+// openContainerLogs switches to the logs view and streams one container.
+func (m Model) openContainerLogs(id string) (Model, tea.Cmd) {
+	m.stopLogStream()
+	m.resetLogView()
+	m.currentLogContainerID = id
+	m.currentLogGroupName = ""
+	m.currentLogSources = nil
+	m.pushView(LogsView)
+	m.logReady = true
+	return m.restartLogStream()
+}
+
+// openGroupLogs switches to the logs view and streams every container of a
+// compose group.
+func (m Model) openGroupLogs(groupID string) (Model, tea.Cmd) {
+	m.stopLogStream()
+	m.resetLogView()
+	m.currentLogGroupName = groupID
+	m.currentLogContainerID = ""
+	m.currentLogSources = nil
+	m.pushView(LogsView)
+	m.logReady = true
+	return m.restartLogStream()
+}
+
+// restartLogStream reads the logs in view again with the current log options.
+// Search and filter are kept; the lines read so far are discarded.
+func (m Model) restartLogStream() (Model, tea.Cmd) {
+	m.stopLogStream()
+	m.logLines.Reset()
+	m.logMatchIdx = -1
+	m.logFollowing = true
+	m.refreshLogView()
+
+	var (
+		ch      chan controller.LogEntry
+		cancel  func()
+		waitCmd tea.Cmd
+	)
+	if m.currentLogSources != nil || m.currentLogGroupName != "" {
+		_, containers := m.logSource()
+		ch, cancel, waitCmd = startGroupLogStreamCmd(containers, m.logOptions)
+	} else {
+		ch, cancel, waitCmd = startLogStreamCmd(m.currentLogContainerID, m.logOptions)
+	}
+	m.logCh = ch
+	m.logCancel = cancel
+	return m, waitCmd
+}
+
+// toggleLogTimestamps switches engine timestamps on or off and reloads.
+func (m Model) toggleLogTimestamps() (Model, tea.Cmd) {
+	m.logOptions.Timestamps = !m.logOptions.Timestamps
+	return m.restartLogStream()
+}
+
+// newLogRangeForm prompts for the tail size, time window and timestamps of
+// the logs view.
+func newLogRangeForm(opts controller.LogOptions) *Form {
+	timestamps := "n"
+	if opts.Timestamps {
+		timestamps = "y"
+	}
+	return NewForm(
+		"Log range",
+		func(m Model, values []string) (Model, tea.Cmd) {
+			next := controller.LogOptions{
+				Tail:       strings.TrimSpace(values[0]),
+				Since:      strings.TrimSpace(values[1]),
+				Until:      strings.TrimSpace(values[2]),
+				Timestamps: strings.EqualFold(values[3], "y") || strings.EqualFold(values[3], "yes"),
+			}
+			if next.Tail == "" {
+				next.Tail = "all"
+			}
+			if err := next.Validate(); err != nil {
+				m.statusMessage = "Log range unchanged: " + err.Error()
+				return m, nil
+			}
+			m.logOptions = next
+			m.statusMessage = fmt.Sprintf("Reloading logs (%s)", next.Summary())
+			return m.restartLogStream()
+		},
+		NewFormField("Tail (lines or all)", opts.Tail, controller.DefaultLogTail),
+		NewFormField("Since", opts.Since, "15m, 2h or 2024-05-01T10:00:00Z"),
+		NewFormField("Until", opts.Until, "empty to keep following"),
+		NewFormField("Timestamps (y/n)", timestamps, "n"),
+	)
+}
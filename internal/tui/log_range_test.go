@@ -0,0 +1,88 @@
+package tui
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/rluders/berth/internal/controller"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func logsViewModel() Model {
+	m := InitialModel()
+	m.pushView(LogsView)
+	m.logReady = true
+	m.currentLogContainerID = "abc123"
+	return m
+}
+
+func TestLogRangeForm_appliesOptionsAndReloads(t *testing.T) {
+	m := logsViewModel()
+	m.logLines = stdoutLines("old line")
+	m.logSearch = "error"
+
+	m, _ = updateModel(t, m, tea.KeyPressMsg{Code: 'o', Text: "o"})
+	require.NotNil(t, m.form)
+	m.form.Fields[0].Input.SetValue("all")
+	m.form.Fields[1].Input.SetValue("30m")
+	m.form.Fields[2].Input.SetValue("10m")
+	m.form.Fields[3].Input.SetValue("y")
+
+	result, cmd := updateModel(t, m, tea.KeyPressMsg{Code: tea.KeyEnter})
+	t.Cleanup(result.stopLogStream)
+
+	assert.NotNil(t, cmd)
+	assert.Nil(t, result.form)
+	assert.Equal(t, controller.LogOptions{Tail: "all", Since: "30m", Until: "10m", Timestamps: true}, result.logOptions)
+	assert.Zero(t, result.logLines.Len())
+	assert.Equal(t, "error", result.logSearch, "search survives a reload")
+	assert.NotNil(t, result.logCh)
+}
+
+func TestLogRangeForm_rejectsInvalidRange(t *testing.T) {
+	m := logsViewModel()
+	m.form = newLogRangeForm(m.logOptions)
+	m.form.Fields[1].Input.SetValue("last tuesday")
+
+	result, cmd := updateModel(t, m, tea.KeyPressMsg{Code: tea.KeyEnter})
+
+	assert.Nil(t, cmd)
+	assert.Equal(t, controller.DefaultLogOptions(), result.logOptions)
+	assert.Contains(t, result.statusMessage, "invalid since")
+}
+
+func TestHandleLogsKey_toggleTimestampsRestartsStream(t *testing.T) {
+	m := logsViewModel()
+	oldCh := make(chan controller.LogEntry)
+	m.logCh = oldCh
+
+	result, cmd := updateModel(t, m, tea.KeyPressMsg{Code: 't', Text: "t"})
+	t.Cleanup(result.stopLogStream)
+
+	assert.NotNil(t, cmd)
+	assert.True(t, result.logOptions.Timestamps)
+	assert.NotEqual(t, oldCh, result.logCh)
+}
+
+func TestHandleLogChunkMsg_dropsLinesFromReloadedStream(t *testing.T) {
+	m := logsViewModel()
+	m.logCh = make(chan controller.LogEntry)
+	stale := make(chan controller.LogEntry)
+
+	result, cmd := updateModel(t, m, logChunkMsg{ch: stale, entries: []controller.LogEntry{{Line: "stale"}}})
+	assert.Zero(t, result.logLines.Len())
+	assert.Nil(t, cmd)
+
+	result, _ = updateModel(t, result, logStreamDoneMsg{ch: stale})
+	assert.NotNil(t, result.logCh, "closing an old stream keeps the current one")
+}
+
+func TestResetLogView_restoresDefaultOptions(t *testing.T) {
+	m := logsViewModel()
+	m.logOptions = controller.LogOptions{Tail: "all", Since: "1h"}
+
+	m.resetLogView()
+
+	assert.Equal(t, controller.DefaultLogOptions(), m.logOptions)
+}
@@ -0,0 +1,392 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"github.com/rluders/berth/internal/controller"
+)
+
+// logInputMode selects what the logs view prompt is editing.
+type logInputMode int
+
+const (
+	logInputNone logInputMode = iota
+	logInputSearch
+	logInputFilter
+	logInputField
+	logInputColumns
+	logInputMute
+)
+
+// logStreamFilter selects which output streams the logs view shows.
+type logStreamFilter int
+
+const (
+	logStreamsBoth logStreamFilter = iota
+	logStreamsStdout
+	logStreamsStderr
+)
+
+// shows reports whether lines from stream are visible. Lines of TTY
+// containers count as stdout.
+func (f logStreamFilter) shows(stream string) bool {
+	switch f {
+	case logStreamsStdout:
+		return stream != controller.StreamStderr
+	case logStreamsStderr:
+		return stream == controller.StreamStderr
+	default:
+		return true
+	}
+}
+
+// next cycles both → stdout → stderr → both.
+func (f logStreamFilter) next() logStreamFilter {
+	return (f + 1) % 3
+}
+
+func (f logStreamFilter) String() string {
+	switch f {
+	case logStreamsStdout:
+		return "stdout only"
+	case logStreamsStderr:
+		return "stderr only"
+	default:
+		return "stdout+stderr"
+	}
+}
+
+// logEntryPrefix returns the container name prefix of group log lines.
+func logEntryPrefix(e controller.LogEntry) string {
+	if e.ContainerName == "" {
+		return ""
+	}
+	return "[" + e.ContainerName + "] "
+}
+
+// logLineText returns the line as shown, searched and filtered.
+func logLineText(l logLine, columns []string, widths []int) string {
+	if l.json != nil {
+		return logEntryPrefix(l.LogEntry) + l.json.text(columns, widths)
+	}
+	return logEntryPrefix(l.LogEntry) + l.Line
+}
+
+// logFilter keeps only the log lines matching (or, inverted, not matching) a
+// regular expression.
+type logFilter struct {
+	text   string // as typed, including the leading "!" of an inverted filter
+	re     *regexp.Regexp
+	invert bool
+}
+
+// parseLogFilter compiles a filter expression. A leading "!" inverts it and
+// an empty expression means no filter.
+func parseLogFilter(text string) (*logFilter, error) {
+	expr, invert := strings.CutPrefix(text, "!")
+	if expr == "" {
+		return nil, nil
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter: %w", err)
+	}
+	return &logFilter{text: text, re: re, invert: invert}, nil
+}
+
+// keep reports whether line passes the filter. A nil filter keeps everything.
+func (f *logFilter) keep(line string) bool {
+	if f == nil {
+		return true
+	}
+	return f.re.MatchString(line) != f.invert
+}
+
+// searchPattern returns a case-insensitive literal matcher for query, or nil
+// when query is empty.
+func searchPattern(query string) *regexp.Regexp {
+	if query == "" {
+		return nil
+	}
+	return regexp.MustCompile("(?i)" + regexp.QuoteMeta(query))
+}
+
+// logRenderOptions collects the logs view's filters and display settings.
+func (m Model) logRenderOptions() logRenderOptions {
+	return logRenderOptions{
+		showLineNumbers: m.showLineNumbers,
+		streams:         m.logStreams,
+		minLevel:        m.logMinLevel,
+		field:           m.logFieldFilter,
+		columns:         m.logColumns,
+		muted:           m.logMuted,
+		filter:          m.logFilter,
+		search:          searchPattern(m.logSearch),
+		currentMatch:    m.currentLogMatchLine(),
+	}
+}
+
+// refreshLogView applies the active filters and search to the buffer and
+// re-renders the visible window.
+func (m *Model) refreshLogView() {
+	m.logRows, m.logMatches, m.logWidths = filterLogRows(m.logLines, m.logRenderOptions())
+	m.logFiltered = m.logLines.Total()
+	m.renderLogWindow()
+}
+
+// updateLogView brings the rows up to date with the lines received since the
+// last refresh, filtering only those, and re-renders the visible window.
+func (m *Model) updateLogView() {
+	widths := slices.Clone(m.logWidths)
+	for n := max(m.logFiltered, m.logLines.Dropped()); n < m.logLines.Total(); n++ {
+		widenLogColumns(widths, m.logLines.At(n-m.logLines.Dropped()), m.logColumns)
+	}
+	if !slices.Equal(widths, m.logWidths) {
+		// Wider columns change the text the filter and search see.
+		m.refreshLogView()
+		return
+	}
+	m.dropLogRows()
+	m.logRows, m.logMatches = appendLogRows(m.logLines, m.logRenderOptions(), m.logWidths, m.logFiltered, m.logRows, m.logMatches)
+	m.logFiltered = m.logLines.Total()
+	m.renderLogWindow()
+}
+
+// dropLogRows forgets the rows of lines dropped from the buffer, and the
+// matches among them.
+func (m *Model) dropLogRows() {
+	n, _ := slices.BinarySearch(m.logRows, m.logLines.Dropped())
+	if n == 0 {
+		return
+	}
+	m.logRows = m.logRows[n:]
+	k, _ := slices.BinarySearch(m.logMatches, n)
+	m.logMatches = m.logMatches[k:]
+	for i := range m.logMatches {
+		m.logMatches[i] -= n
+	}
+	if m.logMatchIdx >= 0 {
+		m.logMatchIdx = max(m.logMatchIdx-k, -1)
+	}
+}
+
+// logTopRow returns the row of the first visible line.
+func (m Model) logTopRow() int {
+	row, _ := slices.BinarySearch(m.logRows, m.logTop)
+	return row
+}
+
+// setLogTopRow makes row the first visible one.
+func (m *Model) setLogTopRow(row int) {
+	if len(m.logRows) > 0 {
+		m.logTop = m.logRows[min(max(row, 0), len(m.logRows)-1)]
+	}
+}
+
+// renderLogWindow renders the rows that fit in the viewport, starting at
+// logTop, or the tail when following.
+func (m *Model) renderLogWindow() {
+	height := max(m.logViewPort.Height(), 1)
+	bottom := max(len(m.logRows)-height, 0)
+	top := min(m.logTopRow(), bottom)
+	if m.logFollowing {
+		top = bottom
+	}
+	m.setLogTopRow(top)
+	m.logViewPort.SetContent(renderLogRows(m.logLines, m.logRows, m.logWidths, top, top+height, m.logRenderOptions()))
+}
+
+// scrollLogs moves the window by delta rows. Scrolling up stops following.
+func (m *Model) scrollLogs(delta int) {
+	if delta < 0 {
+		m.logFollowing = false
+	}
+	m.setLogTopRow(m.logTopRow() + delta)
+	m.renderLogWindow()
+}
+
+// handleLogScrollKey scrolls the logs window with the viewport's vertical
+// keys; it reports false for other keys.
+func (m *Model) handleLogScrollKey(msg tea.KeyPressMsg) bool {
+	km := m.logViewPort.KeyMap
+	page := max(m.logViewPort.Height(), 1)
+	switch {
+	case key.Matches(msg, km.Up):
+		m.scrollLogs(-1)
+	case key.Matches(msg, km.Down):
+		m.scrollLogs(1)
+	case key.Matches(msg, km.PageUp):
+		m.scrollLogs(-page)
+	case key.Matches(msg, km.PageDown):
+		m.scrollLogs(page)
+	case key.Matches(msg, km.HalfPageUp):
+		m.scrollLogs(-page / 2)
+	case key.Matches(msg, km.HalfPageDown):
+		m.scrollLogs(page / 2)
+	default:
+		return false
+	}
+	return true
+}
+
+// currentLogMatchLine returns the rendered line of the current match, or -1.
+func (m Model) currentLogMatchLine() int {
+	if m.logMatchIdx < 0 || m.logMatchIdx >= len(m.logMatches) {
+		return -1
+	}
+	return m.logMatches[m.logMatchIdx]
+}
+
+// jumpToLogMatch moves to the next (delta 1) or previous (delta -1) match,
+// wrapping around, and scrolls it into the middle of the viewport. With delta
+// 0 it selects the first match at or below the top of the viewport.
+func (m *Model) jumpToLogMatch(delta int) {
+	if len(m.logMatches) == 0 {
+		if m.logSearch != "" {
+			m.statusMessage = fmt.Sprintf("No matches for %q", m.logSearch)
+		}
+		return
+	}
+	n := len(m.logMatches)
+	switch {
+	case delta == 0 || m.logMatchIdx < 0:
+		m.logMatchIdx = 0
+		top := m.logTopRow()
+		for i, row := range m.logMatches {
+			if row >= top {
+				m.logMatchIdx = i
+				break
+			}
+		}
+	default:
+		m.logMatchIdx = ((m.logMatchIdx+delta)%n + n) % n
+	}
+	m.logFollowing = false
+	m.setLogTopRow(m.logMatches[m.logMatchIdx] - m.logViewPort.Height()/2)
+	m.renderLogWindow()
+	m.statusMessage = fmt.Sprintf("Match %d/%d for %q", m.logMatchIdx+1, n, m.logSearch)
+}
+
+// openLogInput shows the search or filter prompt, pre-filled with the
+// current value.
+func (m *Model) openLogInput(mode logInputMode) {
+	m.logInputMode = mode
+	m.logInput.Reset()
+	switch mode {
+	case logInputSearch:
+		m.logInput.Prompt = "/ "
+		m.logInput.Placeholder = "search..."
+		m.logInput.SetValue(m.logSearch)
+	case logInputFilter:
+		m.logInput.Prompt = "& "
+		m.logInput.Placeholder = "regex (prefix ! to invert)..."
+		if m.logFilter != nil {
+			m.logInput.SetValue(m.logFilter.text)
+		}
+	case logInputField:
+		m.logInput.Prompt = "= "
+		m.logInput.Placeholder = "field=value or field!=value..."
+		if m.logFieldFilter != nil {
+			m.logInput.SetValue(m.logFieldFilter.text)
+		}
+	case logInputColumns:
+		m.logInput.Prompt = "columns "
+		m.logInput.Placeholder = "comma-separated JSON fields..."
+		m.logInput.SetValue(strings.Join(m.logColumns, ","))
+	case logInputMute:
+		m.logInput.Prompt = "mute "
+		m.logInput.Placeholder = "sources by number or name, comma-separated (empty unmutes all)..."
+		m.logInput.SetValue(strings.Join(m.mutedSourceList(), ","))
+	}
+	m.logInput.CursorEnd()
+	m.logInput.Focus()
+}
+
+// closeLogInput hides the prompt.
+func (m *Model) closeLogInput() {
+	m.logInputMode = logInputNone
+	m.logInput.Blur()
+}
+
+// handleLogInputKey edits the logs view prompt. Search is applied as you
+// type; filters and columns are applied on enter.
+func (m Model) handleLogInputKey(msg tea.KeyPressMsg) (Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, Keys.Filter.Cancel):
+		if m.logInputMode == logInputSearch {
+			m.logSearch = ""
+			m.logMatchIdx = -1
+			m.refreshLogView()
+		}
+		m.closeLogInput()
+		return m, nil
+	case key.Matches(msg, Keys.Filter.Submit):
+		mode := m.logInputMode
+		m.closeLogInput()
+		switch mode {
+		case logInputFilter:
+			f, err := parseLogFilter(m.logInput.Value())
+			if err != nil {
+				m.statusMessage = err.Error()
+				return m, nil
+			}
+			m.logFilter = f
+		case logInputField:
+			f, err := parseLogFieldFilter(m.logInput.Value())
+			if err != nil {
+				m.statusMessage = err.Error()
+				return m, nil
+			}
+			m.logFieldFilter = f
+		case logInputColumns:
+			m.logColumns = parseLogColumns(m.logInput.Value())
+		case logInputMute:
+			muted, err := parseMutedSources(m.logInput.Value(), m.logSourceNames())
+			if err != nil {
+				m.statusMessage = err.Error()
+				return m, nil
+			}
+			m.logMuted = muted
+		default:
+			return m, nil
+		}
+		m.logMatchIdx = -1
+		m.refreshLogView()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.logInput, cmd = m.logInput.Update(msg)
+	if m.logInputMode == logInputSearch && m.logInput.Value() != m.logSearch {
+		m.logSearch = m.logInput.Value()
+		m.logMatchIdx = -1
+		m.refreshLogView()
+		m.jumpToLogMatch(0)
+	}
+	return m, cmd
+}
+
+// resetLogView clears the buffer, search and filter before a new log stream.
+func (m *Model) resetLogView() {
+	m.logLines.Reset()
+	m.logRows = nil
+	m.logFiltered = 0
+	m.logTop = 0
+	m.logFollowing = true
+	m.logSearch = ""
+	m.logFilter = nil
+	m.logMatches = nil
+	m.logMatchIdx = -1
+	m.logOptions = controller.DefaultLogOptions()
+	m.logStreams = logStreamsBoth
+	m.logMinLevel = logLevelUnknown
+	m.logFieldFilter = nil
+	m.logColumns = nil
+	m.logMuted = nil
+	m.closeLogInput()
+}
@@ -0,0 +1,201 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/rluders/berth/internal/controller"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// toLogLines buffers entries as the logs view does.
+func toLogLines(entries ...controller.LogEntry) *logBuffer {
+	buf := newLogBuffer(0)
+	for _, e := range entries {
+		buf.Push(newLogLine(e))
+	}
+	return buf
+}
+
+// stdoutLines buffers lines as stdout output of a single container.
+func stdoutLines(lines ...string) *logBuffer {
+	entries := make([]controller.LogEntry, len(lines))
+	for i, l := range lines {
+		entries[i] = controller.LogEntry{Stream: controller.StreamStdout, Line: l}
+	}
+	return toLogLines(entries...)
+}
+
+// renderAllLogs filters buf and renders every remaining row.
+func renderAllLogs(buf *logBuffer, opts logRenderOptions) (string, []int) {
+	rows, matches, widths := filterLogRows(buf, opts)
+	return renderLogRows(buf, rows, widths, 0, len(rows), opts), matches
+}
+
+func TestParseLogFilter(t *testing.T) {
+	f, err := parseLogFilter("")
+	require.NoError(t, err)
+	assert.Nil(t, f)
+	assert.True(t, f.keep("anything"), "nil filter keeps every line")
+
+	f, err = parseLogFilter("GET /api")
+	require.NoError(t, err)
+	assert.True(t, f.keep("GET /api/users 200"))
+	assert.False(t, f.keep("POST /login 302"))
+
+	f, err = parseLogFilter("!health(check)?")
+	require.NoError(t, err)
+	assert.False(t, f.keep("GET /healthcheck 200"))
+	assert.True(t, f.keep("GET /api 200"))
+
+	_, err = parseLogFilter("(unclosed")
+	assert.ErrorContains(t, err, "invalid filter")
+}
+
+func TestBuildColorizedLogContent_filterSearchAndLineNumbers(t *testing.T) {
+	lines := stdoutLines("alpha one", "beta two", "alpha three", "gamma")
+	f, err := parseLogFilter("!beta")
+	require.NoError(t, err)
+
+	content, matches := renderAllLogs(lines, logRenderOptions{
+		showLineNumbers: true,
+		filter:          f,
+		search:          searchPattern("ALPHA"),
+		currentMatch:    -1,
+	})
+
+	plain := ansi.Strip(content)
+	assert.NotContains(t, plain, "beta")
+	assert.Contains(t, plain, "3 │ alpha three", "line numbers refer to the unfiltered buffer")
+	assert.Equal(t, []int{0, 1}, matches, "matches are rendered line indices")
+}
+
+func TestBuildColorizedLogContent_filterHidesEverything(t *testing.T) {
+	f, err := parseLogFilter("nope")
+	require.NoError(t, err)
+
+	content, matches := renderAllLogs(stdoutLines("a", "b"), logRenderOptions{filter: f, currentMatch: -1})
+
+	assert.Contains(t, ansi.Strip(content), "No lines match the filter.")
+	assert.Empty(t, matches)
+}
+
+func TestHighlightLogLine_keepsText(t *testing.T) {
+	out := highlightLogLine("error: disk error", searchPattern("error"), currentTheme.LogMatchStyle)
+	assert.Equal(t, "error: disk error", ansi.Strip(out))
+}
+
+func typeKeys(t *testing.T, m Model, s string) Model {
+	t.Helper()
+	for _, r := range s {
+		m, _ = updateModel(t, m, tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+	return m
+}
+
+func TestLogsView_incrementalSearchAndNavigation(t *testing.T) {
+	m := InitialModel()
+	m.currentView = LogsView
+	m.logViewPort.SetHeight(10)
+	m.logLines = stdoutLines("start", "request failed", "ok", "request failed again")
+	m.refreshLogView()
+
+	m = typeKeys(t, m, "/fail")
+	assert.Equal(t, logInputSearch, m.logInputMode)
+	assert.Equal(t, "fail", m.logSearch)
+	assert.Equal(t, []int{1, 3}, m.logMatches)
+	assert.False(t, m.logFollowing, "searching stops following")
+
+	m, _ = updateModel(t, m, tea.KeyPressMsg{Code: tea.KeyEnter})
+	assert.Equal(t, logInputNone, m.logInputMode)
+	assert.Equal(t, 0, m.logMatchIdx)
+
+	m = typeKeys(t, m, "n")
+	assert.Equal(t, 1, m.logMatchIdx)
+	m = typeKeys(t, m, "n")
+	assert.Equal(t, 0, m.logMatchIdx, "next wraps around")
+	m = typeKeys(t, m, "N")
+	assert.Equal(t, 1, m.logMatchIdx)
+	assert.Equal(t, `Match 2/2 for "fail"`, m.statusMessage)
+}
+
+func TestLogsView_filterPromptAppliesOnEnter(t *testing.T) {
+	m := InitialModel()
+	m.currentView = LogsView
+	m.logLines = stdoutLines("GET /health 200", "GET /api 500")
+	m.refreshLogView()
+
+	m = typeKeys(t, m, "&!health")
+	assert.Nil(t, m.logFilter, "filter applies on enter only")
+	m, _ = updateModel(t, m, tea.KeyPressMsg{Code: tea.KeyEnter})
+
+	require.NotNil(t, m.logFilter)
+	content := ansi.Strip(m.logViewPort.GetContent())
+	assert.NotContains(t, content, "/health")
+	assert.True(t, strings.Contains(content, "/api 500"))
+
+	m = typeKeys(t, m, "&")
+	assert.Equal(t, "!health", m.logInput.Value(), "prompt is pre-filled with the current filter")
+	m, _ = updateModel(t, m, tea.KeyPressMsg{Code: tea.KeyEscape})
+	assert.NotNil(t, m.logFilter, "esc keeps the applied filter")
+}
+
+func TestBuildColorizedLogContent_streams(t *testing.T) {
+	entries := toLogLines(
+		controller.LogEntry{ContainerName: "web", Stream: controller.StreamStdout, Line: "listening"},
+		controller.LogEntry{ContainerName: "web", Stream: controller.StreamStderr, Line: "deprecated flag"},
+		controller.LogEntry{ContainerName: "tty", Line: "$ ls"},
+	)
+
+	content, _ := renderAllLogs(entries, logRenderOptions{currentMatch: -1})
+	assert.Equal(t, "[web] listening\n! [web] deprecated flag\n[tty] $ ls\n", ansi.Strip(content))
+
+	content, _ = renderAllLogs(entries, logRenderOptions{streams: logStreamsStdout, currentMatch: -1})
+	assert.Equal(t, "[web] listening\n[tty] $ ls\n", ansi.Strip(content), "TTY output counts as stdout")
+
+	content, _ = renderAllLogs(entries, logRenderOptions{streams: logStreamsStderr, currentMatch: -1})
+	assert.Equal(t, "! [web] deprecated flag\n", ansi.Strip(content))
+}
+
+func TestLogsView_streamToggleCycles(t *testing.T) {
+	m := InitialModel()
+	m.currentView = LogsView
+	m.logLines = toLogLines(
+		controller.LogEntry{Stream: controller.StreamStdout, Line: "out"},
+		controller.LogEntry{Stream: controller.StreamStderr, Line: "err"},
+	)
+	m.refreshLogView()
+
+	m = typeKeys(t, m, "s")
+	assert.Equal(t, logStreamsStdout, m.logStreams)
+	assert.NotContains(t, ansi.Strip(m.logViewPort.GetContent()), "err")
+
+	m = typeKeys(t, m, "s")
+	assert.Equal(t, logStreamsStderr, m.logStreams)
+	assert.NotContains(t, ansi.Strip(m.logViewPort.GetContent()), "out")
+
+	m = typeKeys(t, m, "s")
+	assert.Equal(t, logStreamsBoth, m.logStreams)
+}
+
+func TestFilterLogRows_keepsRestartMarkers(t *testing.T) {
+	buf := toLogLines(
+		controller.LogEntry{ContainerName: "web", Stream: controller.StreamStdout, Line: "GET /"},
+		controller.LogEntry{ContainerName: "web", Stream: controller.StreamStdout, Line: "--- container restarted at 10:05:00 ---", Marker: true},
+		controller.LogEntry{ContainerName: "db", Stream: controller.StreamStdout, Line: "--- container restarted at 10:06:00 ---", Marker: true},
+	)
+	f, err := parseLogFilter("nothing matches")
+	require.NoError(t, err)
+
+	content, _ := renderAllLogs(buf, logRenderOptions{
+		filter:       f,
+		streams:      logStreamsStderr,
+		muted:        map[string]bool{"db": true},
+		currentMatch: -1,
+	})
+
+	assert.Equal(t, "[web] --- container restarted at 10:05:00 ---\n", ansi.Strip(content))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
 
 	"charm.land/bubbles/v2/help"
@@ -14,6 +15,7 @@ import (
 	"charm.land/bubbles/v2/viewport"
 	tea "charm.land/bubbletea/v2"
 	"github.com/charmbracelet/x/ansi"
+	"github.com/rluders/berth/internal/config"
 	"github.com/rluders/berth/internal/controller"
 	"github.com/rluders/berth/internal/engine"
 	"github.com/rluders/berth/internal/utils"
@@ -40,11 +42,29 @@ type Model struct {
 	containers []controller.Container
 	images     []controller.Image
 	volumes    []controller.Volume
+	networks   []controller.Network
 
-	// Container stats
-	containerStats map[string]controller.ContainerStat
+	// Sort of each sortable table, by sortableView name; tables without
+	// one keep the order the engine returned.
+	tableSorts map[string]tableSort
 
-	// Accordion state
+	// Container stats (latest sample per container; history lives in the monitor)
+	containerStats map[string]controller.ContainerStat
+	statsMonitor   *controller.StatsMonitor
+	statsViewPort  viewport.Model
+
+	// Resource alerts (reasons per alerting container), the last sampled
+	// restart count of each container and the restarts seen in a row since
+	// a sample last found none
+	config         config.Config
+	alerts         map[string][]string
+	restartCounts  map[string]int
+	restartStreaks map[string]int
+
+	// Accordion state: the grouping ("g"), the collapsed groups of every
+	// grouping used, and those of the current one
+	grouping        Grouping
+	groupCollapsed  map[string]map[string]bool
 	collapsedGroups map[string]bool
 	rows            []Row
 
@@ -60,23 +80,69 @@ type Model struct {
 	// Logs view
 	logViewPort           viewport.Model
 	logReady              bool
-	logLines              []string
+	logLines              *logBuffer
 	logFollowing          bool
-	logCh                 chan string
+	logCh                 chan controller.LogEntry
 	logCancel             context.CancelFunc
 	currentLogContainerID string
 	currentLogGroupName   string
 	showLineNumbers       bool
+	logOptions            controller.LogOptions // tail, time window and timestamps of the stream
+	logExportDir          string                // directory logs were last saved to, persisted
+	logStreams            logStreamFilter       // which of stdout/stderr are shown
+
+	// Multiplexed logs of hand-picked containers ("m" marks, "L" opens)
+	currentLogSources      []controller.Container
+	currentLogSourcesTitle string
+	markedContainers       map[string]bool // IDs marked for multiplexed logs
+	logMuted               map[string]bool // container names hidden from the logs view
+
+	// Logs search ("/", n/N) and regex line filter ("&")
+	logInput     textinput.Model
+	logInputMode logInputMode
+	logSearch    string
+	logMatches   []int // rendered lines containing a search match
+	logMatchIdx  int   // index into logMatches, -1 when none is selected
+	logFilter    *logFilter
+
+	// Visible window of the logs buffer; only rows on screen are rendered
+	logRows          []int // lines kept by the filters, numbered over everything received
+	logFiltered      int   // number of the first line not yet filtered into logRows
+	logWidths        []int // widths of the JSON field columns
+	logTop           int   // number of the first visible line
+	logRenderPending bool  // a coalesced render is scheduled
+
+	// Structured (JSON) logs: level and field filters, field columns
+	logMinLevel    logLevel
+	logFieldFilter *logFieldFilter
+	logColumns     []string
 
 	// Details view
 	detailsViewPort  viewport.Model
 	detailsReady     bool
 	currentDetailsID string
 	currentDetails   controller.ContainerDetails
+	detailsSection   int             // selected section
+	detailsOffsets   []int           // first line of each section
+	detailsCollapsed map[string]bool // collapsed sections by title, persisted
+	detailsReveal    bool            // show secret-looking env values
+
+	// Changes view (container filesystem diff)
+	changesTable     table.Model
+	changes          []controller.FileChange
+	currentChangesID string
+
+	// Top view (container process list)
+	topTable       table.Model
+	processes      []controller.Process
+	currentTopID   string
+	currentTopName string
 
 	// Search / filter
-	filterInput  textinput.Model
-	filterActive bool
+	filterInput   textinput.Model
+	topFilter     textinput.Model // the process list's own filter
+	filterActive  bool
+	unhealthyOnly bool
 
 	// Modal dialog (replaces old confirmAction)
 	modal *Modal
@@ -84,6 +150,24 @@ type Model struct {
 	// Quick actions overlay (space key)
 	quickMenu *QuickMenu
 
+	// Text input form overlay (path prompts, etc.)
+	form *Form
+
+	// Exec dialog choices remembered per image
+	execPrefs map[string]execPrefs
+
+	// Exec output view (one-off commands); history is keyed by container name
+	execOutputVP     viewport.Model
+	execOutput       []controller.ExecLine
+	execOutputCh     <-chan controller.ExecLine
+	execOutputCancel context.CancelFunc
+	execOutputID     string
+	execOutputName   string
+	execOutputCmd    string
+	execRunning      bool
+	execResult       *execDoneMsg
+	execHistory      map[string][]string
+
 	// Help overlay
 	showHelp  bool
 	helpModel help.Model
@@ -96,6 +180,7 @@ type Model struct {
 
 	// Status
 	err           error
+	configErr     string // shown until the configuration is fixed
 	statusMessage string
 	showSpinner   bool
 	spinner       spinner.Model
@@ -111,8 +196,28 @@ type Model struct {
 	lastActionKey string
 
 	// Compose streaming state
-	composeOutput []string           // rolling 200-line buffer of streamed compose output
-	composeCancel context.CancelFunc // cancels the running compose operation, nil when idle
+	composeCancel    context.CancelFunc // cancels the running compose operation, nil when idle
+	composeRunningOp int                // history id of the running operation
+
+	// Compose operations history ("h") and the output of one operation
+	composeOps        []composeOp
+	composeOpSeq      int
+	composeOpsTable   table.Model
+	composeOpsProject string // project the history is limited to; empty for all
+	composeOutputVP   viewport.Model
+	composeOutputOp   int
+
+	// Drift of a compose project from its files ("D")
+	driftTable   table.Model
+	driftProject string
+	drift        []controller.ServiceDrift
+
+	// Compose projects: targets seen in container labels (persisted, so a
+	// project outlives its containers) and the projects read from disk
+	composeTargets  map[string]controller.ComposeTarget
+	composeProjects map[string]controller.ComposeProject
+	composeOptions  map[string]composeOptions // chosen profiles and env files, by project
+	composeErr      string                    // last discovery error, shown once
 }
 
 // InitialModel returns an initialized Model with default values.
@@ -139,33 +244,113 @@ func InitialModel() Model {
 		table.WithHeight(0),
 	)
 
+	changesTable := table.New(
+		table.WithColumns(tableColumns(120, changeCols)),
+		table.WithFocused(true),
+		table.WithHeight(0),
+	)
+
+	topTable := table.New(
+		table.WithColumns(tableColumns(120, topCols)),
+		table.WithFocused(true),
+		table.WithHeight(0),
+	)
+
+	composeOpsTable := table.New(
+		table.WithColumns(tableColumns(120, composeOpCols)),
+		table.WithFocused(true),
+		table.WithHeight(0),
+	)
+
+	driftTable := table.New(
+		table.WithColumns(tableColumns(120, driftCols)),
+		table.WithFocused(true),
+		table.WithHeight(0),
+	)
+
 	s := tableStyles()
 	imageTable.SetStyles(s)
 	volumeTable.SetStyles(s)
 	networkTable.SetStyles(s)
+	changesTable.SetStyles(s)
+	topTable.SetStyles(s)
+	composeOpsTable.SetStyles(s)
+	driftTable.SetStyles(s)
 
 	fi := textinput.New()
 	fi.Placeholder = "filter..."
 	fi.CharLimit = 60
+	tf := textinput.New()
+	tf.Placeholder = "filter processes..."
+	tf.CharLimit = 60
+
+	li := textinput.New()
+	li.CharLimit = 200
+
+	state := loadState()
+	grouping := ParseGrouping(state.Grouping)
+	groupCollapsed := orEmpty(state.GroupCollapsed)
+	groupCollapsed[Grouping{Kind: GroupByCompose}.String()] = orEmpty(state.CollapsedGroups)
+	tableSorts := orEmpty(state.TableSorts)
+	if _, ok := tableSorts["top"]; !ok {
+		// Processes start with the busiest first, like top.
+		tableSorts["top"] = tableSort{Column: 2, Desc: true}
+	}
+
+	cfg, err := config.Load()
+	var configErr string
+	if err != nil {
+		slog.Error("InitialModel: config error", "err", err)
+		configErr = err.Error()
+	}
+	controller.SetComposeTool(cfg.Compose.Tool)
 
 	return Model{
-		engineType:      engine.DetectEngine(),
-		currentView:     ContainersView,
-		containerVP:     viewport.New(),
-		builtCols:       initCols,
-		imageTable:      imageTable,
-		volumeTable:     volumeTable,
-		networkTable:    networkTable,
-		containerStats:  make(map[string]controller.ContainerStat),
-		collapsedGroups: loadedCollapsedGroups(),
-		systemInfo:      controller.SystemInfo{},
-		inspectViewPort: viewport.New(),
-		logViewPort:     viewport.New(),
-		detailsViewPort: viewport.New(),
-		logFollowing:    true,
-		filterInput:     fi,
-		spinner:         spinner.New(),
-		helpModel:       help.New(),
+		engineType:       engine.DetectEngine(),
+		currentView:      ContainersView,
+		containerVP:      viewport.New(),
+		builtCols:        initCols,
+		imageTable:       imageTable,
+		volumeTable:      volumeTable,
+		networkTable:     networkTable,
+		changesTable:     changesTable,
+		topTable:         topTable,
+		composeOpsTable:  composeOpsTable,
+		composeOutputVP:  viewport.New(),
+		driftTable:       driftTable,
+		tableSorts:       tableSorts,
+		containerStats:   make(map[string]controller.ContainerStat),
+		statsMonitor:     controller.NewStatsMonitor(statsHistoryLen(cfg.Alerts)),
+		statsViewPort:    viewport.New(),
+		config:           cfg,
+		alerts:           make(map[string][]string),
+		restartCounts:    make(map[string]int),
+		restartStreaks:   make(map[string]int),
+		configErr:        configErr,
+		grouping:         grouping,
+		groupCollapsed:   groupCollapsed,
+		collapsedGroups:  collapsedFor(groupCollapsed, grouping),
+		markedContainers: map[string]bool{},
+		composeTargets:   orEmpty(state.ComposeProjects),
+		composeOptions:   orEmpty(state.ComposeOptions),
+		detailsCollapsed: orEmpty(state.DetailsCollapsed),
+		execPrefs:        orEmpty(state.ExecPrefs),
+		execHistory:      orEmpty(state.ExecHistory),
+		logExportDir:     state.LogExportDir,
+		execOutputVP:     viewport.New(),
+		systemInfo:       controller.SystemInfo{},
+		inspectViewPort:  viewport.New(),
+		logViewPort:      viewport.New(),
+		detailsViewPort:  viewport.New(),
+		logFollowing:     true,
+		filterInput:      fi,
+		topFilter:        tf,
+		logInput:         li,
+		logMatchIdx:      -1,
+		logOptions:       controller.DefaultLogOptions(),
+		logLines:         newLogBuffer(cfg.Logs.MaxLines),
+		spinner:          spinner.New(),
+		helpModel:        help.New(),
 		progressBar: progress.New(
 			progress.WithDefaultBlend(),
 			progress.WithoutPercentage(),
@@ -201,12 +386,23 @@ func (m Model) getViewName() string {
 	case InspectView:
 		return fmt.Sprintf("Inspect %s", m.currentInspectID)
 	case LogsView:
-		if m.currentLogGroupName != "" {
-			return fmt.Sprintf("Logs  %s", m.currentLogGroupName)
-		}
-		return fmt.Sprintf("Logs  %s", m.currentLogContainerID)
+		return fmt.Sprintf("Logs  %s", m.logTitle())
 	case DetailsView:
 		return fmt.Sprintf("Details  %s", m.currentDetailsID)
+	case ChangesView:
+		return fmt.Sprintf("Changes  %s", m.currentChangesID)
+	case ExecOutputView:
+		return fmt.Sprintf("Run  %s", m.execOutputName)
+	case TopView:
+		return fmt.Sprintf("Top  %s", m.currentTopName)
+	case StatsView:
+		return "Stats"
+	case ComposeOpsView:
+		return fmt.Sprintf("Compose history  %s", m.composeOpsTitle())
+	case ComposeOutputView:
+		return "Compose output"
+	case DriftView:
+		return fmt.Sprintf("Drift  %s", m.driftProject)
 	}
 	return "Unknown"
 }
@@ -222,9 +418,29 @@ func (m Model) headerText() string {
 		}
 		extra = fmt.Sprintf(" [%s]", mode)
 	}
+	if m.currentView == ContainersView {
+		extra += m.containerIndicators()
+	}
 	return fmt.Sprintf("Berth  %s  %s Engine%s", view, eng, extra)
 }
 
+// containerIndicators returns the notes on how the containers list is
+// grouped and filtered, each in brackets after a space.
+func (m Model) containerIndicators() string {
+	var sb strings.Builder
+	switch m.grouping.Kind {
+	case GroupByCompose:
+	case GroupByNone:
+		sb.WriteString(" [ungrouped]")
+	default:
+		sb.WriteString(" [by " + m.grouping.Describe() + "]")
+	}
+	if m.unhealthyOnly {
+		sb.WriteString(" [unhealthy only]")
+	}
+	return sb.String()
+}
+
 func (m *Model) pushView(view ViewType) {
 	m.viewStack = append(m.viewStack, m.currentView)
 	m.currentView = view
@@ -257,6 +473,12 @@ func (m Model) contentHeight() int {
 	if m.progressVisible {
 		h -= 2 // label + bar
 	}
+	if m.alertSummary() != "" {
+		h -= 1
+	}
+	if m.configErr != "" {
+		h -= 1
+	}
 	if h < 0 {
 		return 0
 	}
@@ -265,9 +487,26 @@ func (m Model) contentHeight() int {
 
 // recomputeRows applies filter, rebuilds m.rows via BuildRows, and syncs the viewport.
 func (m *Model) recomputeRows() {
+	m.rows = BuildRows(m.filteredContainers(), m.grouping, m.collapsedGroups, m.filteredGhosts())
+	if s, ok := m.tableSortOf(ContainersView); ok {
+		sortContainerRows(m.rows, s, compareContainers(m.containerStats))
+	}
+	// Clamp cursor after filter may reduce row count.
+	if len(m.rows) > 0 && m.containerCursor >= len(m.rows) {
+		m.containerCursor = len(m.rows) - 1
+	}
+	m.syncContainerViewport()
+}
+
+// filteredContainers returns the containers passing the text filter and the
+// unhealthy-only toggle.
+func (m Model) filteredContainers() []controller.Container {
 	filter := strings.ToLower(m.filterInput.Value())
 	var filtered []controller.Container
 	for _, c := range m.containers {
+		if m.unhealthyOnly && c.Health != controller.HealthUnhealthy {
+			continue
+		}
 		if filter != "" {
 			haystack := strings.ToLower(c.Names + " " + c.Image + " " + c.Status + " " + c.State)
 			if !strings.Contains(haystack, filter) {
@@ -276,19 +515,35 @@ func (m *Model) recomputeRows() {
 		}
 		filtered = append(filtered, c)
 	}
-	m.rows = BuildRows(filtered, m.collapsedGroups)
-	// Clamp cursor after filter may reduce row count.
-	if len(m.rows) > 0 && m.containerCursor >= len(m.rows) {
-		m.containerCursor = len(m.rows) - 1
+	return filtered
+}
+
+// filteredGhosts returns the compose services without a container that pass
+// the text filter. They are never unhealthy, so the unhealthy-only toggle
+// hides them all.
+func (m Model) filteredGhosts() map[string][]controller.ComposeService {
+	if m.unhealthyOnly {
+		return nil
 	}
-	m.syncContainerViewport()
+	filter := strings.ToLower(m.filterInput.Value())
+	ghosts := map[string][]controller.ComposeService{}
+	for project, services := range composeGhosts(m.composeProjects, m.containers) {
+		for _, svc := range services {
+			haystack := strings.ToLower(svc.Name + " " + svc.Image + " " + project)
+			if filter == "" || strings.Contains(haystack, filter) {
+				ghosts[project] = append(ghosts[project], svc)
+			}
+		}
+	}
+	return ghosts
 }
 
 // renderContainerHeader returns a styled header line for the containers viewport.
 func (m Model) renderContainerHeader() string {
 	cells := make([]string, len(m.builtCols))
 	for i, col := range m.builtCols {
-		cells[i] = renderCell(padHeader(col.Header, col.Width, col.Align), col.Width, col.Align)
+		header := col.Header + m.sortArrow(ContainersView, i)
+		cells[i] = renderCell(padHeader(header, col.Width, col.Align), col.Width, col.Align)
 	}
 	return currentTheme.TableHeaderStyle.Width(m.width).Render(strings.Join(cells, " "))
 }
@@ -299,12 +554,12 @@ func (m Model) renderContainerViewRow(row Row, selected bool) string {
 	switch row.Type {
 	case RowTypeGroup:
 		running, total := groupAggStatus(row.Containers)
-		label := GroupStatusColor(running, total)
+		label := GroupStatusColor(running, total+len(row.Ghosts))
 		prefix := "▼ "
 		if row.Collapsed {
 			prefix = "▶ "
 		}
-		values = []string{currentTheme.GroupHeaderStyle.Render(prefix + row.GroupID), label, "", "", "", "", ""}
+		values = []string{currentTheme.GroupHeaderStyle.Render(prefix + row.GroupID), label, "", "", "", "", "", ""}
 
 	case RowTypeContainer:
 		c := row.Container
@@ -317,26 +572,42 @@ func (m Model) renderContainerViewRow(row Row, selected bool) string {
 				memStr = "..."
 			} else {
 				cpuStr = fmt.Sprintf("%.1f", stat.CPUPercent)
-				if stat.MemLimit > 0 {
-					memStr = utils.FormatBytes(stat.MemUsage)
-				} else {
-					memStr = "..."
-				}
+				memStr = utils.FormatBytes(stat.MemUsage)
 			}
 		}
 		name := c.Names
+		if m.markedContainers[c.ID] {
+			name = "● " + c.Names
+		}
 		if row.GroupID != "" {
-			name = currentTheme.GroupChildStyle.Render("  › " + c.Names)
+			name = "  › " + name
+		}
+		switch {
+		case len(m.alerts[c.ID]) > 0:
+			name = currentTheme.AlertRowStyle.Render(strings.Replace(name, c.Names, "⚠ "+c.Names, 1))
+		case row.GroupID != "":
+			name = currentTheme.GroupChildStyle.Render(name)
 		}
 		values = []string{
 			name,
 			FormatStatus(c.State),
+			FormatHealth(c.Health),
 			simplifyImage(c.Image),
 			c.Ports,
 			cpuStr,
 			memStr,
 			utils.FormatAge(c.CreatedAt),
 		}
+
+	case RowTypeGhost:
+		dim := styleStatusDim
+		values = []string{
+			dim.Render("  › " + row.Service.Name),
+			dim.Render("not created"),
+			"",
+			dim.Render(simplifyImage(row.Service.Image)),
+			"", "", "", "",
+		}
 	}
 
 	line := strings.Join(RenderRow(m.builtCols, values), " ")
@@ -387,29 +658,82 @@ func simplifyImage(img string) string {
 // buildImageRows produces filtered image rows.
 func (m Model) buildImageRows() []table.Row {
 	filter := strings.ToLower(m.filterInput.Value())
-	var rows []table.Row
+	var images []controller.Image
 	for _, img := range m.images {
 		if filter != "" {
 			if !strings.Contains(strings.ToLower(img.Repository+" "+img.Tag), filter) {
 				continue
 			}
 		}
+		images = append(images, img)
+	}
+	if s, ok := m.tableSortOf(ImagesView); ok {
+		sortItems(images, s, compareImages)
+	}
+	var rows []table.Row
+	for _, img := range images {
 		rows = append(rows, table.Row{img.ID, img.Repository, img.Tag, img.Size, img.Created})
 	}
 	return rows
 }
 
+// buildChangeRows produces one row per filesystem change.
+func (m Model) buildChangeRows() []table.Row {
+	changes := slices.Clone(m.changes)
+	if s, ok := m.tableSortOf(ChangesView); ok {
+		sortItems(changes, s, compareChanges)
+	}
+	rows := make([]table.Row, len(changes))
+	for i, c := range changes {
+		rows[i] = table.Row{changeKindLabel(c.Kind), c.Path}
+	}
+	return rows
+}
+
+// changeKindLabel expands a diff kind letter into a readable word.
+func changeKindLabel(kind string) string {
+	switch kind {
+	case "A":
+		return "added"
+	case "C":
+		return "changed"
+	case "D":
+		return "deleted"
+	}
+	return kind
+}
+
 // buildVolumeRows produces filtered volume rows.
 func (m Model) buildVolumeRows() []table.Row {
 	filter := strings.ToLower(m.filterInput.Value())
-	var rows []table.Row
+	var volumes []controller.Volume
 	for _, v := range m.volumes {
 		if filter != "" {
 			if !strings.Contains(strings.ToLower(v.Name), filter) {
 				continue
 			}
 		}
+		volumes = append(volumes, v)
+	}
+	if s, ok := m.tableSortOf(VolumesView); ok {
+		sortItems(volumes, s, compareVolumes)
+	}
+	var rows []table.Row
+	for _, v := range volumes {
 		rows = append(rows, table.Row{v.Name, v.Driver, v.Scope, v.Mountpoint})
 	}
 	return rows
 }
+
+// buildNetworkRows produces network rows.
+func (m Model) buildNetworkRows() []table.Row {
+	networks := slices.Clone(m.networks)
+	if s, ok := m.tableSortOf(NetworksView); ok {
+		sortItems(networks, s, compareNetworks)
+	}
+	rows := make([]table.Row, len(networks))
+	for i, net := range networks {
+		rows[i] = table.Row{net.ID, net.Name, net.Driver, net.Scope}
+	}
+	return rows
+}
@@ -3,6 +3,8 @@ package tui
 import (
 	"testing"
 
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/rluders/berth/internal/controller"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -24,7 +26,7 @@ func TestBuildRows_flatListNoLabels(t *testing.T) {
 		makeContainer("b", "redis", "redis:7", "exited", ""),
 	}
 
-	rows := BuildRows(containers, nil)
+	rows := BuildRows(containers, Grouping{Kind: GroupByCompose}, nil, nil)
 
 	require.Len(t, rows, 2)
 	assert.Equal(t, RowTypeContainer, rows[0].Type)
@@ -38,7 +40,7 @@ func TestBuildRows_groupedByComposeProject(t *testing.T) {
 		makeContainer("b", "db", "postgres", "running", "myapp"),
 	}
 
-	rows := BuildRows(containers, nil)
+	rows := BuildRows(containers, Grouping{Kind: GroupByCompose}, nil, nil)
 
 	// 1 header + 2 container rows = 3
 	require.Len(t, rows, 3)
@@ -56,7 +58,7 @@ func TestBuildRows_collapsedGroupHidesChildren(t *testing.T) {
 	}
 	collapsed := map[string]bool{"myapp": true}
 
-	rows := BuildRows(containers, collapsed)
+	rows := BuildRows(containers, Grouping{Kind: GroupByCompose}, collapsed, nil)
 
 	// Only header, children hidden
 	require.Len(t, rows, 1)
@@ -70,7 +72,7 @@ func TestBuildRows_mixedGroupAndStandalone(t *testing.T) {
 		makeContainer("b", "solo", "redis", "running", ""),
 	}
 
-	rows := BuildRows(containers, nil)
+	rows := BuildRows(containers, Grouping{Kind: GroupByCompose}, nil, nil)
 
 	// 1 group header + 1 group child + 1 standalone = 3
 	require.Len(t, rows, 3)
@@ -81,10 +83,81 @@ func TestBuildRows_mixedGroupAndStandalone(t *testing.T) {
 }
 
 func TestBuildRows_emptyInput(t *testing.T) {
-	rows := BuildRows(nil, nil)
+	rows := BuildRows(nil, Grouping{Kind: GroupByCompose}, nil, nil)
 	assert.Nil(t, rows)
 }
 
+func TestBuildRows_groupByLabel(t *testing.T) {
+	web := makeContainer("a", "web", "nginx", "running", "shop")
+	web.Labels["team"] = "front"
+	api := makeContainer("b", "api", "api", "running", "shop")
+	api.Labels["team"] = "back"
+	solo := makeContainer("c", "solo", "redis", "running", "")
+	ghosts := map[string][]controller.ComposeService{"blog": {{Name: "ghost"}}}
+
+	rows := BuildRows([]controller.Container{web, api, solo}, Grouping{Kind: GroupByLabel, Label: "team"}, nil, ghosts)
+
+	require.Len(t, rows, 5, "two groups of one, then the unlabelled container; no ghosts")
+	assert.Equal(t, "front", rows[0].GroupID)
+	assert.Equal(t, "back", rows[2].GroupID)
+	assert.Equal(t, "solo", rows[4].Name)
+	assert.Empty(t, rows[4].GroupID)
+}
+
+func TestBuildRows_groupByImageStateAndNone(t *testing.T) {
+	containers := []controller.Container{
+		makeContainer("a", "web-1", "nginx", "running", ""),
+		makeContainer("b", "cache", "redis", "exited", ""),
+		makeContainer("c", "web-2", "nginx", "exited", ""),
+	}
+
+	rows := BuildRows(containers, Grouping{Kind: GroupByImage}, nil, nil)
+	require.Len(t, rows, 5)
+	assert.Equal(t, "nginx", rows[0].GroupID)
+	assert.Len(t, rows[0].Containers, 2)
+	assert.Equal(t, "redis", rows[3].GroupID)
+
+	rows = BuildRows(containers, Grouping{Kind: GroupByState}, map[string]bool{"exited": true}, nil)
+	require.Len(t, rows, 3)
+	assert.Equal(t, "running", rows[0].GroupID)
+	assert.Equal(t, "exited", rows[2].GroupID)
+	assert.True(t, rows[2].Collapsed)
+
+	rows = BuildRows(containers, Grouping{Kind: GroupByNone}, nil, nil)
+	require.Len(t, rows, 3)
+	assert.Equal(t, RowTypeContainer, rows[0].Type)
+}
+
+func TestBuildRows_groupByNetworkListsEachNetwork(t *testing.T) {
+	web := makeContainer("a", "web", "nginx", "running", "")
+	web.Networks = []string{"back", "front"}
+	db := makeContainer("b", "db", "postgres", "running", "")
+	db.Networks = []string{"back"}
+
+	rows := BuildRows([]controller.Container{web, db}, Grouping{Kind: GroupByNetwork}, nil, nil)
+
+	require.Len(t, rows, 5)
+	assert.Equal(t, "back", rows[0].GroupID)
+	assert.Equal(t, []string{"web", "db"}, []string{rows[1].Name, rows[2].Name})
+	assert.Equal(t, "front", rows[3].GroupID)
+	assert.Equal(t, "web", rows[4].Name)
+}
+
+func TestParseGrouping(t *testing.T) {
+	for _, g := range []Grouping{
+		{Kind: GroupByCompose},
+		{Kind: GroupByLabel, Label: "com.docker.stack.namespace"},
+		{Kind: GroupByImage},
+		{Kind: GroupByNetwork},
+		{Kind: GroupByState},
+		{Kind: GroupByNone},
+	} {
+		assert.Equal(t, g, ParseGrouping(g.String()))
+	}
+	assert.Equal(t, Grouping{Kind: GroupByCompose}, ParseGrouping(""))
+	assert.Equal(t, Grouping{Kind: GroupByCompose}, ParseGrouping("label:"))
+}
+
 // --- recomputeRows (filter logic) ---
 
 func TestRecomputeRows_noFilterShowsAll(t *testing.T) {
@@ -166,3 +239,96 @@ func TestRecomputeRows_clampsCursorOnFilter(t *testing.T) {
 
 	assert.Equal(t, 0, m.containerCursor)
 }
+
+func TestRecomputeRows_unhealthyOnly(t *testing.T) {
+	m := InitialModel()
+	sick := makeContainer("a", "api", "api:latest", "running", "")
+	sick.Health = controller.HealthUnhealthy
+	healthy := makeContainer("b", "db", "postgres:16", "running", "")
+	healthy.Health = controller.HealthHealthy
+	m.containers = []controller.Container{sick, healthy, makeContainer("c", "redis", "redis:7", "running", "")}
+
+	result, _ := updateModel(t, m, tea.KeyPressMsg{Code: 'H', Text: "H"})
+
+	require.Len(t, result.rows, 1)
+	assert.Equal(t, "api", result.rows[0].Container.Names)
+	assert.Contains(t, result.headerText(), "[unhealthy only]")
+	byImage := result
+	byImage.grouping = Grouping{Kind: GroupByImage}
+	assert.Contains(t, byImage.headerText(), "[by image] [unhealthy only]", "the filter adds to the grouping")
+
+	result, _ = updateModel(t, result, tea.KeyPressMsg{Code: 'H', Text: "H"})
+	assert.Len(t, result.rows, 3)
+}
+
+// --- compose services without containers ---
+
+func TestBuildRows_ghostServices(t *testing.T) {
+	containers := []controller.Container{makeContainer("a", "web", "nginx", "running", "myapp")}
+	ghosts := map[string][]controller.ComposeService{
+		"myapp": {{Name: "db", Image: "postgres:16"}},
+		"other": {{Name: "api"}},
+	}
+
+	rows := BuildRows(containers, Grouping{Kind: GroupByCompose}, nil, ghosts)
+
+	require.Len(t, rows, 5)
+	assert.Equal(t, RowTypeGroup, rows[0].Type)
+	assert.Len(t, rows[0].Ghosts, 1)
+	assert.Equal(t, RowTypeContainer, rows[1].Type)
+	assert.Equal(t, RowTypeGhost, rows[2].Type)
+	assert.Equal(t, "db", rows[2].Service.Name)
+	assert.Equal(t, "other", rows[3].GroupID, "ghost-only projects come last")
+	assert.Equal(t, RowTypeGhost, rows[4].Type)
+
+	rows = BuildRows(containers, Grouping{Kind: GroupByCompose}, map[string]bool{"other": true}, ghosts)
+	assert.Len(t, rows, 4, "collapsed group hides its ghosts")
+}
+
+func ghostModel() Model {
+	m := InitialModel()
+	web := makeContainer("a", "web", "nginx", "exited", "myapp")
+	web.Labels["com.docker.compose.service"] = "web"
+	m.containers = []controller.Container{web}
+	m.composeProjects = map[string]controller.ComposeProject{
+		"myapp": {
+			ComposeTarget: controller.ComposeTarget{Project: "myapp", WorkDir: "/src/myapp"},
+			Services:      []controller.ComposeService{{Name: "db", Image: "postgres:16"}, {Name: "web", Image: "nginx"}},
+		},
+	}
+	m.recomputeRows()
+	return m
+}
+
+func TestRecomputeRows_ghostsFromComposeProjects(t *testing.T) {
+	m := ghostModel()
+
+	require.Len(t, m.rows, 3, "stopped web keeps its container row, db has none")
+	assert.Equal(t, RowTypeGhost, m.rows[2].Type)
+	assert.Equal(t, "db", m.rows[2].Name)
+	assert.Contains(t, ansi.Strip(m.renderContainerViewRow(m.rows[2], false)), "not created")
+
+	m.filterInput.SetValue("postgres")
+	m.recomputeRows()
+	require.Len(t, m.rows, 2)
+	assert.Equal(t, RowTypeGhost, m.rows[1].Type)
+
+	m.filterInput.SetValue("")
+	m.unhealthyOnly = true
+	m.recomputeRows()
+	assert.Empty(t, m.rows)
+}
+
+func TestContainersView_upOnGhostService(t *testing.T) {
+	m := ghostModel()
+	pinComposeTool(t, "docker compose")
+	m.moveContainerCursor(2)
+
+	result, cmd := updateModel(t, m, tea.KeyPressMsg{Code: 'u', Text: "u"})
+	t.Cleanup(func() { result.composeCancel() })
+
+	require.NotNil(t, cmd)
+	assert.Equal(t, "docker compose up -d db  [myapp]", result.statusMessage)
+	assert.Equal(t, "docker compose -p myapp up -d db", result.BuildCommandPreview())
+	assert.Equal(t, "/src/myapp", result.composeTarget("myapp").WorkDir)
+}
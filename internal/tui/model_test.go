@@ -18,6 +18,7 @@ func TestInitialModel_mapsInitialized(t *testing.T) {
 	m := InitialModel()
 	assert.NotNil(t, m.containerStats)
 	assert.NotNil(t, m.collapsedGroups)
+	assert.NotNil(t, m.execPrefs)
 }
 
 func TestInitialModel_spinnerReady(t *testing.T) {
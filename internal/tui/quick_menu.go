@@ -184,24 +184,51 @@ func NewContainerQuickMenu(id, name string) *QuickMenu {
 				Label: "Logs",
 				Key:   "l",
 				Action: func(m Model) (Model, tea.Cmd) {
-					m.stopLogStream()
-					m.logLines = nil
-					m.logFollowing = true
-					m.currentLogContainerID = id
-					m.currentLogGroupName = ""
-					m.pushView(LogsView)
-					m.logReady = true
-					ch, cancel, waitCmd := startLogStreamCmd(id)
-					m.logCh = ch
-					m.logCancel = cancel
-					return m, waitCmd
+					return m.openContainerLogs(id)
 				},
 			},
 			{
-				Label: "Exec shell",
+				Label: "Exec into container",
 				Key:   "e",
 				Action: func(m Model) (Model, tea.Cmd) {
-					return m, execShellCmd(id)
+					return m.openExecForm(id, name)
+				},
+			},
+			{
+				Label: "Run command",
+				Key:   "!",
+				Action: func(m Model) (Model, tea.Cmd) {
+					return m.openRunCommandForm(id, name)
+				},
+			},
+			{
+				Label: "Processes (top)",
+				Key:   "t",
+				Action: func(m Model) (Model, tea.Cmd) {
+					return m.openTopView(id, name)
+				},
+			},
+			{
+				Label: "Filesystem changes",
+				Key:   "c",
+				Action: func(m Model) (Model, tea.Cmd) {
+					return m.openChangesView(id, name)
+				},
+			},
+			{
+				Label: "Copy from container",
+				Key:   "o",
+				Action: func(m Model) (Model, tea.Cmd) {
+					m.form = newCopyOutForm(id, "")
+					return m, nil
+				},
+			},
+			{
+				Label: "Copy into container",
+				Key:   "i",
+				Action: func(m Model) (Model, tea.Cmd) {
+					m.form = newCopyInForm(id, "/")
+					return m, nil
 				},
 			},
 			{
@@ -5,13 +5,36 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+
+	"github.com/rluders/berth/internal/controller"
 )
 
 type persistedState struct {
-	CollapsedGroups map[string]bool `json:"collapsedGroups"`
+	// CollapsedGroups holds the collapsed compose projects; GroupCollapsed
+	// the collapsed groups of the other groupings, by Grouping.String.
+	CollapsedGroups map[string]bool            `json:"collapsedGroups"`
+	GroupCollapsed  map[string]map[string]bool `json:"groupCollapsed,omitempty"`
+	Grouping        string                     `json:"grouping,omitempty"`
+	ExecPrefs       map[string]execPrefs       `json:"execPrefs,omitempty"`
+	ExecHistory     map[string][]string        `json:"execHistory,omitempty"`
+	// DetailsCollapsed holds the titles of collapsed details view sections.
+	DetailsCollapsed map[string]bool `json:"detailsCollapsed,omitempty"`
+	// LogExportDir is the directory logs were last saved to.
+	LogExportDir string `json:"logExportDir,omitempty"`
+	// ComposeProjects holds the compose projects seen in container labels,
+	// so they are still found on disk after their containers are removed.
+	ComposeProjects map[string]controller.ComposeTarget `json:"composeProjects,omitempty"`
+	// ComposeOptions holds the profiles and extra env files chosen per
+	// compose project.
+	ComposeOptions map[string]composeOptions `json:"composeOptions,omitempty"`
+	// TableSorts holds the sort of each table, by sortableView name.
+	TableSorts map[string]tableSort `json:"tableSorts,omitempty"`
 }
 
-func stateFilePath() (string, error) {
+// stateFilePath returns where the state is kept; tests point it elsewhere.
+var stateFilePath = userStateFilePath
+
+func userStateFilePath() (string, error) {
 	cacheDir, err := os.UserCacheDir()
 	if err != nil {
 		return "", err
@@ -36,11 +59,40 @@ func loadState() persistedState {
 	return s
 }
 
-func loadedCollapsedGroups() map[string]bool {
-	if m := loadState().CollapsedGroups; m != nil {
-		return m
+// orEmpty returns m, or an empty map when m is nil.
+func orEmpty[V any](m map[string]V) map[string]V {
+	if m == nil {
+		return make(map[string]V)
+	}
+	return m
+}
+
+// persistState writes every persisted field of the model to disk.
+func (m Model) persistState() {
+	saveState(persistedState{
+		CollapsedGroups:  m.groupCollapsed[Grouping{Kind: GroupByCompose}.String()],
+		GroupCollapsed:   m.otherGroupCollapsed(),
+		Grouping:         m.grouping.String(),
+		ExecPrefs:        m.execPrefs,
+		ExecHistory:      m.execHistory,
+		DetailsCollapsed: m.detailsCollapsed,
+		LogExportDir:     m.logExportDir,
+		ComposeProjects:  m.composeTargets,
+		ComposeOptions:   m.composeOptions,
+		TableSorts:       m.tableSorts,
+	})
+}
+
+// otherGroupCollapsed returns the collapsed groups of the groupings other
+// than compose, which are saved on their own.
+func (m Model) otherGroupCollapsed() map[string]map[string]bool {
+	other := make(map[string]map[string]bool)
+	for grouping, collapsed := range m.groupCollapsed {
+		if grouping != (Grouping{Kind: GroupByCompose}).String() && len(collapsed) > 0 {
+			other[grouping] = collapsed
+		}
 	}
-	return make(map[string]bool)
+	return other
 }
 
 func saveState(s persistedState) {
@@ -0,0 +1,39 @@
+package tui
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	// Nothing is loaded or saved unless a test asks for its own state file,
+	// so the tests leave the user's state alone and do not see each other's.
+	stateFilePath = func() (string, error) { return "", errors.New("no state file in tests") }
+	os.Exit(m.Run())
+}
+
+// useStateFile points state persistence at a fresh file for the test.
+func useStateFile(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "berth", "state.json")
+	orig := stateFilePath
+	stateFilePath = func() (string, error) { return path, nil }
+	t.Cleanup(func() { stateFilePath = orig })
+	return path
+}
+
+func TestPersistState_roundTrip(t *testing.T) {
+	path := useStateFile(t)
+	m := InitialModel()
+	m.logExportDir = "/tmp/logs"
+	m.persistState()
+
+	require.FileExists(t, path)
+	assert.Equal(t, "/tmp/logs", loadState().LogExportDir)
+	assert.Equal(t, "/tmp/logs", InitialModel().logExportDir)
+}
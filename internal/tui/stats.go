@@ -0,0 +1,159 @@
+package tui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	"github.com/rluders/berth/internal/config"
+	"github.com/rluders/berth/internal/controller"
+	"github.com/rluders/berth/internal/utils"
+)
+
+// statsHistorySize is the number of samples kept per container (the engine
+// streams roughly one sample per second).
+const statsHistorySize = 120
+
+// statsHistoryLen returns the number of samples to keep per container: the
+// sparkline history, or more when the CPU alert's window is longer. Twice the
+// window leaves room for samples arriving faster than one a second.
+func statsHistoryLen(alerts config.Alerts) int {
+	return max(statsHistorySize, 2*alerts.CPUSeconds)
+}
+
+// sparkWidth is the number of samples drawn in a sparkline.
+const sparkWidth = 40
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline renders the last width values as block characters scaled to
+// maxValue (or to the largest value when maxValue is 0). Missing history is
+// padded on the left so the newest sample is always at the right edge.
+func sparkline(values []float64, width int, maxValue float64) string {
+	if width <= 0 {
+		return ""
+	}
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	if maxValue <= 0 {
+		for _, v := range values {
+			maxValue = math.Max(maxValue, v)
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(strings.Repeat(" ", width-len(values)))
+	for _, v := range values {
+		idx := 0
+		if maxValue > 0 && v > 0 {
+			idx = int(math.Round(v / maxValue * float64(len(sparkBlocks)-1)))
+			idx = min(max(idx, 0), len(sparkBlocks)-1)
+		}
+		sb.WriteRune(sparkBlocks[idx])
+	}
+	return sb.String()
+}
+
+// statSeries extracts one metric from a history.
+func statSeries(history []controller.StatSample, get func(controller.ContainerStat) float64) []float64 {
+	values := make([]float64, len(history))
+	for i, s := range history {
+		values[i] = get(s.Stat)
+	}
+	return values
+}
+
+// rateSeries converts a cumulative counter into per-second rates between
+// consecutive samples. Counter resets (container restart) count as zero.
+func rateSeries(history []controller.StatSample, get func(controller.ContainerStat) uint64) []float64 {
+	if len(history) < 2 {
+		return nil
+	}
+	rates := make([]float64, 0, len(history)-1)
+	for i := 1; i < len(history); i++ {
+		prev, cur := history[i-1], history[i]
+		secs := cur.Time.Sub(prev.Time).Seconds()
+		var r float64
+		if a, b := get(prev.Stat), get(cur.Stat); secs > 0 && b >= a {
+			r = float64(b-a) / secs
+		}
+		rates = append(rates, r)
+	}
+	return rates
+}
+
+// lastValue returns the newest value of a series, or 0 when it is empty.
+func lastValue(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	return values[len(values)-1]
+}
+
+// formatRate renders a bytes-per-second rate.
+func formatRate(bytesPerSec float64) string {
+	return utils.FormatBytes(uint64(bytesPerSec)) + "/s"
+}
+
+// renderStatLines renders the resource lines (with sparklines) for one
+// container's history, shared by the stats view and the details view.
+func renderStatLines(history []controller.StatSample) []string {
+	th := currentTheme
+	muted := lipgloss.NewStyle().Foreground(lipgloss.Color(colorMuted))
+
+	if len(history) == 0 {
+		return []string{"  " + muted.Render("(waiting for stats)")}
+	}
+	latest := history[len(history)-1].Stat
+
+	line := func(label, spark, value string) string {
+		return "  " + th.CardTitleStyle.Render(fmt.Sprintf("%-9s", label)) + "  " +
+			th.SparklineStyle.Render(spark) + "  " + th.CardValueStyle.Render(value)
+	}
+
+	cpu := statSeries(history, func(s controller.ContainerStat) float64 { return s.CPUPercent })
+	mem := statSeries(history, func(s controller.ContainerStat) float64 { return float64(s.MemUsage) })
+	rx := rateSeries(history, func(s controller.ContainerStat) uint64 { return s.NetRx })
+	tx := rateSeries(history, func(s controller.ContainerStat) uint64 { return s.NetTx })
+	blkR := rateSeries(history, func(s controller.ContainerStat) uint64 { return s.BlockRead })
+	blkW := rateSeries(history, func(s controller.ContainerStat) uint64 { return s.BlockWrite })
+	pids := statSeries(history, func(s controller.ContainerStat) float64 { return float64(s.PIDs) })
+
+	memValue := utils.FormatBytes(latest.MemUsage)
+	if latest.MemLimit > 0 {
+		memValue += " / " + utils.FormatBytes(latest.MemLimit)
+	}
+
+	return []string{
+		line("CPU", sparkline(cpu, sparkWidth, 0), fmt.Sprintf("%.1f%%", latest.CPUPercent)),
+		line("Memory", sparkline(mem, sparkWidth, float64(latest.MemLimit)), memValue),
+		line("Net rx", sparkline(rx, sparkWidth, 0), fmt.Sprintf("%s  (%s total)", formatRate(lastValue(rx)), utils.FormatBytes(latest.NetRx))),
+		line("Net tx", sparkline(tx, sparkWidth, 0), fmt.Sprintf("%s  (%s total)", formatRate(lastValue(tx)), utils.FormatBytes(latest.NetTx))),
+		line("Blk read", sparkline(blkR, sparkWidth, 0), fmt.Sprintf("%s  (%s total)", formatRate(lastValue(blkR)), utils.FormatBytes(latest.BlockRead))),
+		line("Blk write", sparkline(blkW, sparkWidth, 0), fmt.Sprintf("%s  (%s total)", formatRate(lastValue(blkW)), utils.FormatBytes(latest.BlockWrite))),
+		line("PIDs", sparkline(pids, sparkWidth, 0), fmt.Sprintf("%d", latest.PIDs)),
+	}
+}
+
+// renderStatsContent renders one card per running container for the stats view.
+func (m Model) renderStatsContent() string {
+	th := currentTheme
+
+	var cards []string
+	for _, c := range m.containers {
+		if c.State != "running" {
+			continue
+		}
+		header := th.SectionStyle.Render("▸ "+c.Names) + "  " +
+			lipgloss.NewStyle().Foreground(lipgloss.Color(colorMuted)).Render(c.ID)
+		cards = append(cards, th.CardStyle.Render(header+"\n"+strings.Join(renderStatLines(m.statsMonitor.History(c.ID)), "\n")))
+	}
+	if len(cards) == 0 {
+		return lipgloss.NewStyle().
+			Foreground(lipgloss.Color(colorMuted)).
+			Render("  No running containers.")
+	}
+	return strings.Join(cards, "\n")
+}
@@ -0,0 +1,92 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/rluders/berth/internal/controller"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		width  int
+		max    float64
+		want   string
+	}{
+		{"scales to largest value", []float64{0, 5, 10}, 3, 0, "▁▅█"},
+		{"scales to fixed max", []float64{5}, 1, 10, "▅"},
+		{"pads missing history on the left", []float64{10}, 3, 0, "  █"},
+		{"keeps newest values", []float64{10, 0, 0, 10}, 2, 0, "▁█"},
+		{"all zero", []float64{0, 0}, 2, 0, "▁▁"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, sparkline(tt.values, tt.width, tt.max))
+		})
+	}
+}
+
+func TestRateSeries(t *testing.T) {
+	start := time.Now()
+	history := []controller.StatSample{
+		{Time: start, Stat: controller.ContainerStat{NetRx: 1000}},
+		{Time: start.Add(2 * time.Second), Stat: controller.ContainerStat{NetRx: 3000}},
+		{Time: start.Add(3 * time.Second), Stat: controller.ContainerStat{NetRx: 100}}, // restart
+	}
+
+	rates := rateSeries(history, func(s controller.ContainerStat) uint64 { return s.NetRx })
+
+	assert.Equal(t, []float64{1000, 0}, rates)
+	assert.Nil(t, rateSeries(history[:1], func(s controller.ContainerStat) uint64 { return s.NetRx }))
+}
+
+func TestRenderStatLines_showsAllMetrics(t *testing.T) {
+	history := []controller.StatSample{
+		{Time: time.Now(), Stat: controller.ContainerStat{CPUPercent: 12.5, MemUsage: 1 << 20, MemLimit: 1 << 30, PIDs: 4}},
+	}
+
+	out := ansi.Strip(strings.Join(renderStatLines(history), "\n"))
+
+	for _, label := range []string{"CPU", "Memory", "Net rx", "Net tx", "Blk read", "Blk write", "PIDs"} {
+		assert.Contains(t, out, label)
+	}
+	assert.Contains(t, out, "12.5%")
+}
+
+func TestContainersKey_statsOpensStatsView(t *testing.T) {
+	m := InitialModel()
+
+	result, _ := updateModel(t, m, tea.KeyPressMsg{Code: 'S', Text: "S"})
+
+	assert.Equal(t, StatsView, result.currentView)
+}
+
+func TestHandleContainerStatsMsg_refreshesStatsView(t *testing.T) {
+	m := InitialModel()
+	m.statsViewPort.SetWidth(120)
+	m.statsViewPort.SetHeight(40)
+	m.containers = []controller.Container{{ID: "abc", Names: "web", State: "running"}}
+	m.pushView(StatsView)
+	m.statsMonitor.Record("abc", controller.StatSample{Time: time.Now(), Stat: controller.ContainerStat{PIDs: 3}})
+
+	result, _ := updateModel(t, m, containerStatsMsg{"abc": {PIDs: 3}})
+
+	assert.Contains(t, ansi.Strip(result.statsViewPort.View()), "web")
+}
+
+func TestQuit_stopsStatsStreams(t *testing.T) {
+	m := InitialModel()
+	m.statsMonitor.Record("abc", controller.StatSample{Time: time.Now(), Stat: controller.ContainerStat{PIDs: 3}})
+
+	result, cmd := updateModel(t, m, tea.KeyPressMsg{Code: 'q', Text: "q"})
+
+	require.NotNil(t, cmd)
+	assert.Empty(t, result.statsMonitor.Latest())
+}
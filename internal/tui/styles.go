@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"charm.land/lipgloss/v2"
+	"github.com/rluders/berth/internal/controller"
 )
 
 // Catppuccin Mocha palette
@@ -94,14 +95,21 @@ type Theme struct {
 	FilterStyle lipgloss.Style
 
 	// Log viewer
-	LogTimestampStyle lipgloss.Style
-	LogErrorStyle     lipgloss.Style
-	LogWarnStyle      lipgloss.Style
-	LogInfoStyle      lipgloss.Style
-	LogDebugStyle     lipgloss.Style
-	LogLineNumStyle   lipgloss.Style
-	LogFollowStyle    lipgloss.Style
-	LogPausedStyle    lipgloss.Style
+	LogTimestampStyle    lipgloss.Style
+	LogErrorStyle        lipgloss.Style
+	LogWarnStyle         lipgloss.Style
+	LogInfoStyle         lipgloss.Style
+	LogDebugStyle        lipgloss.Style
+	LogLineNumStyle      lipgloss.Style
+	LogFollowStyle       lipgloss.Style
+	LogPausedStyle       lipgloss.Style
+	LogMatchStyle        lipgloss.Style
+	LogCurrentMatchStyle lipgloss.Style
+	LogColumnStyle       lipgloss.Style
+	LogMarkerStyle       lipgloss.Style
+
+	// Stats
+	SparklineStyle lipgloss.Style
 
 	// Viewport
 	ViewportStyle lipgloss.Style
@@ -112,6 +120,7 @@ type Theme struct {
 	// Container accordion
 	GroupHeaderStyle lipgloss.Style
 	GroupChildStyle  lipgloss.Style
+	AlertRowStyle    lipgloss.Style
 
 	// Legacy (referenced by view.go / update.go)
 	ModalStyle lipgloss.Style
@@ -298,6 +307,8 @@ func DefaultTheme() Theme {
 	t.LogInfoStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(colorBlue))
 	t.LogDebugStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(colorMuted))
 	t.LogLineNumStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(colorOverlay))
+	t.LogColumnStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(colorTeal))
+	t.LogMarkerStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(colorMauve)).Bold(true)
 	t.LogFollowStyle = lipgloss.NewStyle().
 		Foreground(lipgloss.Color(colorBase)).
 		Background(lipgloss.Color(colorGreen)).
@@ -307,6 +318,16 @@ func DefaultTheme() Theme {
 		Foreground(lipgloss.Color(colorBase)).
 		Background(lipgloss.Color(colorYellow)).
 		Padding(0, 1)
+	t.LogMatchStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color(colorBase)).
+		Background(lipgloss.Color(colorYellow))
+	t.LogCurrentMatchStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color(colorBase)).
+		Background(lipgloss.Color(colorPeach)).
+		Bold(true)
+
+	// Stats
+	t.SparklineStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(colorTeal))
 
 	// Viewport
 	t.ViewportStyle = lipgloss.NewStyle().
@@ -322,6 +343,9 @@ func DefaultTheme() Theme {
 		Bold(true)
 	t.GroupChildStyle = lipgloss.NewStyle().
 		Foreground(lipgloss.Color(colorSubtext))
+	t.AlertRowStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color(colorRed)).
+		Bold(true)
 
 	return t
 }
@@ -367,6 +391,21 @@ func FormatStatus(state string) string {
 	}
 }
 
+// FormatHealth returns a styled health check state, or a muted dash when the
+// container has no health check.
+func FormatHealth(health string) string {
+	switch health {
+	case controller.HealthHealthy:
+		return styleStatusRunning.Render("♥ healthy")
+	case controller.HealthUnhealthy:
+		return styleStatusDead.Render("✗ unhealthy")
+	case controller.HealthStarting:
+		return styleStatusRestarting.Render("… starting")
+	default:
+		return styleStatusDim.Render("-")
+	}
+}
+
 // StatusBadge returns a styled status badge string for a container status.
 func StatusBadge(status string) string {
 	switch {
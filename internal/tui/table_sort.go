@@ -0,0 +1,407 @@
+package tui
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"charm.land/bubbles/v2/table"
+	"github.com/rluders/berth/internal/controller"
+)
+
+// tableSort is the order of a table: the column it is sorted by, as an index
+// into the view's column specs, and the direction.
+type tableSort struct {
+	Column int  `json:"column"`
+	Desc   bool `json:"desc,omitempty"`
+}
+
+// sortableView returns the name a view's sort is persisted under and its
+// column specs; ok is false for views without a sortable table.
+func sortableView(v ViewType) (name string, cols []Column, ok bool) {
+	switch v {
+	case ContainersView:
+		return "containers", containerCols, true
+	case ImagesView:
+		return "images", imageCols, true
+	case VolumesView:
+		return "volumes", volumeCols, true
+	case NetworksView:
+		return "networks", networkCols, true
+	case ChangesView:
+		return "changes", changeCols, true
+	case TopView:
+		return "top", topCols, true
+	case ComposeOpsView:
+		return "composeHistory", composeOpCols, true
+	case DriftView:
+		return "drift", driftCols, true
+	}
+	return "", nil, false
+}
+
+// tableSortOf returns the sort of view v; ok is false while its rows keep the
+// order the engine returned them in.
+func (m Model) tableSortOf(v ViewType) (s tableSort, ok bool) {
+	name, _, _ := sortableView(v)
+	s, ok = m.tableSorts[name]
+	return s, ok
+}
+
+// setTableSort sets the sort of the current view, or clears it when sorted
+// is false, then re-sorts the view's rows and persists the choice.
+func (m *Model) setTableSort(s tableSort, sorted bool) {
+	name, _, ok := sortableView(m.currentView)
+	if !ok {
+		return
+	}
+	if sorted {
+		m.tableSorts[name] = s
+	} else {
+		delete(m.tableSorts, name)
+	}
+	m.refreshSortedView()
+	m.persistState()
+}
+
+// cycleSort sorts the current view by its next column. After the last column
+// the rows go back to the engine's order.
+func (m *Model) cycleSort() {
+	_, cols, ok := sortableView(m.currentView)
+	if !ok {
+		return
+	}
+	s, sorted := m.tableSortOf(m.currentView)
+	switch {
+	case !sorted:
+		m.setTableSort(tableSort{}, true)
+	case s.Column+1 < len(cols):
+		s.Column++
+		m.setTableSort(s, true)
+	default:
+		m.setTableSort(tableSort{}, false)
+	}
+}
+
+// reverseSort flips the direction of the current view's sort; an unsorted
+// view is sorted by its first column, descending.
+func (m *Model) reverseSort() {
+	s, _ := m.tableSortOf(m.currentView)
+	s.Desc = !s.Desc
+	m.setTableSort(s, true)
+}
+
+// sortByHeader sorts the current view by the column under x on the header
+// line, flipping the direction when the view is already sorted by it.
+func (m *Model) sortByHeader(x int) {
+	col := m.headerColumnAt(x)
+	if col < 0 {
+		return
+	}
+	s, sorted := m.tableSortOf(m.currentView)
+	if sorted && s.Column == col {
+		s.Desc = !s.Desc
+	} else {
+		s = tableSort{Column: col}
+	}
+	m.setTableSort(s, true)
+}
+
+// headerColumnAt returns the index of the current view's column at x, or -1
+// when x is past the last column.
+func (m Model) headerColumnAt(x int) int {
+	var widths []int
+	switch m.currentView {
+	case ContainersView:
+		// Container cells are joined by a single space.
+		for _, col := range m.builtCols {
+			widths = append(widths, col.Width+1)
+		}
+	default:
+		t, ok := m.sortableTable()
+		if !ok {
+			return -1
+		}
+		// Table cells carry one space of padding on each side.
+		for _, col := range t.Columns() {
+			widths = append(widths, col.Width+2)
+		}
+	}
+	for i, w := range widths {
+		if x < w {
+			return i
+		}
+		x -= w
+	}
+	return -1
+}
+
+// sortableTable returns the table of the current view, when it has one.
+func (m Model) sortableTable() (table.Model, bool) {
+	switch m.currentView {
+	case ImagesView:
+		return m.imageTable, true
+	case VolumesView:
+		return m.volumeTable, true
+	case NetworksView:
+		return m.networkTable, true
+	case ChangesView:
+		return m.changesTable, true
+	case TopView:
+		return m.topTable, true
+	case ComposeOpsView:
+		return m.composeOpsTable, true
+	case DriftView:
+		return m.driftTable, true
+	}
+	return table.Model{}, false
+}
+
+// refreshSortedView rebuilds the rows and headers of the current view after
+// its sort changed.
+func (m *Model) refreshSortedView() {
+	switch m.currentView {
+	case ContainersView:
+		m.recomputeRows()
+	case ImagesView:
+		m.imageTable.SetColumns(m.sortedColumns(ImagesView, m.imageTable.Width()))
+		m.imageTable.SetRows(m.buildImageRows())
+	case VolumesView:
+		m.volumeTable.SetColumns(m.sortedColumns(VolumesView, m.volumeTable.Width()))
+		m.volumeTable.SetRows(m.buildVolumeRows())
+	case NetworksView:
+		m.networkTable.SetColumns(m.sortedColumns(NetworksView, m.networkTable.Width()))
+		m.networkTable.SetRows(m.buildNetworkRows())
+	case ChangesView:
+		m.changesTable.SetColumns(m.sortedColumns(ChangesView, m.changesTable.Width()))
+		m.changesTable.SetRows(m.buildChangeRows())
+	case TopView:
+		m.refreshTopTable()
+	case ComposeOpsView:
+		m.composeOpsTable.SetColumns(m.sortedColumns(ComposeOpsView, m.composeOpsTable.Width()))
+		m.refreshComposeOps()
+	case DriftView:
+		m.driftTable.SetColumns(m.sortedColumns(DriftView, m.driftTable.Width()))
+		m.refreshDriftRows()
+	}
+}
+
+// sortArrow returns the indicator shown after the header of the column view
+// v is sorted by, or empty for the other columns.
+func (m Model) sortArrow(v ViewType, column int) string {
+	s, ok := m.tableSortOf(v)
+	switch {
+	case !ok || s.Column != column:
+		return ""
+	case s.Desc:
+		return " ▼"
+	}
+	return " ▲"
+}
+
+// sortedColumns returns the table columns of view v for width, with the sort
+// indicator on the active column's header.
+func (m Model) sortedColumns(v ViewType, width int) []table.Column {
+	_, specs, _ := sortableView(v)
+	cols := tableColumns(width, specs)
+	for i := range cols {
+		cols[i].Title += m.sortArrow(v, i)
+	}
+	return cols
+}
+
+// sortItems orders items in place by the column of s, keeping the current
+// order between equal items. compare orders two items ascending by a column.
+func sortItems[T any](items []T, s tableSort, compare func(a, b T, column int) int) {
+	slices.SortStableFunc(items, func(a, b T) int {
+		c := compare(a, b, s.Column)
+		if s.Desc {
+			return -c
+		}
+		return c
+	})
+}
+
+// compareNumbers orders two decimal strings by value; values that do not
+// parse come first.
+func compareNumbers(a, b string) int {
+	ai, aErr := strconv.ParseInt(a, 10, 64)
+	bi, bErr := strconv.ParseInt(b, 10, 64)
+	if aErr != nil || bErr != nil {
+		return cmp.Compare(boolRank(aErr == nil), boolRank(bErr == nil))
+	}
+	return cmp.Compare(ai, bi)
+}
+
+// boolRank orders false before true.
+func boolRank(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// compareContainers orders containers by a column of containerCols. CPU and
+// memory come from stats; containers without a sample sort first. Age is
+// ascending from the most recently created container.
+func compareContainers(stats map[string]controller.ContainerStat) func(a, b controller.Container, column int) int {
+	return func(a, b controller.Container, column int) int {
+		sa, aOK := stats[a.ID]
+		sb, bOK := stats[b.ID]
+		if (column == 5 || column == 6) && aOK != bOK {
+			return cmp.Compare(boolRank(aOK), boolRank(bOK))
+		}
+		switch column {
+		case 1:
+			return strings.Compare(a.State, b.State)
+		case 2:
+			return strings.Compare(a.Health, b.Health)
+		case 3:
+			return strings.Compare(simplifyImage(a.Image), simplifyImage(b.Image))
+		case 4:
+			return strings.Compare(a.Ports, b.Ports)
+		case 5:
+			return cmp.Compare(sa.CPUPercent, sb.CPUPercent)
+		case 6:
+			return cmp.Compare(sa.MemUsage, sb.MemUsage)
+		case 7:
+			return cmp.Compare(b.CreatedAt, a.CreatedAt)
+		}
+		return strings.Compare(a.Names, b.Names)
+	}
+}
+
+// sortContainerRows sorts the container rows of each group, and the
+// standalone containers, in place. Group rows and services without a
+// container keep their position.
+func sortContainerRows(rows []Row, s tableSort, compare func(a, b controller.Container, column int) int) {
+	for start := 0; start < len(rows); {
+		if rows[start].Type != RowTypeContainer {
+			start++
+			continue
+		}
+		end := start + 1
+		for end < len(rows) && rows[end].Type == RowTypeContainer && rows[end].GroupID == rows[start].GroupID {
+			end++
+		}
+		sortItems(rows[start:end], s, func(a, b Row, column int) int {
+			return compare(*a.Container, *b.Container, column)
+		})
+		start = end
+	}
+}
+
+func compareImages(a, b controller.Image, column int) int {
+	switch column {
+	case 1:
+		return strings.Compare(a.Repository, b.Repository)
+	case 2:
+		return strings.Compare(a.Tag, b.Tag)
+	case 3:
+		return compareNumbers(a.Size, b.Size)
+	case 4:
+		return compareNumbers(a.Created, b.Created)
+	}
+	return strings.Compare(a.ID, b.ID)
+}
+
+func compareVolumes(a, b controller.Volume, column int) int {
+	switch column {
+	case 1:
+		return strings.Compare(a.Driver, b.Driver)
+	case 2:
+		return strings.Compare(a.Scope, b.Scope)
+	case 3:
+		return strings.Compare(a.Mountpoint, b.Mountpoint)
+	}
+	return strings.Compare(a.Name, b.Name)
+}
+
+func compareNetworks(a, b controller.Network, column int) int {
+	switch column {
+	case 1:
+		return strings.Compare(a.Name, b.Name)
+	case 2:
+		return strings.Compare(a.Driver, b.Driver)
+	case 3:
+		return strings.Compare(a.Scope, b.Scope)
+	}
+	return strings.Compare(a.ID, b.ID)
+}
+
+func compareChanges(a, b controller.FileChange, column int) int {
+	if column == 0 {
+		return strings.Compare(changeKindLabel(a.Kind), changeKindLabel(b.Kind))
+	}
+	return strings.Compare(a.Path, b.Path)
+}
+
+// compareProcesses orders processes by a column of topCols; PIDs compare by
+// value.
+func compareProcesses(a, b controller.Process, column int) int {
+	switch column {
+	case 1:
+		return strings.Compare(a.User, b.User)
+	case 2:
+		return cmp.Compare(a.CPU, b.CPU)
+	case 3:
+		return cmp.Compare(a.Memory, b.Memory)
+	case 4:
+		return strings.Compare(a.Command, b.Command)
+	}
+	return compareNumbers(a.PID, b.PID)
+}
+
+// compareComposeOps orders operations by a column of composeOpCols; running
+// operations have no duration yet and sort first by it.
+func compareComposeOps(a, b composeOp, column int) int {
+	switch column {
+	case 1:
+		return strings.Compare(a.command, b.command)
+	case 2:
+		return a.started.Compare(b.started)
+	case 3:
+		return cmp.Compare(opDuration(a), opDuration(b))
+	case 4:
+		return strings.Compare(a.status(), b.status())
+	}
+	return strings.Compare(a.target.Project, b.target.Project)
+}
+
+// opDuration returns how long a finished operation took, or -1 while it runs.
+func opDuration(op composeOp) time.Duration {
+	if op.ended.IsZero() {
+		return -1
+	}
+	return op.ended.Sub(op.started)
+}
+
+// compareDrift orders services by a column of driftCols; the change columns
+// compare the first change of each service.
+func compareDrift(a, b controller.ServiceDrift, column int) int {
+	switch column {
+	case 1:
+		return strings.Compare(a.Container, b.Container)
+	case 2:
+		return strings.Compare(a.Status, b.Status)
+	case 3, 4, 5:
+		ac, bc := firstChange(a), firstChange(b)
+		switch column {
+		case 3:
+			return strings.Compare(ac.Field, bc.Field)
+		case 4:
+			return strings.Compare(ac.Want, bc.Want)
+		}
+		return strings.Compare(ac.Got, bc.Got)
+	}
+	return strings.Compare(a.Service, b.Service)
+}
+
+func firstChange(d controller.ServiceDrift) controller.DriftChange {
+	if len(d.Changes) == 0 {
+		return controller.DriftChange{}
+	}
+	return d.Changes[0]
+}
@@ -0,0 +1,173 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/rluders/berth/internal/controller"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sortTestModel(t *testing.T, view ViewType) Model {
+	t.Helper()
+	useStateFile(t)
+	m := InitialModel()
+	m.syncTableSizes(120, 20)
+	m.currentView = view
+	return m
+}
+
+func imageIDs(m Model) []string {
+	var ids []string
+	for _, row := range m.imageTable.Rows() {
+		ids = append(ids, row[0])
+	}
+	return ids
+}
+
+func TestSortKeys_cycleColumnsThenEngineOrder(t *testing.T) {
+	m := sortTestModel(t, NetworksView)
+	m.networks = []controller.Network{
+		{ID: "b1", Name: "web", Driver: "bridge", Scope: "local"},
+		{ID: "a2", Name: "db", Driver: "overlay", Scope: "swarm"},
+	}
+	m.networkTable.SetRows(m.buildNetworkRows())
+
+	m = typeKeys(t, m, "o")
+	assert.Equal(t, "a2", m.networkTable.Rows()[0][0], "first column ascending")
+	assert.Equal(t, "ID ▲", m.networkTable.Columns()[0].Title)
+
+	m = typeKeys(t, m, "O")
+	assert.Equal(t, "b1", m.networkTable.Rows()[0][0])
+	assert.Equal(t, "ID ▼", m.networkTable.Columns()[0].Title)
+
+	m = typeKeys(t, m, "ooo")
+	assert.Equal(t, "Scope ▼", m.networkTable.Columns()[3].Title, "direction is kept across columns")
+
+	m = typeKeys(t, m, "o")
+	_, sorted := m.tableSortOf(NetworksView)
+	assert.False(t, sorted, "past the last column the engine's order comes back")
+	assert.Equal(t, "b1", m.networkTable.Rows()[0][0])
+	assert.Equal(t, "ID", m.networkTable.Columns()[0].Title)
+}
+
+func TestHeaderClick_sortsImagesBySizeNumerically(t *testing.T) {
+	m := sortTestModel(t, ImagesView)
+	m.images = []controller.Image{
+		{ID: "aaa", Repository: "nginx", Size: "900", Created: "3"},
+		{ID: "bbb", Repository: "postgres", Size: "12000", Created: "1"},
+		{ID: "ccc", Repository: "alpine", Size: "80", Created: "2"},
+	}
+	m.imageTable.SetRows(m.buildImageRows())
+
+	cols := m.imageTable.Columns()
+	sizeX := cols[0].Width + 2 + cols[1].Width + 2 + cols[2].Width + 2 + 1
+	m, _ = m.handleTableClick(0, sizeX)
+	assert.Equal(t, []string{"ccc", "aaa", "bbb"}, imageIDs(m))
+	assert.Equal(t, "Size ▲", m.imageTable.Columns()[3].Title)
+
+	m, _ = m.handleTableClick(0, sizeX)
+	assert.Equal(t, []string{"bbb", "aaa", "ccc"}, imageIDs(m), "a second click reverses")
+
+	m, _ = m.handleTableClick(0, 1)
+	assert.Equal(t, []string{"aaa", "bbb", "ccc"}, imageIDs(m), "another column starts ascending")
+}
+
+func TestHeaderClick_subtractsContentLeftOffset(t *testing.T) {
+	orig := currentTheme.AppStyle
+	currentTheme.AppStyle = orig.PaddingLeft(3)
+	t.Cleanup(func() { currentTheme.AppStyle = orig })
+
+	m := sortTestModel(t, ImagesView)
+	m.images = []controller.Image{
+		{ID: "aaa", Repository: "nginx", Tag: "latest"},
+		{ID: "bbb", Repository: "nginx", Tag: "alpine"},
+	}
+	m.imageTable.SetRows(m.buildImageRows())
+
+	cols := m.imageTable.Columns()
+	lastTagX := cols[0].Width + 2 + cols[1].Width + 2 + cols[2].Width + 2 - 1
+	headerY := lipgloss.Height(currentTheme.HeaderStyle.Render(m.headerText())) + 1
+	m, _ = m.handleLeftClick(tea.MouseClickMsg{Button: tea.MouseLeft, X: contentLeft() + lastTagX, Y: headerY})
+	assert.Equal(t, "Tag ▲", m.imageTable.Columns()[2].Title, "the click lands on the column drawn under it")
+	assert.Equal(t, []string{"bbb", "aaa"}, imageIDs(m))
+}
+
+func TestTableSort_persistedPerView(t *testing.T) {
+	m := sortTestModel(t, VolumesView)
+	m = typeKeys(t, m, "oO")
+
+	state := loadState()
+	assert.Equal(t, map[string]tableSort{"volumes": {Column: 0, Desc: true}, "top": {Column: 2, Desc: true}}, state.TableSorts)
+
+	restored := InitialModel()
+	restored.syncTableSizes(120, 20)
+	assert.Equal(t, "Name ▼", restored.volumeTable.Columns()[0].Title)
+	assert.Equal(t, "ID", restored.imageTable.Columns()[0].Title, "other views keep their own order")
+}
+
+func TestContainerSort_withinGroups(t *testing.T) {
+	m := sortTestModel(t, ContainersView)
+	m.containers = []controller.Container{
+		makeContainer("1", "shop-web", "nginx", "running", "shop"),
+		makeContainer("2", "zeta", "redis", "running", ""),
+		makeContainer("3", "blog-db", "postgres", "running", "blog"),
+		makeContainer("4", "shop-api", "api", "running", "shop"),
+		makeContainer("5", "alpha", "busybox", "exited", ""),
+		makeContainer("6", "shop-db", "postgres", "running", "shop"),
+	}
+	m.containerStats = map[string]controller.ContainerStat{
+		"1": {CPUPercent: 2},
+		"2": {CPUPercent: 40},
+		"3": {CPUPercent: 5},
+		"4": {CPUPercent: 30},
+		"6": {CPUPercent: 1},
+	}
+	m.setTableSort(tableSort{Column: 5, Desc: true}, true)
+
+	var names []string
+	for _, row := range m.rows {
+		names = append(names, row.Name)
+	}
+	assert.Equal(t, []string{
+		"shop", "shop-api", "shop-web", "shop-db",
+		"blog", "blog-db",
+		"zeta", "alpha",
+	}, names, "groups keep their order; containers sort inside them")
+	assert.Contains(t, m.renderContainerHeader(), "CPU% ▼")
+}
+
+func TestComposeOpsSort_selectionFollowsRows(t *testing.T) {
+	m := sortTestModel(t, ComposeOpsView)
+	start := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	m.composeOps = []composeOp{
+		{id: 1, target: controller.ComposeTarget{Project: "shop"}, command: "up -d", started: start, ended: start.Add(time.Minute)},
+		{id: 2, target: controller.ComposeTarget{Project: "blog"}, command: "pull", started: start.Add(time.Hour), ended: start.Add(time.Hour + time.Second)},
+		{id: 3, target: controller.ComposeTarget{Project: "shop"}, command: "down", started: start.Add(2 * time.Hour)},
+	}
+	m.refreshComposeOps()
+
+	m.setTableSort(tableSort{Column: 3, Desc: true}, true)
+	m.composeOpsTable.SetCursor(0)
+
+	op, ok := m.selectedComposeOp()
+	require.True(t, ok)
+	assert.Equal(t, 1, op.id, "the longest operation is listed first")
+	assert.Equal(t, "up -d", m.composeOpsTable.Rows()[0][1])
+}
+
+func TestChangesView_sortKeysAndCopyOut(t *testing.T) {
+	m := sortTestModel(t, ChangesView)
+	m.changes = []controller.FileChange{{Kind: "A", Path: "/b"}, {Kind: "C", Path: "/a"}}
+	m.changesTable.SetRows(m.buildChangeRows())
+
+	m = typeKeys(t, m, "oo")
+	assert.Equal(t, "/a", m.changesTable.Rows()[0][1], "o cycles to the path column")
+	assert.Nil(t, m.form)
+
+	result, _ := updateModel(t, m, tea.KeyPressMsg{Code: 'e', Text: "e"})
+	assert.NotNil(t, result.form, "e copies the selected path out")
+}
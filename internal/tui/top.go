@@ -0,0 +1,109 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"charm.land/bubbles/v2/table"
+	tea "charm.land/bubbletea/v2"
+	"github.com/rluders/berth/internal/controller"
+)
+
+// topSignals are the signals offered for a selected process, keyed by the
+// quick menu shortcut.
+var topSignals = []struct{ key, signal, label string }{
+	{"t", "TERM", "SIGTERM  graceful stop"},
+	{"i", "INT", "SIGINT   interrupt"},
+	{"h", "HUP", "SIGHUP   reload"},
+	{"1", "USR1", "SIGUSR1"},
+	{"2", "USR2", "SIGUSR2"},
+	{"9", "KILL", "SIGKILL  force kill"},
+}
+
+// openTopView pushes the process list view and fetches the first listing.
+func (m Model) openTopView(id, name string) (Model, tea.Cmd) {
+	if c, ok := m.findContainer(id); ok && c.State != "running" {
+		m.statusMessage = "Container must be running to list processes"
+		return m, nil
+	}
+	m.pushView(TopView)
+	m.currentTopID = id
+	m.currentTopName = name
+	m.processes = nil
+	m.topFilter.SetValue("")
+	m.topTable.SetRows(nil)
+	m.topTable.GotoTop()
+	m.statusMessage = fmt.Sprintf("docker top %s", name)
+	m.showSpinner = true
+	return m, tea.Batch(fetchTopCmd(id), m.spinner.Tick)
+}
+
+// buildTopRows produces the filtered, sorted process rows.
+func (m Model) buildTopRows() []table.Row {
+	filter := strings.ToLower(m.topFilter.Value())
+	procs := make([]controller.Process, 0, len(m.processes))
+	for _, p := range m.processes {
+		if filter != "" && !strings.Contains(strings.ToLower(p.PID+" "+p.User+" "+p.Command), filter) {
+			continue
+		}
+		procs = append(procs, p)
+	}
+	if s, ok := m.tableSortOf(TopView); ok {
+		sortItems(procs, s, compareProcesses)
+	}
+
+	rows := make([]table.Row, len(procs))
+	for i, p := range procs {
+		rows[i] = table.Row{p.PID, p.User, formatPercent(p.CPU), formatPercent(p.Memory), p.Command}
+	}
+	return rows
+}
+
+// canSignalProcess reports whether a listed process can be signalled; tests
+// replace it.
+var canSignalProcess = controller.CanSignalProcess
+
+// formatPercent renders a ps percentage, or "-" when it was not reported.
+func formatPercent(v float64) string {
+	if v < 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f", v)
+}
+
+// refreshTopTable rebuilds the process rows, keeping the selected PID.
+func (m *Model) refreshTopTable() {
+	selected := ""
+	if row := m.topTable.SelectedRow(); len(row) > 0 {
+		selected = row[0]
+	}
+	rows := m.buildTopRows()
+	m.topTable.SetColumns(m.sortedColumns(TopView, m.topTable.Width()))
+	m.topTable.SetRows(rows)
+	for i, row := range rows {
+		if row[0] == selected {
+			m.topTable.SetCursor(i)
+			return
+		}
+	}
+	if m.topTable.Cursor() >= len(rows) {
+		m.topTable.GotoTop()
+	}
+}
+
+// newSignalMenu offers signals to send to a process in the container.
+func newSignalMenu(id, pid, command string) *QuickMenu {
+	menu := &QuickMenu{Title: fmt.Sprintf("Signal  %s  %s", pid, command)}
+	for _, s := range topSignals {
+		signal := s.signal
+		menu.Items = append(menu.Items, QuickMenuItem{
+			Label: s.label,
+			Key:   s.key,
+			Action: func(m Model) (Model, tea.Cmd) {
+				m.statusMessage = fmt.Sprintf("kill -s %s %s", signal, pid)
+				return m, signalProcessCmd(id, pid, signal)
+			},
+		})
+	}
+	return menu
+}
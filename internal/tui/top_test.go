@@ -0,0 +1,146 @@
+package tui
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/rluders/berth/internal/controller"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func topTestModel() Model {
+	m := InitialModel()
+	m.topTable.SetHeight(10)
+	m.pushView(TopView)
+	m.currentTopID = "abc"
+	m.currentTopName = "web"
+	m.processes = []controller.Process{
+		{PID: "10", User: "root", CPU: 0.5, Memory: 1.0, Command: "nginx: master"},
+		{PID: "9", User: "www", CPU: 12.0, Memory: 0.2, Command: "nginx: worker"},
+		{PID: "100", User: "www", CPU: 3.0, Memory: 4.0, Command: "php-fpm"},
+	}
+	return m
+}
+
+func topPIDs(m Model) []string {
+	var pids []string
+	for _, row := range m.topTable.Rows() {
+		pids = append(pids, row[0])
+	}
+	return pids
+}
+
+func TestBuildTopRows_defaultSortsByCPUDescending(t *testing.T) {
+	m := topTestModel()
+	m.refreshTopTable()
+
+	assert.Equal(t, []string{"9", "100", "10"}, topPIDs(m))
+	assert.Equal(t, "12.0", m.topTable.Rows()[0][2])
+}
+
+func TestBuildTopRows_sortsPIDsNumerically(t *testing.T) {
+	m := topTestModel()
+	m.tableSorts["top"] = tableSort{Column: 0}
+	m.refreshTopTable()
+
+	assert.Equal(t, []string{"9", "10", "100"}, topPIDs(m))
+}
+
+func TestBuildTopRows_filtersByCommandAndUser(t *testing.T) {
+	m := topTestModel()
+	m.topFilter.SetValue("nginx")
+	m.refreshTopTable()
+	assert.Equal(t, []string{"9", "10"}, topPIDs(m))
+
+	m.topFilter.SetValue("root")
+	m.refreshTopTable()
+	assert.Equal(t, []string{"10"}, topPIDs(m))
+}
+
+func TestFormatPercent_unreported(t *testing.T) {
+	assert.Equal(t, "-", formatPercent(-1))
+	assert.Equal(t, "0.0", formatPercent(0))
+}
+
+func TestHandleTopKey_sortCyclesColumnAndMarksHeader(t *testing.T) {
+	m := topTestModel()
+	m.refreshTopTable()
+
+	result, _ := updateModel(t, m, tea.KeyPressMsg{Code: 'o', Text: "o"})
+
+	assert.Equal(t, tableSort{Column: 3, Desc: true}, result.tableSorts["top"])
+	assert.Equal(t, "MEM% ▼", result.topTable.Columns()[3].Title)
+	assert.Equal(t, []string{"100", "10", "9"}, topPIDs(result))
+}
+
+func TestHandleTopMsg_keepsSelectedProcess(t *testing.T) {
+	m := topTestModel()
+	m.refreshTopTable()
+	m.topTable.SetCursor(1) // PID 100
+
+	procs := append([]controller.Process{{PID: "200", CPU: 50, Command: "busy"}}, m.processes...)
+	result, _ := updateModel(t, m, topMsg{id: "abc", processes: procs})
+
+	require.NotEmpty(t, result.topTable.SelectedRow())
+	assert.Equal(t, "100", result.topTable.SelectedRow()[0])
+}
+
+func TestHandleTopMsg_ignoresOtherContainer(t *testing.T) {
+	m := topTestModel()
+
+	result, _ := updateModel(t, m, topMsg{id: "other", processes: nil})
+
+	assert.Len(t, result.processes, 3)
+}
+
+func TestHandleStatsTickMsg_refreshesTopView(t *testing.T) {
+	m := topTestModel()
+
+	_, cmd := updateModel(t, m, statsTickMsg{})
+
+	assert.NotNil(t, cmd)
+}
+
+// useCanSignal stubs the check for the engine host's /proc.
+func useCanSignal(t *testing.T, ok bool) {
+	t.Helper()
+	orig := canSignalProcess
+	canSignalProcess = func(string) bool { return ok }
+	t.Cleanup(func() { canSignalProcess = orig })
+}
+
+func TestHandleTopKey_signalOpensMenuForSelectedProcess(t *testing.T) {
+	useCanSignal(t, true)
+	m := topTestModel()
+	m.refreshTopTable()
+
+	result, _ := updateModel(t, m, tea.KeyPressMsg{Code: 'K', Text: "K"})
+
+	require.NotNil(t, result.quickMenu)
+	assert.Contains(t, result.quickMenu.Title, "9")
+	assert.Len(t, result.quickMenu.Items, len(topSignals))
+}
+
+func TestHandleTopKey_signalNeedsContainerPID(t *testing.T) {
+	useCanSignal(t, false)
+	m := topTestModel()
+	m.refreshTopTable()
+
+	result, _ := updateModel(t, m, tea.KeyPressMsg{Code: 'K', Text: "K"})
+
+	assert.Nil(t, result.quickMenu)
+	assert.Contains(t, result.statusMessage, "/proc")
+}
+
+func TestTopFilter_separateFromContainersFilter(t *testing.T) {
+	m := topTestModel()
+	m.filterInput.SetValue("web")
+
+	m, _ = m.openTopView("abc", "web")
+	m = typeKeys(t, m, "/php")
+	m, _ = updateModel(t, m, tea.KeyPressMsg{Code: tea.KeyEnter})
+
+	assert.Equal(t, "php", m.topFilter.Value())
+	assert.Equal(t, "web", m.filterInput.Value(), "the containers filter is left alone")
+}
@@ -16,6 +16,13 @@ const (
 	InspectView
 	LogsView
 	DetailsView
+	ChangesView
+	ExecOutputView
+	TopView
+	StatsView
+	ComposeOpsView
+	ComposeOutputView
+	DriftView
 )
 
 // progressMsg drives the progress bar for long operations.
@@ -25,6 +32,17 @@ type progressMsg struct {
 	done    bool
 }
 
+// logChunkMsg carries the entries read from the log channel ch in one go.
+type logChunkMsg struct {
+	ch      <-chan controller.LogEntry
+	entries []controller.LogEntry
+}
+
+// logStreamDoneMsg reports that the log channel ch was closed.
+type logStreamDoneMsg struct {
+	ch <-chan controller.LogEntry
+}
+
 // progressTickMsg animates the progress bar while an operation runs.
 type progressTickMsg struct{}
 
@@ -35,27 +53,82 @@ type (
 	volumeListMsg     []controller.Volume
 	networkListMsg    []controller.Network
 	systemInfoMsg     controller.SystemInfo
-	logChunkMsg       string
-	logStreamDoneMsg  struct{}
 	inspectMsg        string
 	detailsMsg        controller.ContainerDetails
+	changesMsg        []controller.FileChange
 	containerStatsMsg map[string]controller.ContainerStat
+	restartCountsMsg  map[string]int
 	statsTickMsg      struct{}
 	refreshTickMsg    struct{}
+	logRenderMsg      struct{}
 	statusMsg         string
 	errMsg            struct{ err error }
 
+	// topMsg carries a container's process list for the top view.
+	topMsg struct {
+		id        string
+		processes []controller.Process
+	}
+
+	// alertHookMsg reports the result of an alert hook command.
+	alertHookMsg struct {
+		name string
+		err  error
+	}
+
+	// execShellDetectedMsg carries the shell found in a container for the exec dialog.
+	execShellDetectedMsg struct {
+		id    string
+		name  string
+		image string
+		shell string
+	}
+
+	// execOutputMsg carries one line of output from a running one-off command.
+	execOutputMsg struct {
+		line controller.ExecLine
+		ch   <-chan controller.ExecLine
+		done <-chan execDoneMsg
+	}
+	// execDoneMsg signals a one-off command exited (or failed to start).
+	execDoneMsg struct {
+		code int
+		err  error
+		ch   <-chan controller.ExecLine
+	}
+
 	// composeOutputMsg carries one streamed line from an ongoing compose operation.
 	composeOutputMsg struct {
+		op      int // history id of the operation
 		project string
 		line    string
-		ch      <-chan string
+		ch      <-chan controller.ComposeLine
 	}
 	// composeDoneMsg signals a compose operation completed (with or without error).
 	composeDoneMsg struct {
+		op      int
 		project string
 		err     error
 	}
+	// composeStartMsg starts a compose operation once it was confirmed.
+	composeStartMsg struct {
+		target  controller.ComposeTarget
+		command string
+		run     composeRunFunc
+	}
+	// composeDriftMsg carries the drift of a compose project from its files.
+	composeDriftMsg struct {
+		project string
+		drift   []controller.ServiceDrift
+		err     error
+	}
+	// composeProjectsMsg carries the compose projects read from disk, and
+	// the remembered projects whose files are gone.
+	composeProjectsMsg struct {
+		projects []controller.ComposeProject
+		gone     []string
+		err      error
+	}
 )
 
 func (e errMsg) Error() string { return e.err.Error() }
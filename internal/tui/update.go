@@ -26,6 +26,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case containerListMsg:
 		return m.handleContainerListMsg(msg)
 
+	case composeProjectsMsg:
+		return m.handleComposeProjectsMsg(msg)
+
 	case imageListMsg:
 		return m.handleImageListMsg(msg)
 
@@ -41,6 +44,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case containerStatsMsg:
 		return m.handleContainerStatsMsg(msg)
 
+	case restartCountsMsg:
+		return m.handleRestartCountsMsg(msg)
+
+	case alertHookMsg:
+		return m.handleAlertHookMsg(msg)
+
 	case statsTickMsg:
 		return m.handleStatsTickMsg()
 
@@ -53,11 +62,29 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case detailsMsg:
 		return m.handleDetailsMsg(msg)
 
+	case changesMsg:
+		return m.handleChangesMsg(msg)
+
+	case topMsg:
+		return m.handleTopMsg(msg)
+
+	case execShellDetectedMsg:
+		return m.handleExecShellDetectedMsg(msg)
+
+	case execOutputMsg:
+		return m.handleExecOutputMsg(msg)
+
+	case execDoneMsg:
+		return m.handleExecDoneMsg(msg)
+
 	case logChunkMsg:
 		return m.handleLogChunkMsg(msg)
 
 	case logStreamDoneMsg:
-		return m.handleLogStreamDoneMsg()
+		return m.handleLogStreamDoneMsg(msg)
+
+	case logRenderMsg:
+		return m.handleLogRenderMsg()
 
 	case progressMsg:
 		return m.handleProgressMsg(msg)
@@ -74,9 +101,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case composeOutputMsg:
 		return m.handleComposeOutputMsg(msg)
 
+	case composeStartMsg:
+		return m.handleComposeStartMsg(msg)
+
 	case composeDoneMsg:
 		return m.handleComposeDoneMsg(msg)
 
+	case composeDriftMsg:
+		return m.handleComposeDriftMsg(msg)
+
 	case errMsg:
 		return m.handleErrMsg(msg)
 	}
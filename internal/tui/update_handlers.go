@@ -1,14 +1,17 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
+	"os"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 
 	"charm.land/bubbles/v2/progress"
-	"charm.land/bubbles/v2/table"
 	tea "charm.land/bubbletea/v2"
-	"charm.land/lipgloss/v2"
 	"github.com/rluders/berth/internal/controller"
 )
 
@@ -29,6 +32,12 @@ func (m Model) handleWindowSizeMsg(msg tea.WindowSizeMsg) (Model, tea.Cmd) {
 	m.logViewPort.SetHeight(contentH)
 	m.detailsViewPort.SetWidth(viewW)
 	m.detailsViewPort.SetHeight(contentH)
+	m.statsViewPort.SetWidth(viewW)
+	m.statsViewPort.SetHeight(contentH)
+	m.execOutputVP.SetWidth(viewW)
+	m.execOutputVP.SetHeight(max(contentH-2, 0)) // title and status lines
+	m.composeOutputVP.SetWidth(viewW)
+	m.composeOutputVP.SetHeight(max(contentH-2, 0))
 
 	m.syncContainerViewport()
 
@@ -37,7 +46,7 @@ func (m Model) handleWindowSizeMsg(msg tea.WindowSizeMsg) (Model, tea.Cmd) {
 		m.inspectReady = true
 	}
 	if m.currentView == DetailsView && !m.detailsReady && m.currentDetails.ID != "" {
-		m.detailsViewPort.SetContent(renderDetailsContent(m.currentDetails))
+		m.refreshDetails()
 		m.detailsReady = true
 	}
 
@@ -62,23 +71,108 @@ func (m *Model) syncTableSizes(width, contentH int) {
 
 	m.imageTable.SetWidth(width)
 	m.imageTable.SetHeight(contentH)
-	m.imageTable.SetColumns(tableColumns(width, imageCols))
+	m.imageTable.SetColumns(m.sortedColumns(ImagesView, width))
 
 	m.volumeTable.SetWidth(width)
 	m.volumeTable.SetHeight(contentH)
-	m.volumeTable.SetColumns(tableColumns(width, volumeCols))
+	m.volumeTable.SetColumns(m.sortedColumns(VolumesView, width))
 
 	m.networkTable.SetWidth(width)
 	m.networkTable.SetHeight(contentH)
-	m.networkTable.SetColumns(tableColumns(width, networkCols))
+	m.networkTable.SetColumns(m.sortedColumns(NetworksView, width))
+
+	m.changesTable.SetWidth(width)
+	m.changesTable.SetHeight(contentH)
+	m.changesTable.SetColumns(m.sortedColumns(ChangesView, width))
+
+	m.topTable.SetWidth(width)
+	m.topTable.SetHeight(contentH)
+	m.topTable.SetColumns(m.sortedColumns(TopView, width))
+
+	m.composeOpsTable.SetWidth(width)
+	m.composeOpsTable.SetHeight(contentH)
+	m.composeOpsTable.SetColumns(m.sortedColumns(ComposeOpsView, width))
+
+	m.driftTable.SetWidth(width)
+	m.driftTable.SetHeight(contentH)
+	m.driftTable.SetColumns(m.sortedColumns(DriftView, width))
 }
 
 func (m Model) handleContainerListMsg(msg containerListMsg) (Model, tea.Cmd) {
 	slog.Debug("containerListMsg", "count", len(msg))
+	prev := m.containers
 	m.containers = []controller.Container(msg)
+	m.forgetRestartCounts()
 	m.recomputeRows()
 	m.showSpinner = false
 	m.statusMessage = ""
+	m, alertCmd := m.updateAlerts()
+	cmds := []tea.Cmd{alertCmd, m.discoverCompose()}
+	if ids := m.restartWatchIDs(prev); len(ids) > 0 {
+		cmds = append(cmds, fetchRestartCountsCmd(ids))
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// discoverCompose records the compose projects in the containers' labels and
+// returns the command reading them, the configured project directories and
+// the current directory from disk.
+func (m *Model) discoverCompose() tea.Cmd {
+	changed := false
+	for _, t := range controller.ComposeTargetsFromLabels(m.containers) {
+		if prev, ok := m.composeTargets[t.Project]; !ok || !slices.Equal(prev.Files, t.Files) || !slices.Equal(prev.EnvFiles, t.EnvFiles) || prev.WorkDir != t.WorkDir {
+			m.composeTargets[t.Project] = t
+			changed = true
+		}
+	}
+	if changed {
+		m.persistState()
+	}
+
+	targets := make([]controller.ComposeTarget, 0, len(m.composeTargets))
+	for _, t := range m.composeTargets {
+		targets = append(targets, t)
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i].Project < targets[j].Project })
+	dirs := slices.Clone(m.config.Compose.ProjectDirs)
+	if wd, err := os.Getwd(); err == nil {
+		dirs = append(dirs, wd)
+	}
+	return discoverComposeCmd(targets, dirs)
+}
+
+func (m Model) handleComposeProjectsMsg(msg composeProjectsMsg) (Model, tea.Cmd) {
+	// Discovery runs on every refresh; report each new error once.
+	switch {
+	case msg.err == nil:
+		m.composeErr = ""
+	case msg.err.Error() != m.composeErr:
+		m.composeErr = msg.err.Error()
+		m.statusMessage = "Compose: " + strings.ReplaceAll(m.composeErr, "\n", "; ")
+	}
+
+	// Forget remembered projects whose files are gone, unless containers
+	// still carry their labels.
+	labelled := map[string]bool{}
+	for _, t := range controller.ComposeTargetsFromLabels(m.containers) {
+		labelled[t.Project] = true
+	}
+	pruned := false
+	for _, project := range msg.gone {
+		if _, ok := m.composeTargets[project]; ok && !labelled[project] {
+			delete(m.composeTargets, project)
+			pruned = true
+		}
+	}
+	if pruned {
+		m.persistState()
+	}
+
+	m.composeProjects = make(map[string]controller.ComposeProject, len(msg.projects))
+	for _, p := range msg.projects {
+		m.composeProjects[p.Project] = p
+	}
+	m.recomputeRows()
 	return m, nil
 }
 
@@ -102,11 +196,8 @@ func (m Model) handleVolumeListMsg(msg volumeListMsg) (Model, tea.Cmd) {
 
 func (m Model) handleNetworkListMsg(msg networkListMsg) (Model, tea.Cmd) {
 	slog.Debug("networkListMsg", "count", len(msg))
-	rows := make([]table.Row, len(msg))
-	for i, net := range msg {
-		rows[i] = table.Row{net.ID, net.Name, net.Driver, net.Scope}
-	}
-	m.networkTable.SetRows(rows)
+	m.networks = []controller.Network(msg)
+	m.networkTable.SetRows(m.buildNetworkRows())
 	m.showSpinner = false
 	m.statusMessage = ""
 	return m, nil
@@ -135,6 +226,25 @@ func (m Model) handleContainerStatsMsg(msg containerStatsMsg) (Model, tea.Cmd) {
 		}
 	}
 	m.recomputeRows()
+
+	switch {
+	case m.currentView == StatsView:
+		m.statsViewPort.SetContent(m.renderStatsContent())
+	case m.currentView == DetailsView && m.detailsReady:
+		m.refreshDetails()
+	}
+	return m.updateAlerts()
+}
+
+func (m Model) handleRestartCountsMsg(msg restartCountsMsg) (Model, tea.Cmd) {
+	m.recordRestartCounts(msg)
+	return m.updateAlerts()
+}
+
+func (m Model) handleAlertHookMsg(msg alertHookMsg) (Model, tea.Cmd) {
+	if msg.err != nil {
+		m.statusMessage = fmt.Sprintf("Alert hook for %s failed: %v", msg.name, msg.err)
+	}
 	return m, nil
 }
 
@@ -145,9 +255,10 @@ func (m Model) handleStatsTickMsg() (Model, tea.Cmd) {
 			ids = append(ids, c.ID)
 		}
 	}
-	var cmds []tea.Cmd
-	if len(ids) > 0 {
-		cmds = append(cmds, fetchStatsCmd(ids))
+	// Sync even with no running containers so stopped ones release their streams.
+	cmds := []tea.Cmd{syncStatsCmd(m.statsMonitor, ids)}
+	if m.currentView == TopView {
+		cmds = append(cmds, fetchTopCmd(m.currentTopID))
 	}
 	cmds = append(cmds, statsTickCmd())
 	return m, tea.Batch(cmds...)
@@ -175,7 +286,7 @@ func (m Model) handleDetailsMsg(msg detailsMsg) (Model, tea.Cmd) {
 	m.statusMessage = ""
 	m.currentDetails = controller.ContainerDetails(msg)
 	if m.width > 0 {
-		m.detailsViewPort.SetContent(renderDetailsContent(m.currentDetails))
+		m.refreshDetails()
 		m.detailsReady = true
 	}
 	return m, func() tea.Msg {
@@ -183,22 +294,74 @@ func (m Model) handleDetailsMsg(msg detailsMsg) (Model, tea.Cmd) {
 	}
 }
 
+func (m Model) handleChangesMsg(msg changesMsg) (Model, tea.Cmd) {
+	m.showSpinner = false
+	m.changes = []controller.FileChange(msg)
+	m.changesTable.SetRows(m.buildChangeRows())
+	m.changesTable.GotoTop()
+	m.statusMessage = fmt.Sprintf("%d change(s) in %s", len(m.changes), m.currentChangesID)
+	return m, nil
+}
+
+func (m Model) handleTopMsg(msg topMsg) (Model, tea.Cmd) {
+	if msg.id != m.currentTopID {
+		return m, nil
+	}
+	m.showSpinner = false
+	if m.processes == nil {
+		m.statusMessage = fmt.Sprintf("%d process(es) in %s", len(msg.processes), m.currentTopName)
+	}
+	m.processes = msg.processes
+	m.refreshTopTable()
+	return m, nil
+}
+
 func (m Model) handleLogChunkMsg(msg logChunkMsg) (Model, tea.Cmd) {
-	m.logLines = append(m.logLines, string(msg))
-	if len(m.logLines) > 10000 {
-		m.logLines = m.logLines[len(m.logLines)-5000:]
+	if msg.ch != m.logCh {
+		// Left over from a stream that was reloaded or closed.
+		return m, nil
 	}
-	m.logViewPort.SetContent(buildColorizedLogContent(m.logLines, m.showLineNumbers))
-	if m.logFollowing {
-		m.logViewPort.GotoBottom()
+	for _, e := range msg.entries {
+		switch {
+		case e.Waiting:
+			m.statusMessage = logWaitingStatus(e.ContainerName)
+			continue
+		case e.Marker && m.statusMessage == logWaitingStatus(e.ContainerName):
+			m.statusMessage = ""
+		}
+		m.logLines.Push(newLogLine(e))
 	}
+	// Chunks arriving within a frame share one render.
+	var cmds []tea.Cmd
 	if m.logCh != nil {
-		return m, waitForLogLineCmd(m.logCh)
+		cmds = append(cmds, waitForLogChunkCmd(m.logCh))
+	}
+	if !m.logRenderPending {
+		m.logRenderPending = true
+		cmds = append(cmds, logRenderTickCmd())
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// logWaitingStatus is the status shown while a followed container, named in
+// group streams, is awaited to run again.
+func logWaitingStatus(name string) string {
+	if name == "" {
+		return "Waiting for container to restart..."
 	}
+	return fmt.Sprintf("Waiting for %s to restart...", name)
+}
+
+func (m Model) handleLogRenderMsg() (Model, tea.Cmd) {
+	m.logRenderPending = false
+	m.updateLogView()
 	return m, nil
 }
 
-func (m Model) handleLogStreamDoneMsg() (Model, tea.Cmd) {
+func (m Model) handleLogStreamDoneMsg(msg logStreamDoneMsg) (Model, tea.Cmd) {
+	if msg.ch != m.logCh {
+		return m, nil
+	}
 	m.logCh = nil
 	m.logCancel = nil
 	return m, nil
@@ -247,25 +410,73 @@ func (m Model) handleStatusMsg(msg statusMsg) (Model, tea.Cmd) {
 }
 
 func (m Model) handleComposeOutputMsg(msg composeOutputMsg) (Model, tea.Cmd) {
-	m.composeOutput = append(m.composeOutput, msg.line)
-	if len(m.composeOutput) > 200 {
-		m.composeOutput = m.composeOutput[1:]
+	if op := m.findComposeOp(msg.op); op != nil {
+		op.output = append(op.output, msg.line)
+		if len(op.output) > composeOpMaxLines {
+			op.output = op.output[len(op.output)-composeOpMaxLines:]
+		}
+		if m.currentView == ComposeOutputView && m.composeOutputOp == msg.op {
+			m.refreshComposeOutput()
+		}
+	}
+	if msg.op == m.composeRunningOp {
+		m.statusMessage = msg.line
 	}
-	m.statusMessage = msg.line
-	return m, readNextComposeLineCmd(msg.ch, msg.project)
+	return m, readNextComposeLineCmd(msg.ch, msg.op, msg.project)
 }
 
 func (m Model) handleComposeDoneMsg(msg composeDoneMsg) (Model, tea.Cmd) {
+	if op := m.findComposeOp(msg.op); op != nil {
+		op.ended = time.Now()
+		op.err = msg.err
+		m.refreshComposeOps()
+		if m.currentView == ComposeOutputView && m.composeOutputOp == msg.op {
+			m.refreshComposeOutput()
+		}
+	}
+	if msg.op != m.composeRunningOp {
+		// A superseded operation winding down.
+		return m, nil
+	}
 	m.showSpinner = false
 	m.composeCancel = nil
 	if msg.err != nil {
 		m.statusMessage = fmt.Sprintf("[%s] compose failed: %v", msg.project, msg.err)
 	} else {
 		m.statusMessage = fmt.Sprintf("[%s] compose done.", msg.project)
+		if m.currentView == DriftView && msg.project == m.driftProject {
+			m, cmd := m.refreshDrift()
+			return m, tea.Batch(cmd, fetchContainersCmd())
+		}
 	}
 	return m, fetchContainersCmd()
 }
 
+func (m Model) handleComposeDriftMsg(msg composeDriftMsg) (Model, tea.Cmd) {
+	if msg.project != m.driftProject {
+		return m, nil
+	}
+	m.showSpinner = false
+	if errors.Is(msg.err, controller.ErrNoJSONConfig) {
+		m.statusMessage = fmt.Sprintf("Drift detection skipped: %s cannot print the project's configuration as JSON.", controller.ComposeToolName())
+		return m, nil
+	}
+	if msg.err != nil {
+		m.statusMessage = fmt.Sprintf("Drift of %s: %v", msg.project, msg.err)
+		return m, nil
+	}
+	m.drift = msg.drift
+	m.refreshDriftRows()
+	m.statusMessage = driftSummary(msg.project, msg.drift)
+	return m, nil
+}
+
+func (m Model) handleComposeStartMsg(msg composeStartMsg) (Model, tea.Cmd) {
+	m.statusMessage = fmt.Sprintf("%s %s  [%s]", controller.ComposeToolName(), msg.command, msg.target.Project)
+	cmd := m.startComposeOp(msg.target, msg.command, msg.run)
+	return m, tea.Batch(cmd, m.spinner.Tick)
+}
+
 func (m Model) handleErrMsg(msg errMsg) (Model, tea.Cmd) {
 	slog.Error("errMsg", "error", msg.err)
 	m.err = msg.err
@@ -273,99 +484,3 @@ func (m Model) handleErrMsg(msg errMsg) (Model, tea.Cmd) {
 	m.statusMessage = ""
 	return m, nil
 }
-
-// renderDetailsContent formats ContainerDetails into a card-based scrollable view.
-func renderDetailsContent(d controller.ContainerDetails) string {
-	th := currentTheme
-
-	field := func(label, value string) string {
-		l := th.CardTitleStyle.Render(fmt.Sprintf("%-12s", label))
-		v := th.CardValueStyle.Render(value)
-		return "  " + l + "  " + v
-	}
-
-	section := func(title string, lines []string) string {
-		header := th.SectionStyle.Render("▸ " + title)
-		body := strings.Join(lines, "\n")
-		return th.CardStyle.Render(header + "\n" + body)
-	}
-
-	// ── Container section ──────────────────────────────────────────────────
-	stateBadge := StatusBadge(d.State)
-	infoLines := []string{
-		field("ID", d.ID),
-		field("Name", d.Name),
-		field("Image", d.Image),
-		field("Command", d.Command),
-		field("State", stateBadge),
-		field("Created", d.Created),
-	}
-
-	// ── Environment section ────────────────────────────────────────────────
-	envLines := []string{}
-	if len(d.Env) == 0 {
-		envLines = append(envLines, "  "+th.CardValueStyle.Foreground(lipgloss.Color(colorMuted)).Render("(none)"))
-	} else {
-		for _, e := range d.Env {
-			parts := strings.SplitN(e, "=", 2)
-			if len(parts) == 2 {
-				masked := strings.Repeat("•", len(parts[1]))
-				if len(masked) > 12 {
-					masked = masked[:12]
-				}
-				envLines = append(envLines, field(parts[0], masked))
-			} else {
-				envLines = append(envLines, "  "+e)
-			}
-		}
-	}
-
-	// ── Ports section ──────────────────────────────────────────────────────
-	portLines := []string{}
-	if len(d.Ports) == 0 {
-		portLines = append(portLines, "  "+th.CardValueStyle.Foreground(lipgloss.Color(colorMuted)).Render("(none)"))
-	} else {
-		for _, p := range d.Ports {
-			hostIP := p.HostIP
-			if hostIP == "" {
-				hostIP = "0.0.0.0"
-			}
-			line := fmt.Sprintf("%s/%s → %s:%s", p.ContainerPort, p.Protocol, hostIP, p.HostPort)
-			portLines = append(portLines, "  "+th.CardValueStyle.Render(line))
-		}
-	}
-
-	// ── Mounts section ─────────────────────────────────────────────────────
-	mountLines := []string{}
-	if len(d.Mounts) == 0 {
-		mountLines = append(mountLines, "  "+th.CardValueStyle.Foreground(lipgloss.Color(colorMuted)).Render("(none)"))
-	} else {
-		for _, mt := range d.Mounts {
-			rw := th.LogDebugStyle.Render("ro")
-			if mt.RW {
-				rw = th.LogInfoStyle.Render("rw")
-			}
-			line := fmt.Sprintf("[%s] %s → %s (%s)", mt.Type, mt.Source, mt.Destination, rw)
-			mountLines = append(mountLines, "  "+th.CardValueStyle.Render(line))
-		}
-	}
-
-	// ── Networks section ───────────────────────────────────────────────────
-	netLines := []string{}
-	if len(d.Networks) == 0 {
-		netLines = append(netLines, "  "+th.CardValueStyle.Foreground(lipgloss.Color(colorMuted)).Render("(none)"))
-	} else {
-		for _, n := range d.Networks {
-			line := fmt.Sprintf("%s  IP: %s  GW: %s", n.Name, n.IPAddress, n.Gateway)
-			netLines = append(netLines, "  "+th.CardValueStyle.Render(line))
-		}
-	}
-
-	return strings.Join([]string{
-		section("Container", infoLines),
-		section("Environment", envLines),
-		section("Ports", portLines),
-		section("Mounts", mountLines),
-		section("Networks", netLines),
-	}, "\n")
-}
@@ -2,18 +2,24 @@ package tui
 
 import (
 	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"charm.land/bubbles/v2/table"
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/rluders/berth/internal/controller"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHandleContainerListMsg_setsContainers(t *testing.T) {
 	m := InitialModel()
+	m.config.Alerts.RestartCount = 0 // no restart count fetch
 	containers := []controller.Container{{ID: "abc123", Names: "test", State: "running"}}
 
 	result, cmd := updateModel(t, m, containerListMsg(containers))
@@ -22,7 +28,23 @@ func TestHandleContainerListMsg_setsContainers(t *testing.T) {
 	assert.Equal(t, "abc123", result.containers[0].ID)
 	assert.False(t, result.showSpinner)
 	assert.Empty(t, result.statusMessage)
-	assert.Nil(t, cmd)
+	require.NotNil(t, cmd)
+	assert.IsType(t, composeProjectsMsg{}, cmd(), "only compose discovery runs")
+}
+
+func TestHandleContainerListMsg_fetchesRestartCounts(t *testing.T) {
+	m := InitialModel()
+	m.config.Alerts.RestartCount = 3
+	containers := []controller.Container{
+		{ID: "abc123", Names: "web", State: "running"},
+		{ID: "def456", Names: "old", State: "exited"},
+	}
+
+	result, cmd := updateModel(t, m, containerListMsg(containers))
+
+	require.NotNil(t, cmd)
+	assert.Equal(t, []string{"abc123"}, result.restartWatchIDs(nil))
+	assert.Empty(t, result.restartWatchIDs(containers), "unchanged containers are not inspected again")
 }
 
 func TestHandleContainerListMsg_empty(t *testing.T) {
@@ -60,28 +82,30 @@ func TestHandleErrMsg_setsError(t *testing.T) {
 	assert.Nil(t, cmd)
 }
 
-func TestHandleComposeOutputMsg_appendsToBuffer(t *testing.T) {
+func TestHandleComposeOutputMsg_appendsToOperation(t *testing.T) {
 	m := InitialModel()
-	ch := make(chan string, 1)
+	m.composeOps = []composeOp{{id: 1, target: controller.ComposeTarget{Project: "myapp"}}}
+	m.composeRunningOp = 1
+	ch := make(chan controller.ComposeLine, 1)
 	close(ch)
 
-	result, _ := updateModel(t, m, composeOutputMsg{project: "myapp", line: "Pulling image...", ch: ch})
+	result, _ := updateModel(t, m, composeOutputMsg{op: 1, project: "myapp", line: "Pulling image...", ch: ch})
 
-	assert.Len(t, result.composeOutput, 1)
-	assert.Equal(t, "Pulling image...", result.composeOutput[0])
+	assert.Equal(t, []string{"Pulling image..."}, result.composeOps[0].output)
 	assert.Equal(t, "Pulling image...", result.statusMessage)
 }
 
-func TestHandleComposeOutputMsg_rollingBuffer(t *testing.T) {
+func TestHandleComposeOutputMsg_capsOutput(t *testing.T) {
 	m := InitialModel()
-	m.composeOutput = make([]string, 200)
-	ch := make(chan string, 1)
+	m.composeOps = []composeOp{{id: 1, output: make([]string, composeOpMaxLines)}}
+	ch := make(chan controller.ComposeLine, 1)
 	close(ch)
 
-	result, _ := updateModel(t, m, composeOutputMsg{project: "myapp", line: "new line", ch: ch})
+	result, _ := updateModel(t, m, composeOutputMsg{op: 1, project: "myapp", line: "new line", ch: ch})
 
-	assert.Len(t, result.composeOutput, 200, "buffer must not exceed 200 entries")
-	assert.Equal(t, "new line", result.composeOutput[199])
+	output := result.composeOps[0].output
+	assert.Len(t, output, composeOpMaxLines, "output must not exceed the cap")
+	assert.Equal(t, "new line", output[len(output)-1])
 }
 
 func TestHandleComposeDoneMsg_success(t *testing.T) {
@@ -191,23 +215,183 @@ func columnWidthByTitle(t *testing.T, cols []table.Column, title string) int {
 func TestHandleLogChunkMsg_appendsLine(t *testing.T) {
 	m := InitialModel()
 
-	result, cmd := updateModel(t, m, logChunkMsg("2024-01-01 INFO started"))
+	result, cmd := updateModel(t, m, logChunkMsg{entries: []controller.LogEntry{{Stream: controller.StreamStdout, Line: "2024-01-01 INFO started"}}})
 
-	assert.Len(t, result.logLines, 1)
-	assert.Equal(t, "2024-01-01 INFO started", result.logLines[0])
-	assert.Nil(t, cmd)
+	assert.Equal(t, 1, result.logLines.Len())
+	assert.Equal(t, "2024-01-01 INFO started", result.logLines.At(0).Line)
+	assert.True(t, result.logRenderPending)
+	assert.NotNil(t, cmd, "schedules a render")
+}
+
+func TestHandleLogChunkMsg_showsWaitingForRestart(t *testing.T) {
+	m := InitialModel()
+
+	m, _ = updateModel(t, m, logChunkMsg{entries: []controller.LogEntry{{Stream: controller.StreamStdout, Waiting: true}}})
+	assert.Equal(t, "Waiting for container to restart...", m.statusMessage)
+	assert.Equal(t, 0, m.logLines.Len(), "the waiting note is not a line")
+
+	m, _ = updateModel(t, m, logChunkMsg{entries: []controller.LogEntry{{Stream: controller.StreamStdout, Line: "--- container restarted at 10:05:00 ---", Marker: true}}})
+	assert.Empty(t, m.statusMessage)
+	assert.Equal(t, 1, m.logLines.Len())
 }
 
 func TestHandleLogStreamDoneMsg_clearsChannel(t *testing.T) {
 	m := InitialModel()
-	m.logCh = make(chan string)
+	m.logCh = make(chan controller.LogEntry)
 	cancelCalled := false
 	m.logCancel = func() { cancelCalled = true }
 
-	result, cmd := updateModel(t, m, logStreamDoneMsg{})
+	result, cmd := updateModel(t, m, logStreamDoneMsg{ch: m.logCh})
 
 	assert.Nil(t, result.logCh)
 	assert.Nil(t, result.logCancel)
 	assert.Nil(t, cmd)
 	_ = cancelCalled
 }
+
+func TestHandleChangesMsg_populatesTable(t *testing.T) {
+	m := InitialModel()
+	m.currentChangesID = "abc123"
+	m.showSpinner = true
+	changes := []controller.FileChange{
+		{Kind: "A", Path: "/etc/app.conf"},
+		{Kind: "D", Path: "/tmp/old"},
+	}
+
+	result, _ := updateModel(t, m, changesMsg(changes))
+
+	assert.False(t, result.showSpinner)
+	assert.Len(t, result.changesTable.Rows(), 2)
+	assert.Equal(t, table.Row{"added", "/etc/app.conf"}, result.changesTable.Rows()[0])
+	assert.Equal(t, table.Row{"deleted", "/tmp/old"}, result.changesTable.Rows()[1])
+	assert.Contains(t, result.statusMessage, "2 change(s)")
+}
+
+func TestRenderHealthLines(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.Local)
+	hc := &controller.HealthCheck{
+		Test:          []string{"CMD", "pg_isready"},
+		Interval:      10 * time.Second,
+		Retries:       3,
+		Status:        controller.HealthUnhealthy,
+		FailingStreak: 2,
+		Probes: []controller.HealthProbe{
+			{Start: start, End: start.Add(250 * time.Millisecond), ExitCode: 1, Output: "no response\nline 2\nline 3\nline 4"},
+			{Start: start.Add(-10 * time.Second), End: start.Add(-10 * time.Second), ExitCode: 0},
+		},
+	}
+
+	out := ansi.Strip(strings.Join(renderHealthLines(hc), "\n"))
+
+	assert.Contains(t, out, "✗ unhealthy  (failing streak 2)")
+	assert.Contains(t, out, "CMD pg_isready")
+	assert.Contains(t, out, "every 10s, timeout 30s, 3 retries")
+	assert.Contains(t, out, "10:00:00  exit 1  250ms")
+	assert.Contains(t, out, "no response")
+	assert.NotContains(t, out, "line 4")
+	assert.Contains(t, out, "exit 0")
+
+	assert.Contains(t, ansi.Strip(strings.Join(renderHealthLines(nil), "\n")), "(no health check)")
+
+	hc.Retries = 0
+	out = ansi.Strip(strings.Join(renderHealthLines(hc), "\n"))
+	assert.Contains(t, out, "3 retries", "unset retries show the engine default")
+}
+
+func TestHandleContainerListMsg_remembersComposeProjects(t *testing.T) {
+	useStateFile(t)
+	t.Setenv("HOME", t.TempDir())
+	m := InitialModel()
+	c := makeContainer("a", "web", "nginx", "running", "shop")
+	c.Labels["com.docker.compose.project.working_dir"] = "/src/shop"
+
+	result, _ := updateModel(t, m, containerListMsg([]controller.Container{c}))
+
+	want := controller.ComposeTarget{Project: "shop", WorkDir: "/src/shop"}
+	assert.Equal(t, want, result.composeTargets["shop"])
+	assert.Equal(t, want, loadState().ComposeProjects["shop"], "kept for when the containers are gone")
+}
+
+func TestHandleComposeProjectsMsg_setsProjects(t *testing.T) {
+	m := InitialModel()
+
+	result, _ := updateModel(t, m, composeProjectsMsg{projects: []controller.ComposeProject{{
+		ComposeTarget: controller.ComposeTarget{Project: "shop"},
+		Services:      []controller.ComposeService{{Name: "api"}},
+	}}})
+
+	require.Len(t, result.rows, 2)
+	assert.Equal(t, "shop", result.rows[0].GroupID)
+	assert.Equal(t, RowTypeGhost, result.rows[1].Type)
+}
+
+func TestHandleComposeProjectsMsg_reportsErrorOnce(t *testing.T) {
+	m := InitialModel()
+	msg := composeProjectsMsg{err: errors.New("failed to parse compose file /src/shop/compose.yaml")}
+
+	m, _ = updateModel(t, m, msg)
+	assert.Equal(t, "Compose: failed to parse compose file /src/shop/compose.yaml", m.statusMessage)
+
+	m.statusMessage = ""
+	m, _ = updateModel(t, m, msg)
+	assert.Empty(t, m.statusMessage, "the same error is not shown again")
+
+	m, _ = updateModel(t, m, composeProjectsMsg{})
+	m, _ = updateModel(t, m, msg)
+	assert.NotEmpty(t, m.statusMessage, "it is shown again once it was fixed and came back")
+}
+
+func TestHandleComposeProjectsMsg_forgetsGoneProjects(t *testing.T) {
+	useStateFile(t)
+	m := InitialModel()
+	m.containers = []controller.Container{makeContainer("a", "web", "nginx", "running", "shop")}
+	m.composeTargets = map[string]controller.ComposeTarget{
+		"shop": {Project: "shop", WorkDir: "/src/shop"},
+		"old":  {Project: "old", WorkDir: "/src/old"},
+	}
+
+	result, _ := updateModel(t, m, composeProjectsMsg{gone: []string{"shop", "old"}})
+
+	assert.Contains(t, result.composeTargets, "shop", "kept while its containers exist")
+	assert.NotContains(t, result.composeTargets, "old")
+	assert.NotContains(t, loadState().ComposeProjects, "old")
+}
+
+func TestDiscoverComposeCmd_reportsGoneTargets(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "compose.yaml"), []byte("name: shop\nservices:\n  api:\n    image: api\n"), 0o600))
+
+	msg := discoverComposeCmd([]controller.ComposeTarget{
+		{Project: "shop", WorkDir: dir},
+		{Project: "old", WorkDir: filepath.Join(dir, "missing")},
+	}, nil)().(composeProjectsMsg)
+
+	require.NoError(t, msg.err)
+	require.Len(t, msg.projects, 1)
+	assert.Equal(t, "shop", msg.projects[0].Project)
+	assert.Equal(t, []string{"old"}, msg.gone)
+}
+
+func TestHandleContainerListMsg_keepsConfigError(t *testing.T) {
+	m := InitialModel()
+	m.configErr = "failed to parse config: unexpected end of JSON input"
+	m.width, m.height = 100, 30
+
+	result, _ := updateModel(t, m, containerListMsg(nil))
+
+	assert.Equal(t, m.configErr, result.configErr)
+	assert.Contains(t, ansi.Strip(result.renderFooter()), "failed to parse config")
+}
+
+func TestContentHeight_leavesRoomForAlertsAndConfigError(t *testing.T) {
+	m := InitialModel()
+	m.height = 30
+	base := m.contentHeight()
+
+	m.containers = []controller.Container{{ID: "abc", Names: "web", State: "running"}}
+	m.alerts = map[string][]string{"abc": {"unhealthy"}}
+	assert.Equal(t, base-1, m.contentHeight())
+
+	m.configErr = "bad config"
+	assert.Equal(t, base-2, m.contentHeight())
+}
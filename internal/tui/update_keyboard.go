@@ -2,13 +2,15 @@ package tui
 
 import (
 	"bytes"
-	"context"
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"path"
 
 	"charm.land/bubbles/v2/key"
+	"charm.land/bubbles/v2/textinput"
 	tea "charm.land/bubbletea/v2"
+	"github.com/rluders/berth/internal/controller"
 )
 
 var mainTabs = []ViewType{ContainersView, ImagesView, VolumesView, NetworksView, SystemView}
@@ -22,6 +24,11 @@ func (m Model) handleKeyMsg(msg tea.KeyPressMsg) (Model, tea.Cmd) {
 		return m.handleQuickMenuKey(msg)
 	}
 
+	// Form overlay intercepts all keys.
+	if m.form != nil {
+		return m.handleFormKey(msg)
+	}
+
 	// Modal dialog intercepts all keys.
 	if m.modal != nil {
 		return m.handleModalKey(msg)
@@ -38,16 +45,25 @@ func (m Model) handleKeyMsg(msg tea.KeyPressMsg) (Model, tea.Cmd) {
 		return m.handleFilterKey(msg)
 	}
 
+	// Logs search/filter prompt intercepts typing when open.
+	if m.currentView == LogsView && m.logInputMode != logInputNone {
+		return m.handleLogInputKey(msg)
+	}
+
 	// Global keys.
 	switch {
 	case key.Matches(msg, Keys.Global.Quit):
-		return m, tea.Quit
+		return m.quit()
 	case key.Matches(msg, Keys.Global.Help):
 		m.showHelp = true
 		return m, nil
 	case key.Matches(msg, Keys.Global.Back):
 		switch m.currentView {
-		case InspectView, DetailsView:
+		case InspectView, DetailsView, ChangesView, TopView, StatsView, ComposeOpsView, ComposeOutputView, DriftView:
+			m.popView()
+			return m, nil
+		case ExecOutputView:
+			m.stopExecCommand()
 			m.popView()
 			return m, nil
 		case LogsView:
@@ -55,9 +71,10 @@ func (m Model) handleKeyMsg(msg tea.KeyPressMsg) (Model, tea.Cmd) {
 			m.popView()
 			m.logReady = false
 			m.currentLogGroupName = ""
+			m.currentLogSources = nil
 			return m, nil
 		}
-		return m, tea.Quit
+		return m.quit()
 	case key.Matches(msg, Keys.Global.Tab1):
 		m.leaveLogView()
 		m.currentView = ContainersView
@@ -104,26 +121,59 @@ func (m Model) handleKeyMsg(msg tea.KeyPressMsg) (Model, tea.Cmd) {
 		return m.handleLogsKey(msg)
 	case DetailsView:
 		return m.handleDetailsKey(msg)
+	case ChangesView:
+		return m.handleChangesKey(msg)
+	case ExecOutputView:
+		return m.handleExecOutputKey(msg)
+	case TopView:
+		return m.handleTopKey(msg)
+	case StatsView:
+		var cmd tea.Cmd
+		m.statsViewPort, cmd = m.statsViewPort.Update(msg)
+		return m, cmd
+	case ComposeOpsView:
+		return m.handleComposeOpsKey(msg)
+	case ComposeOutputView:
+		return m.handleComposeOutputKey(msg)
+	case DriftView:
+		return m.handleDriftKey(msg)
 	}
 
 	return m, nil
 }
 
+// quit closes the stats streams and exits the program.
+func (m Model) quit() (Model, tea.Cmd) {
+	m.statsMonitor.Stop()
+	return m, tea.Quit
+}
+
 func (m Model) handleFilterKey(msg tea.KeyPressMsg) (Model, tea.Cmd) {
 	switch {
 	case key.Matches(msg, Keys.Filter.Cancel), key.Matches(msg, Keys.Filter.Submit):
 		m.filterActive = false
-		m.filterInput.Blur()
+		m.activeFilter().Blur()
 		m.rebuildFilteredTables()
 		return m, nil
 	default:
 		var cmd tea.Cmd
-		m.filterInput, cmd = m.filterInput.Update(msg)
+		input := m.activeFilter()
+		*input, cmd = input.Update(msg)
 		m.rebuildFilteredTables()
 		return m, cmd
 	}
 }
 
+// activeFilter returns the filter input of the current view. The process
+// list keeps its own, so opening it neither clears the containers filter
+// nor leaves its text behind there.
+func (m *Model) activeFilter() *textinput.Model {
+	if m.currentView == TopView {
+		return &m.topFilter
+	}
+	return &m.filterInput
+}
+
 func (m *Model) rebuildFilteredTables() {
 	switch m.currentView {
 	case ContainersView:
@@ -132,6 +182,8 @@ func (m *Model) rebuildFilteredTables() {
 		m.imageTable.SetRows(m.buildImageRows())
 	case VolumesView:
 		m.volumeTable.SetRows(m.buildVolumeRows())
+	case TopView:
+		m.refreshTopTable()
 	}
 }
 
@@ -176,7 +228,7 @@ func (m Model) handleContainersKey(msg tea.KeyPressMsg) (Model, tea.Cmd) {
 
 	// Track last action key for command preview; movement keys reset to default.
 	switch msg.String() {
-	case "s", "x", "r", "d", "l", "i", "e", "u", "U", "R", "p", "b":
+	case "s", "x", "r", "d", "l", "i", "e", "!", "t", "c", "u", "U", "R", "p", "b":
 		m.lastActionKey = msg.String()
 	default:
 		m.lastActionKey = ""
@@ -188,6 +240,45 @@ func (m Model) handleContainersKey(msg tea.KeyPressMsg) (Model, tea.Cmd) {
 		return m, tea.Batch(cmds...)
 	}
 
+	if key.Matches(msg, Keys.Container.Unhealthy) {
+		m.unhealthyOnly = !m.unhealthyOnly
+		m.recomputeRows()
+		return m, tea.Batch(cmds...)
+	}
+
+	if key.Matches(msg, Keys.Container.MultiLogs) {
+		return m.openMultiLogs()
+	}
+
+	if key.Matches(msg, Keys.Container.Group) {
+		m.quickMenu = NewGroupingQuickMenu(m.grouping)
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(msg, Keys.Table.Sort):
+		m.cycleSort()
+		return m, nil
+	case key.Matches(msg, Keys.Table.Reverse):
+		m.reverseSort()
+		return m, nil
+	}
+
+	if key.Matches(msg, Keys.Compose.History) {
+		project := ""
+		if m.containerCursor >= 0 && m.containerCursor < len(m.rows) {
+			project = m.composeProjectOf(m.rows[m.containerCursor])
+		}
+		return m.openComposeOps(project)
+	}
+
+	if key.Matches(msg, Keys.Container.Stats) {
+		m.pushView(StatsView)
+		m.statsViewPort.SetContent(m.renderStatsContent())
+		m.statsViewPort.GotoTop()
+		return m, tea.Batch(cmds...)
+	}
+
 	if len(m.rows) == 0 {
 		return m, tea.Batch(cmds...)
 	}
@@ -198,21 +289,26 @@ func (m Model) handleContainersKey(msg tea.KeyPressMsg) (Model, tea.Cmd) {
 	}
 	row := m.rows[idx]
 
+	if key.Matches(msg, Keys.Container.Mark) {
+		m.toggleMark(row)
+		return m, tea.Batch(cmds...)
+	}
+
 	switch row.Type {
 	case RowTypeGroup:
 		switch {
 		case key.Matches(msg, Keys.Container.Details):
 			m.collapsedGroups[row.GroupID] = !m.collapsedGroups[row.GroupID]
 			m.recomputeRows()
-			saveState(persistedState{CollapsedGroups: m.collapsedGroups})
+			m.persistState()
 		case key.Matches(msg, Keys.Container.Expand):
 			delete(m.collapsedGroups, row.GroupID)
 			m.recomputeRows()
-			saveState(persistedState{CollapsedGroups: m.collapsedGroups})
+			m.persistState()
 		case key.Matches(msg, Keys.Container.Collapse):
 			m.collapsedGroups[row.GroupID] = true
 			m.recomputeRows()
-			saveState(persistedState{CollapsedGroups: m.collapsedGroups})
+			m.persistState()
 		case key.Matches(msg, Keys.Container.Start):
 			m.statusMessage = fmt.Sprintf("docker start [%s]", row.GroupID)
 			m.showSpinner = true
@@ -238,23 +334,19 @@ func (m Model) handleContainersKey(msg tea.KeyPressMsg) (Model, tea.Cmd) {
 			)
 			m.showSpinner = false
 		case key.Matches(msg, Keys.Container.Logs):
-			m.stopLogStream()
-			m.logLines = nil
-			m.logFollowing = true
-			m.currentLogGroupName = row.GroupID
-			m.currentLogContainerID = ""
-			group := findGroupContainers(m.containers, row.GroupID)
-			m.pushView(LogsView)
-			m.logReady = true
-			ch, cancel, waitCmd := startGroupLogStreamCmd(group)
-			m.logCh = ch
-			m.logCancel = cancel
-			cmds = append(cmds, waitCmd)
+			var cmd tea.Cmd
+			if m.grouping.Kind == GroupByCompose {
+				m, cmd = m.openGroupLogs(row.GroupID)
+			} else {
+				m, cmd = m.openSourceLogs(row.GroupID, row.Containers)
+			}
+			cmds = append(cmds, cmd)
 		case key.Matches(msg, Keys.Container.QuickActions):
 			m.statusMessage = "Group: use s/x/r/d to start/stop/restart/delete all containers"
 		default:
-			workDir := m.composeWorkDir(row.GroupID)
-			return m.dispatchComposeAction(msg, row.GroupID, workDir, cmds)
+			if m.grouping.Kind == GroupByCompose {
+				return m.dispatchComposeAction(msg, m.composeTarget(row.GroupID), cmds)
+			}
 		}
 
 	case RowTypeContainer:
@@ -267,7 +359,23 @@ func (m Model) handleContainersKey(msg tea.KeyPressMsg) (Model, tea.Cmd) {
 			m.quickMenu = NewContainerQuickMenu(row.Container.ID, row.Container.Names)
 			return m, nil
 		}
+		if key.Matches(msg, Keys.Compose.Service) {
+			return m.openServiceMenu(*row.Container)
+		}
 		return m.dispatchContainerAction(msg, row.Container.ID, row.Container.Names, row.Container.State, cmds)
+
+	case RowTypeGhost:
+		switch {
+		case key.Matches(msg, Keys.Container.Collapse):
+			m.collapsedGroups[row.GroupID] = true
+			m.recomputeRows()
+		case key.Matches(msg, Keys.Compose.Up):
+			svc := row.Service.Name
+			m.statusMessage = fmt.Sprintf("%s up -d %s  [%s]", controller.ComposeToolName(), svc, row.GroupID)
+			cmds = append(cmds, m.startComposeOp(m.composeTarget(row.GroupID), "up -d "+svc, composeServiceCmd(controller.ComposeUp, svc)), m.spinner.Tick)
+		default:
+			m.statusMessage = fmt.Sprintf("%s has no container: press u to create and start it", row.Service.Name)
+		}
 	}
 
 	return m, tea.Batch(cmds...)
@@ -280,6 +388,9 @@ func (m Model) dispatchContainerAction(msg tea.KeyPressMsg, id, name, state stri
 		m.pushView(DetailsView)
 		m.currentDetailsID = id
 		m.detailsReady = false
+		m.detailsSection = 0
+		m.detailsReveal = false
+		m.detailsViewPort.GotoTop()
 		m.statusMessage = fmt.Sprintf("Loading details %s...", name)
 		m.showSpinner = true
 		cmds = append(cmds, fetchDetailsCmd(id), m.spinner.Tick)
@@ -303,16 +414,9 @@ func (m Model) dispatchContainerAction(msg tea.KeyPressMsg, id, name, state stri
 		)
 		m.showSpinner = false
 	case key.Matches(msg, Keys.Container.Logs):
-		m.stopLogStream()
-		m.logLines = nil
-		m.logFollowing = true
-		m.currentLogContainerID = id
-		m.pushView(LogsView)
-		m.logReady = true
-		ch, cancel, waitCmd := startLogStreamCmd(id)
-		m.logCh = ch
-		m.logCancel = cancel
-		cmds = append(cmds, waitCmd)
+		var cmd tea.Cmd
+		m, cmd = m.openContainerLogs(id)
+		cmds = append(cmds, cmd)
 	case key.Matches(msg, Keys.Container.Inspect):
 		m.pushView(InspectView)
 		m.currentInspectID = id
@@ -325,74 +429,112 @@ func (m Model) dispatchContainerAction(msg tea.KeyPressMsg, id, name, state stri
 			m.statusMessage = "Container must be running to exec"
 			return m, tea.Batch(cmds...)
 		}
-		cmds = append(cmds, execShellCmd(id))
+		var cmd tea.Cmd
+		m, cmd = m.openExecForm(id, name)
+		cmds = append(cmds, cmd)
+	case key.Matches(msg, Keys.Container.Changes):
+		var cmd tea.Cmd
+		m, cmd = m.openChangesView(id, name)
+		cmds = append(cmds, cmd)
+	case key.Matches(msg, Keys.Container.Run):
+		var cmd tea.Cmd
+		m, cmd = m.openRunCommandForm(id, name)
+		cmds = append(cmds, cmd)
+	case key.Matches(msg, Keys.Container.Top):
+		var cmd tea.Cmd
+		m, cmd = m.openTopView(id, name)
+		cmds = append(cmds, cmd)
 	}
 	return m, tea.Batch(cmds...)
 }
 
-// composeWorkDir returns the working directory for a compose project by checking container labels.
-func (m Model) composeWorkDir(project string) string {
-	for _, c := range m.containers {
-		if c.Labels["com.docker.compose.project"] == project {
-			if wd := c.Labels["com.docker.compose.project.working_dir"]; wd != "" {
-				return wd
+// openChangesView pushes the filesystem changes view and fetches the diff.
+func (m Model) openChangesView(id, name string) (Model, tea.Cmd) {
+	m.pushView(ChangesView)
+	m.currentChangesID = id
+	m.changes = nil
+	m.changesTable.SetRows(nil)
+	m.statusMessage = fmt.Sprintf("docker diff %s", name)
+	m.showSpinner = true
+	return m, tea.Batch(fetchChangesCmd(id), m.spinner.Tick)
+}
+
+// newCopyOutForm prompts for a container path and a host destination.
+func newCopyOutForm(id, srcPath string) *Form {
+	return NewForm(
+		"Copy from container  "+id,
+		func(m Model, values []string) (Model, tea.Cmd) {
+			if values[0] == "" || values[1] == "" {
+				m.statusMessage = "Copy cancelled: both paths are required."
+				return m, nil
 			}
-		}
-	}
-	return ""
+			m.statusMessage = fmt.Sprintf("docker cp %s:%s %s", id, values[0], values[1])
+			m.showSpinner = true
+			return m, tea.Batch(copyFromContainerCmd(id, values[0], values[1]), m.spinner.Tick)
+		},
+		NewFormField("Container path", srcPath, "/etc/app.conf"),
+		NewFormField("Host destination", ".", "./out"),
+	)
+}
+
+// newCopyInForm prompts for a host path and a container destination.
+func newCopyInForm(id, destPath string) *Form {
+	return NewForm(
+		"Copy into container  "+id,
+		func(m Model, values []string) (Model, tea.Cmd) {
+			if values[0] == "" || values[1] == "" {
+				m.statusMessage = "Copy cancelled: both paths are required."
+				return m, nil
+			}
+			m.statusMessage = fmt.Sprintf("docker cp %s %s:%s", values[0], id, values[1])
+			m.showSpinner = true
+			return m, tea.Batch(copyToContainerCmd(id, values[0], values[1]), m.spinner.Tick)
+		},
+		NewFormField("Host path", "", "./patched.conf"),
+		NewFormField("Container destination", destPath, "/etc/"),
+	)
 }
 
-// startComposeOp cancels any running compose op, creates a fresh context, and returns the cmd.
-func (m *Model) startComposeOp(project, workDir string, fn func(context.Context, string, string) tea.Cmd) tea.Cmd {
-	if m.composeCancel != nil {
-		m.composeCancel()
+// composeTarget returns where compose commands for project run: the project
+// read from disk when known, or else the working directory and files recorded
+// in its containers' labels, with the profiles and env files chosen for it.
+func (m Model) composeTarget(project string) controller.ComposeTarget {
+	opts := m.composeOptions[project]
+	if p, ok := m.composeProjects[project]; ok {
+		return opts.apply(p.ComposeTarget)
 	}
-	ctx, cancel := context.WithCancel(context.Background())
-	m.composeCancel = cancel
-	m.showSpinner = true
-	m.composeOutput = nil
-	return fn(ctx, project, workDir)
+	for _, t := range controller.ComposeTargetsFromLabels(m.containers) {
+		if t.Project == project {
+			return opts.apply(t)
+		}
+	}
+	return opts.apply(controller.ComposeTarget{Project: project})
 }
 
 // dispatchComposeAction handles compose project-level action keys when a group row is selected.
-func (m Model) dispatchComposeAction(msg tea.KeyPressMsg, project, workDir string, cmds []tea.Cmd) (Model, tea.Cmd) {
+func (m Model) dispatchComposeAction(msg tea.KeyPressMsg, t controller.ComposeTarget, cmds []tea.Cmd) (Model, tea.Cmd) {
+	project := t.Project
 	switch {
 	case key.Matches(msg, Keys.Compose.Up):
-		m.statusMessage = fmt.Sprintf("docker compose up -d  [%s]", project)
-		cmds = append(cmds, m.startComposeOp(project, workDir, composeUpCmd), m.spinner.Tick)
+		m.statusMessage = fmt.Sprintf("%s up -d  [%s]", controller.ComposeToolName(), project)
+		cmds = append(cmds, m.startComposeOp(t, "up -d", composeUpCmd), m.spinner.Tick)
+	case key.Matches(msg, Keys.Compose.Options):
+		m.form = newComposeOptionsForm(project, m.composeOptions[project], m.composeProfiles(project))
+	case key.Matches(msg, Keys.Compose.Drift):
+		return m.openDrift(t)
 	case key.Matches(msg, Keys.Compose.UpBuild):
-		m.statusMessage = fmt.Sprintf("docker compose up -d --build  [%s]", project)
-		cmds = append(cmds, m.startComposeOp(project, workDir, composeUpBuildCmd), m.spinner.Tick)
+		m.statusMessage = fmt.Sprintf("%s up -d --build  [%s]", controller.ComposeToolName(), project)
+		cmds = append(cmds, m.startComposeOp(t, "up -d --build", composeUpBuildCmd), m.spinner.Tick)
 	case key.Matches(msg, Keys.Compose.Recreate):
-		ctx, cancel := context.WithCancel(context.Background())
-		if m.composeCancel != nil {
-			m.composeCancel()
-		}
-		m.composeCancel = cancel
-		m.composeOutput = nil
-		m.modal = NewConfirmModal(
-			"Force Recreate",
-			fmt.Sprintf("docker compose up -d --force-recreate\nProject: %s", project),
-			tea.Batch(composeRecreateCmd(ctx, project, workDir), m.spinner.Tick),
-		)
+		m.confirmComposeOp("Force Recreate", t, "up -d --force-recreate", composeRecreateCmd)
 	case key.Matches(msg, Keys.Compose.Down):
-		ctx, cancel := context.WithCancel(context.Background())
-		if m.composeCancel != nil {
-			m.composeCancel()
-		}
-		m.composeCancel = cancel
-		m.composeOutput = nil
-		m.modal = NewConfirmModal(
-			"Compose Down",
-			fmt.Sprintf("docker compose down\nProject: %s", project),
-			tea.Batch(composeDownCmd(ctx, project, workDir), m.spinner.Tick),
-		)
+		m.confirmComposeOp("Compose Down", t, "down", composeDownCmd)
 	case key.Matches(msg, Keys.Compose.Pull):
-		m.statusMessage = fmt.Sprintf("docker compose pull  [%s]", project)
-		cmds = append(cmds, m.startComposeOp(project, workDir, composePullCmd), m.spinner.Tick)
+		m.statusMessage = fmt.Sprintf("%s pull  [%s]", controller.ComposeToolName(), project)
+		cmds = append(cmds, m.startComposeOp(t, "pull", composePullCmd), m.spinner.Tick)
 	case key.Matches(msg, Keys.Compose.Build):
-		m.statusMessage = fmt.Sprintf("docker compose build  [%s]", project)
-		cmds = append(cmds, m.startComposeOp(project, workDir, composeBuildCmd), m.spinner.Tick)
+		m.statusMessage = fmt.Sprintf("%s build  [%s]", controller.ComposeToolName(), project)
+		cmds = append(cmds, m.startComposeOp(t, "build", composeBuildCmd), m.spinner.Tick)
 	}
 	return m, tea.Batch(cmds...)
 }
@@ -404,6 +546,10 @@ func (m Model) handleImagesKey(msg tea.KeyPressMsg) (Model, tea.Cmd) {
 	cmds = append(cmds, cmd)
 
 	switch {
+	case key.Matches(msg, Keys.Table.Sort):
+		m.cycleSort()
+	case key.Matches(msg, Keys.Table.Reverse):
+		m.reverseSort()
 	case key.Matches(msg, Keys.Image.Filter):
 		m.filterActive = true
 		m.filterInput.Focus()
@@ -434,6 +580,10 @@ func (m Model) handleVolumesKey(msg tea.KeyPressMsg) (Model, tea.Cmd) {
 	cmds = append(cmds, cmd)
 
 	switch {
+	case key.Matches(msg, Keys.Table.Sort):
+		m.cycleSort()
+	case key.Matches(msg, Keys.Table.Reverse):
+		m.reverseSort()
 	case key.Matches(msg, Keys.Volume.Filter):
 		m.filterActive = true
 		m.filterInput.Focus()
@@ -457,7 +607,12 @@ func (m Model) handleNetworksKey(msg tea.KeyPressMsg) (Model, tea.Cmd) {
 	m.networkTable, cmd = m.networkTable.Update(msg)
 	cmds = append(cmds, cmd)
 
-	if key.Matches(msg, Keys.Network.Inspect) && len(m.networkTable.SelectedRow()) > 0 {
+	switch {
+	case key.Matches(msg, Keys.Table.Sort):
+		m.cycleSort()
+	case key.Matches(msg, Keys.Table.Reverse):
+		m.reverseSort()
+	case key.Matches(msg, Keys.Network.Inspect) && len(m.networkTable.SelectedRow()) > 0:
 		id := m.networkTable.SelectedRow()[0]
 		m.pushView(InspectView)
 		m.currentInspectID = id
@@ -519,21 +674,157 @@ func (m Model) handleLogsKey(msg tea.KeyPressMsg) (Model, tea.Cmd) {
 		return m, nil
 	case key.Matches(msg, Keys.Logs.Follow):
 		m.logFollowing = true
-		m.logViewPort.GotoBottom()
+		m.renderLogWindow()
 		return m, nil
 	case key.Matches(msg, Keys.Logs.LineNumbers):
 		m.showLineNumbers = !m.showLineNumbers
-		m.logViewPort.SetContent(buildColorizedLogContent(m.logLines, m.showLineNumbers))
+		m.refreshLogView()
+		return m, nil
+	case key.Matches(msg, Keys.Logs.Search):
+		m.openLogInput(logInputSearch)
+		return m, nil
+	case key.Matches(msg, Keys.Logs.Filter):
+		m.openLogInput(logInputFilter)
+		return m, nil
+	case key.Matches(msg, Keys.Logs.NextMatch):
+		m.jumpToLogMatch(+1)
+		return m, nil
+	case key.Matches(msg, Keys.Logs.PrevMatch):
+		m.jumpToLogMatch(-1)
+		return m, nil
+	case key.Matches(msg, Keys.Logs.Range):
+		m.form = newLogRangeForm(m.logOptions)
+		return m, nil
+	case key.Matches(msg, Keys.Logs.Timestamps):
+		return m.toggleLogTimestamps()
+	case key.Matches(msg, Keys.Logs.Level):
+		m.logMinLevel = m.logMinLevel.nextMinLevel()
+		m.logMatchIdx = -1
+		m.refreshLogView()
+		return m, nil
+	case key.Matches(msg, Keys.Logs.FieldFilter):
+		m.openLogInput(logInputField)
+		return m, nil
+	case key.Matches(msg, Keys.Logs.Columns):
+		m.openLogInput(logInputColumns)
+		return m, nil
+	case key.Matches(msg, Keys.Logs.Mute):
+		if len(m.logSourceNames()) < 2 {
+			m.statusMessage = "Muting needs logs from several containers."
+			return m, nil
+		}
+		m.openLogInput(logInputMute)
+		return m, nil
+	case key.Matches(msg, Keys.Logs.Streams):
+		m.logStreams = m.logStreams.next()
+		m.logMatchIdx = -1
+		m.refreshLogView()
+		return m, nil
+	case key.Matches(msg, Keys.Logs.Save):
+		m.form = newSaveLogsForm(m.logExportDir)
+		return m, nil
+	case key.Matches(msg, Keys.Logs.Reload):
+		m.statusMessage = fmt.Sprintf("Reloading logs (%s)", m.logOptions.Summary())
+		return m.restartLogStream()
+	}
+	if m.handleLogScrollKey(msg) {
 		return m, nil
 	}
+	// Horizontal scrolling is left to the viewport.
 	var cmd tea.Cmd
 	m.logViewPort, cmd = m.logViewPort.Update(msg)
 	return m, cmd
 }
 
-func (m Model) handleDetailsKey(msg tea.KeyPressMsg) (Model, tea.Cmd) {
+func (m Model) handleChangesKey(msg tea.KeyPressMsg) (Model, tea.Cmd) {
+	selected := ""
+	if row := m.changesTable.SelectedRow(); len(row) > 1 {
+		selected = row[1]
+	}
+
+	switch {
+	case key.Matches(msg, Keys.Changes.CopyOut):
+		m.form = newCopyOutForm(m.currentChangesID, selected)
+		return m, nil
+	case key.Matches(msg, Keys.Changes.CopyIn):
+		dest := "/"
+		if selected != "" {
+			dest = path.Dir(selected)
+		}
+		m.form = newCopyInForm(m.currentChangesID, dest)
+		return m, nil
+	case key.Matches(msg, Keys.Changes.Refresh):
+		m.showSpinner = true
+		return m, tea.Batch(fetchChangesCmd(m.currentChangesID), m.spinner.Tick)
+	case key.Matches(msg, Keys.Table.Sort):
+		m.cycleSort()
+		return m, nil
+	case key.Matches(msg, Keys.Table.Reverse):
+		m.reverseSort()
+		return m, nil
+	}
+
 	var cmd tea.Cmd
-	m.detailsViewPort, cmd = m.detailsViewPort.Update(msg)
+	m.changesTable, cmd = m.changesTable.Update(msg)
+	return m, cmd
+}
+
+func (m Model) handleExecOutputKey(msg tea.KeyPressMsg) (Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, Keys.Exec.Rerun):
+		return m.runCommand(m.execOutputID, m.execOutputName, m.execOutputCmd)
+	case key.Matches(msg, Keys.Exec.New):
+		m.form = newRunCommandForm(m.execOutputID, m.execOutputName, m.execHistory[m.execOutputName])
+		return m, nil
+	case key.Matches(msg, Keys.Exec.History):
+		if history := m.execHistory[m.execOutputName]; len(history) > 0 {
+			m.quickMenu = newExecHistoryMenu(m.execOutputID, m.execOutputName, history)
+		}
+		return m, nil
+	case key.Matches(msg, Keys.Exec.Stop):
+		if m.execRunning {
+			m.stopExecCommand()
+			m.statusMessage = m.execOutputCmd + " stopped."
+		}
+		return m, nil
+	case key.Matches(msg, Keys.Exec.LineNumbers):
+		m.showLineNumbers = !m.showLineNumbers
+		m.refreshExecOutput()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.execOutputVP, cmd = m.execOutputVP.Update(msg)
+	return m, cmd
+}
+
+func (m Model) handleTopKey(msg tea.KeyPressMsg) (Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, Keys.Top.Filter):
+		m.filterActive = true
+		m.topFilter.Focus()
+		return m, nil
+	case key.Matches(msg, Keys.Table.Sort):
+		m.cycleSort()
+		return m, nil
+	case key.Matches(msg, Keys.Table.Reverse):
+		m.reverseSort()
+		return m, nil
+	case key.Matches(msg, Keys.Top.Signal):
+		row := m.topTable.SelectedRow()
+		if len(row) != len(topCols) {
+			return m, nil
+		}
+		if !canSignalProcess(row[0]) {
+			m.statusMessage = "Signals need the engine host's /proc to find the process in the container"
+			return m, nil
+		}
+		m.quickMenu = newSignalMenu(m.currentTopID, row[0], row[4])
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.topTable, cmd = m.topTable.Update(msg)
 	return m, cmd
 }
 
@@ -547,14 +838,20 @@ func (m Model) cycleTab(delta int) Model {
 	return m
 }
 
-// leaveLogView stops the log stream and resets log state when navigating away via tab switch.
+// leaveLogView stops the log stream (or a running one-off command) and resets
+// its state when navigating away via tab switch.
 func (m *Model) leaveLogView() {
+	if m.currentView == ExecOutputView {
+		m.stopExecCommand()
+		return
+	}
 	if m.currentView != LogsView {
 		return
 	}
 	m.stopLogStream()
 	m.logReady = false
 	m.currentLogGroupName = ""
+	m.currentLogSources = nil
 }
 
 // stopLogStream cancels and cleans up the log stream goroutine.
@@ -9,8 +9,8 @@ import (
 
 // handleMouseMsg dispatches mouse events to the appropriate handler.
 func (m Model) handleMouseMsg(msg tea.MouseMsg) (Model, tea.Cmd) {
-	// Ignore mouse while modal or filter input is active.
-	if m.modal != nil || m.filterActive {
+	// Ignore mouse while modal, form or filter input is active.
+	if m.modal != nil || m.form != nil || m.filterActive {
 		return m, nil
 	}
 
@@ -43,13 +43,34 @@ func (m Model) handleScrollUp() (Model, tea.Cmd) {
 		var cmd tea.Cmd
 		m.networkTable, cmd = m.networkTable.Update(tea.KeyPressMsg{Code: tea.KeyUp})
 		return m, cmd
+	case ChangesView:
+		var cmd tea.Cmd
+		m.changesTable, cmd = m.changesTable.Update(tea.KeyPressMsg{Code: tea.KeyUp})
+		return m, cmd
+	case TopView:
+		var cmd tea.Cmd
+		m.topTable, cmd = m.topTable.Update(tea.KeyPressMsg{Code: tea.KeyUp})
+		return m, cmd
+	case ComposeOpsView:
+		var cmd tea.Cmd
+		m.composeOpsTable, cmd = m.composeOpsTable.Update(tea.KeyPressMsg{Code: tea.KeyUp})
+		return m, cmd
+	case DriftView:
+		var cmd tea.Cmd
+		m.driftTable, cmd = m.driftTable.Update(tea.KeyPressMsg{Code: tea.KeyUp})
+		return m, cmd
 	case InspectView:
 		m.inspectViewPort.ScrollUp(3)
 	case LogsView:
-		m.logFollowing = false
-		m.logViewPort.ScrollUp(3)
+		m.scrollLogs(-3)
 	case DetailsView:
 		m.detailsViewPort.ScrollUp(3)
+	case ExecOutputView:
+		m.execOutputVP.ScrollUp(3)
+	case ComposeOutputView:
+		m.composeOutputVP.ScrollUp(3)
+	case StatsView:
+		m.statsViewPort.ScrollUp(3)
 	}
 	return m, nil
 }
@@ -71,36 +92,70 @@ func (m Model) handleScrollDown() (Model, tea.Cmd) {
 		var cmd tea.Cmd
 		m.networkTable, cmd = m.networkTable.Update(tea.KeyPressMsg{Code: tea.KeyDown})
 		return m, cmd
+	case ChangesView:
+		var cmd tea.Cmd
+		m.changesTable, cmd = m.changesTable.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+		return m, cmd
+	case TopView:
+		var cmd tea.Cmd
+		m.topTable, cmd = m.topTable.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+		return m, cmd
+	case ComposeOpsView:
+		var cmd tea.Cmd
+		m.composeOpsTable, cmd = m.composeOpsTable.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+		return m, cmd
+	case DriftView:
+		var cmd tea.Cmd
+		m.driftTable, cmd = m.driftTable.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+		return m, cmd
 	case InspectView:
 		m.inspectViewPort.ScrollDown(3)
 	case LogsView:
-		m.logViewPort.ScrollDown(3)
+		m.scrollLogs(3)
 	case DetailsView:
 		m.detailsViewPort.ScrollDown(3)
+	case ExecOutputView:
+		m.execOutputVP.ScrollDown(3)
+	case ComposeOutputView:
+		m.composeOutputVP.ScrollDown(3)
+	case StatsView:
+		m.statsViewPort.ScrollDown(3)
 	}
 	return m, nil
 }
 
 func (m Model) handleLeftClick(msg tea.MouseMsg) (Model, tea.Cmd) {
 	mouse := msg.Mouse()
+	// Columns inside the app start after the left side of its frame.
+	x := mouse.X - contentLeft()
+	if x < 0 {
+		return m, nil
+	}
 	// Calculate header height to determine if click landed on tab bar.
 	headerH := lipgloss.Height(currentTheme.HeaderStyle.Render(m.headerText()))
 	tabBarH := 1 // tab bar is 1 line, rendered after header in Task 8
 
 	// Click on header area: check for tab bar clicks.
 	if mouse.Y >= headerH && mouse.Y < headerH+tabBarH {
-		return m.handleTabClick(mouse.X)
+		return m.handleTabClick(x)
 	}
 
 	// Click in content area: handle table row selection.
 	contentStartY := headerH + tabBarH
 	if mouse.Y >= contentStartY {
-		return m.handleTableClick(mouse.Y-contentStartY, mouse.X)
+		return m.handleTableClick(mouse.Y-contentStartY, x)
 	}
 
 	return m, nil
 }
 
+// contentLeft returns the screen column the header, tab bar and content
+// start at: the left side of the app frame.
+func contentLeft() int {
+	s := currentTheme.AppStyle
+	return s.GetMarginLeft() + s.GetBorderLeftSize() + s.GetPaddingLeft()
+}
+
 // handleTabClick maps an X coordinate to a tab and switches views.
 // Replicates renderTabBar() label+style logic to get accurate widths via lipgloss.Width().
 func (m Model) handleTabClick(x int) (Model, tea.Cmd) {
@@ -143,9 +198,14 @@ func (m Model) handleTabClick(x int) (Model, tea.Cmd) {
 }
 
 // handleTableClick maps a Y offset (relative to table start) to a row selection.
-func (m Model) handleTableClick(relY, _ int) (Model, tea.Cmd) {
-	// Row 0 = table header; data rows start at relY == 1.
-	if relY <= 0 {
+func (m Model) handleTableClick(relY, x int) (Model, tea.Cmd) {
+	// Row 0 = table header; clicking a column title sorts by it. Data rows
+	// start at relY == 1.
+	if relY < 0 {
+		return m, nil
+	}
+	if relY == 0 {
+		m.sortByHeader(x)
 		return m, nil
 	}
 	rowIndex := relY - 1 // 0-based data row index
@@ -182,6 +242,26 @@ func (m Model) handleTableClick(relY, _ int) (Model, tea.Cmd) {
 				m.networkTable, _ = m.networkTable.Update(tea.KeyPressMsg{Code: tea.KeyDown})
 			}
 		}
+	case ChangesView:
+		rows := m.changesTable.Rows()
+		if rowIndex < len(rows) {
+			m.changesTable.GotoTop()
+			for i := 0; i < rowIndex; i++ {
+				m.changesTable, _ = m.changesTable.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+			}
+		}
+	case TopView:
+		if rowIndex < len(m.topTable.Rows()) {
+			m.topTable.SetCursor(rowIndex)
+		}
+	case ComposeOpsView:
+		if rowIndex < len(m.composeOpsTable.Rows()) {
+			m.composeOpsTable.SetCursor(rowIndex)
+		}
+	case DriftView:
+		if rowIndex < len(m.driftTable.Rows()) {
+			m.driftTable.SetCursor(rowIndex)
+		}
 	}
 
 	return m, nil
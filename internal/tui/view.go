@@ -3,50 +3,173 @@ package tui
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/rluders/berth/internal/controller"
 )
 
+// logLineStyle picks the colour of a log line from its level keywords; ok is
+// false for lines without one.
+func logLineStyle(line string) (style lipgloss.Style, ok bool) {
+	return levelStyle(plainLogLevel(line))
+}
+
 // colorizeLogLine applies color coding based on log level keywords.
 func colorizeLogLine(line string) string {
-	th := currentTheme
-	lower := strings.ToLower(line)
+	if style, ok := logLineStyle(line); ok {
+		return style.Render(line)
+	}
+	return line
+}
 
-	// Detect timestamp prefix (common formats: "2024-01-01" or "[12:34:56]")
-	// and style it differently.
-	switch {
-	case strings.Contains(lower, "error") || strings.Contains(lower, "fatal") || strings.Contains(lower, "panic") || strings.Contains(lower, "critical"):
-		return th.LogErrorStyle.Render(line)
-	case strings.Contains(lower, "warn") || strings.Contains(lower, "warning"):
-		return th.LogWarnStyle.Render(line)
-	case strings.Contains(lower, "info") || strings.Contains(lower, "notice"):
-		return th.LogInfoStyle.Render(line)
-	case strings.Contains(lower, "debug") || strings.Contains(lower, "trace"):
-		return th.LogDebugStyle.Render(line)
-	default:
-		return line
+// highlightLogLine colours a log line like colorizeLogLine and renders every
+// search match with matchStyle.
+func highlightLogLine(line string, search *regexp.Regexp, matchStyle lipgloss.Style) string {
+	style, ok := logLineStyle(line)
+	plain := func(s string) string {
+		if ok && s != "" {
+			return style.Render(s)
+		}
+		return s
+	}
+	var sb strings.Builder
+	last := 0
+	for _, loc := range search.FindAllStringIndex(line, -1) {
+		if loc[0] == loc[1] {
+			continue
+		}
+		sb.WriteString(plain(line[last:loc[0]]))
+		sb.WriteString(matchStyle.Render(line[loc[0]:loc[1]]))
+		last = loc[1]
+	}
+	sb.WriteString(plain(line[last:]))
+	return sb.String()
+}
+
+// logRenderOptions controls which log lines are shown and how.
+type logRenderOptions struct {
+	showLineNumbers bool
+	filter          *logFilter     // lines not kept are hidden
+	search          *regexp.Regexp // matches are highlighted
+	currentMatch    int            // row of the selected match, or -1
+	streams         logStreamFilter
+	minLevel        logLevel        // lines below it, or without a level, are hidden
+	field           *logFieldFilter // JSON field match
+	columns         []string        // JSON fields shown as columns
+	muted           map[string]bool // container names whose lines are hidden
+}
+
+// filterLogRows returns the lines kept by the filters, numbered over
+// everything received, the rows (indices into the kept lines) containing a
+// search match and the widths of the field columns.
+func filterLogRows(buf *logBuffer, opts logRenderOptions) (rows, matches, widths []int) {
+	widths = logColumnWidths(buf, opts.columns)
+	rows, matches = appendLogRows(buf, opts, widths, buf.Dropped(), nil, nil)
+	return rows, matches, widths
+}
+
+// appendLogRows filters the lines of buf numbered from on, appending those
+// kept to rows and the rows containing a search match to matches.
+func appendLogRows(buf *logBuffer, opts logRenderOptions, widths []int, from int, rows, matches []int) ([]int, []int) {
+	needText := opts.filter != nil || opts.search != nil
+	for n := max(from, buf.Dropped()); n < buf.Total(); n++ {
+		l := buf.At(n - buf.Dropped())
+		if opts.muted[l.ContainerName] {
+			continue
+		}
+		if l.Marker {
+			// Restart markers stay visible whatever the filters.
+			rows = append(rows, n)
+			continue
+		}
+		if !opts.streams.shows(l.Stream) || !opts.field.keep(l) {
+			continue
+		}
+		if opts.minLevel != logLevelUnknown && l.level() < opts.minLevel {
+			continue
+		}
+		if needText {
+			line := logLineText(l, opts.columns, widths)
+			if !opts.filter.keep(line) {
+				continue
+			}
+			if opts.search != nil && opts.search.MatchString(line) {
+				matches = append(matches, len(rows))
+			}
+		}
+		rows = append(rows, n)
 	}
+	return rows, matches
 }
 
-// buildColorizedLogContent formats all log lines with colors and optional line numbers.
-func buildColorizedLogContent(lines []string, showLineNumbers bool) string {
+// renderLogRows renders rows[from:to] with colors, optional line numbers
+// (counted over everything received, so they survive the buffer wrapping)
+// and highlighted search matches. JSON lines are laid out as
+// `time level msg key=val`. Only the visible rows are rendered, however
+// large the buffer.
+func renderLogRows(buf *logBuffer, rows, widths []int, from, to int, opts logRenderOptions) string {
+	muted := lipgloss.NewStyle().Foreground(lipgloss.Color(colorMuted))
+	if buf.Len() == 0 {
+		return muted.Render("  Waiting for log output...")
+	}
+	if len(rows) == 0 {
+		return muted.Render("  No lines match the filter.")
+	}
+
+	th := currentTheme
+	var sb strings.Builder
+	for row := max(from, 0); row < min(to, len(rows)); row++ {
+		n := rows[row]
+		l := buf.At(n - buf.Dropped())
+		line := logLineText(l, opts.columns, widths)
+		if opts.showLineNumbers {
+			sb.WriteString(th.LogLineNumStyle.Render(fmt.Sprintf("%4d │ ", n+1)))
+		}
+		if l.Stream == controller.StreamStderr {
+			sb.WriteString(th.LogWarnStyle.Render("! "))
+		}
+		switch {
+		case l.Marker:
+			sb.WriteString(renderLogEntryPrefix(l.LogEntry) + th.LogMarkerStyle.Render(l.Line))
+		case opts.search != nil && opts.search.MatchString(line):
+			matchStyle := th.LogMatchStyle
+			if row == opts.currentMatch {
+				matchStyle = th.LogCurrentMatchStyle
+			}
+			sb.WriteString(highlightLogLine(line, opts.search, matchStyle))
+		case l.json != nil:
+			sb.WriteString(renderLogEntryPrefix(l.LogEntry) + l.json.render(opts.columns, widths))
+		default:
+			sb.WriteString(renderLogEntryPrefix(l.LogEntry) + colorizeLogLine(l.Line))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// buildExecOutputContent formats one-off command output with the log colours,
+// marking stderr lines so they can be told apart from stdout.
+func buildExecOutputContent(lines []controller.ExecLine, showLineNumbers bool) string {
 	if len(lines) == 0 {
 		return lipgloss.NewStyle().
 			Foreground(lipgloss.Color(colorMuted)).
-			Render("  Waiting for log output...")
+			Render("  Waiting for output...")
 	}
 
 	th := currentTheme
 	var sb strings.Builder
-	for i, line := range lines {
+	for i, l := range lines {
 		if showLineNumbers {
-			num := th.LogLineNumStyle.Render(fmt.Sprintf("%4d │ ", i+1))
-			sb.WriteString(num + colorizeLogLine(line) + "\n")
-		} else {
-			sb.WriteString(colorizeLogLine(line) + "\n")
+			sb.WriteString(th.LogLineNumStyle.Render(fmt.Sprintf("%4d │ ", i+1)))
 		}
+		if l.Stderr {
+			sb.WriteString(th.LogWarnStyle.Render("! "))
+		}
+		sb.WriteString(colorizeLogLine(l.Line) + "\n")
 	}
 	return sb.String()
 }
@@ -69,6 +192,8 @@ func (m Model) View() tea.View {
 			body := m.renderContent()
 			if m.quickMenu != nil {
 				body = m.renderQuickMenu(body)
+			} else if m.form != nil {
+				body = m.renderForm(body)
 			} else if m.modal != nil {
 				body = m.renderModal(body)
 			}
@@ -100,12 +225,28 @@ func (m Model) renderHeader() string {
 
 	viewName := ""
 	switch m.currentView {
+	case ContainersView:
+		viewName = m.containerIndicators()
 	case InspectView:
 		viewName = " › inspect " + m.currentInspectID
 	case LogsView:
-		viewName = " › logs " + m.currentLogContainerID
+		viewName = " › logs " + m.logTitle()
 	case DetailsView:
 		viewName = " › details " + m.currentDetailsID
+	case ChangesView:
+		viewName = " › changes " + m.currentChangesID
+	case ExecOutputView:
+		viewName = " › run " + m.execOutputName
+	case TopView:
+		viewName = " › top " + m.currentTopName
+	case StatsView:
+		viewName = " › stats"
+	case ComposeOpsView:
+		viewName = " › compose history " + m.composeOpsTitle()
+	case ComposeOutputView:
+		viewName = " › compose output"
+	case DriftView:
+		viewName = " › drift " + m.driftProject
 	}
 
 	left := lipgloss.NewStyle().
@@ -186,35 +327,105 @@ func (m Model) renderContent() string {
 		return m.renderLogsView()
 	case DetailsView:
 		return m.detailsViewPort.View()
+	case ChangesView:
+		return m.changesTable.View()
+	case ExecOutputView:
+		return m.renderExecOutputView()
+	case TopView:
+		return m.topTable.View()
+	case StatsView:
+		return m.statsViewPort.View()
+	case ComposeOpsView:
+		return m.composeOpsTable.View()
+	case ComposeOutputView:
+		return m.renderComposeOutputView()
+	case DriftView:
+		return m.driftTable.View()
 	}
 	return ""
 }
 
+// renderExecOutputView renders a one-off command's output with its run state
+// or exit code.
+func (m Model) renderExecOutputView() string {
+	th := currentTheme
+
+	titleBar := lipgloss.NewStyle().
+		Padding(0, 1).
+		Bold(true).
+		Render("$ " + m.execOutputCmd)
+
+	var badge string
+	switch {
+	case m.execRunning:
+		badge = th.LogFollowStyle.Render("▶ RUNNING")
+	case m.execResult == nil:
+		badge = th.LogPausedStyle.Render("⏹ STOPPED")
+	case m.execResult.err != nil:
+		badge = th.LogErrorStyle.Render("✗ " + m.execResult.err.Error())
+	case m.execResult.code == 0:
+		badge = th.LogFollowStyle.Render("✓ exit 0")
+	default:
+		badge = th.LogErrorStyle.Render(fmt.Sprintf("✗ exit %d", m.execResult.code))
+	}
+
+	indicator := lipgloss.NewStyle().
+		Padding(0, 1).
+		Render(badge)
+
+	return lipgloss.JoinVertical(lipgloss.Left, titleBar, indicator, m.execOutputVP.View())
+}
+
 // renderLogsView renders the log viewport with a follow/pause indicator bar.
 func (m Model) renderLogsView() string {
 	th := currentTheme
 
-	title := m.currentLogContainerID
-	if m.currentLogGroupName != "" {
-		title = m.currentLogGroupName
-	}
 	titleBar := lipgloss.NewStyle().
 		Padding(0, 1).
 		Bold(true).
-		Render("Logs: " + title)
+		Render("Logs: " + m.logTitle())
+	if sources := m.renderLogSources(); sources != "" {
+		titleBar += "  " + sources
+	}
 
 	var badge string
-	if m.logFollowing {
+	switch {
+	case m.logCh == nil:
+		badge = th.LogPausedStyle.Render("■ ENDED")
+	case m.logFollowing:
 		badge = th.LogFollowStyle.Render("▶ LIVE")
-	} else {
+	default:
 		badge = th.LogPausedStyle.Render("⏸ PAUSED")
 	}
 
+	muted := lipgloss.NewStyle().Foreground(lipgloss.Color(colorMuted))
 	numBadge := ""
+	if summary := m.logOptions.Summary(); summary != "" {
+		numBadge += " " + muted.Render("["+summary+"]")
+	}
+	if m.logMinLevel != logLevelUnknown {
+		numBadge += " " + muted.Render("[level ≥ "+m.logMinLevel.String()+"]")
+	}
+	if m.logFieldFilter != nil {
+		numBadge += " " + muted.Render("[= "+m.logFieldFilter.text+"]")
+	}
+	if len(m.logColumns) > 0 {
+		numBadge += " " + muted.Render("[columns "+strings.Join(m.logColumns, ", ")+"]")
+	}
+	if m.logStreams != logStreamsBoth {
+		numBadge += " " + muted.Render("["+m.logStreams.String()+"]")
+	}
 	if m.showLineNumbers {
-		numBadge = " " + lipgloss.NewStyle().
-			Foreground(lipgloss.Color(colorMuted)).
-			Render("[line numbers on]")
+		numBadge += " " + muted.Render("[line numbers on]")
+	}
+	if m.logFilter != nil {
+		numBadge += " " + muted.Render("[& "+m.logFilter.text+"]")
+	}
+	if m.logSearch != "" {
+		numBadge += " " + muted.Render(fmt.Sprintf("[/ %s  %d matches]", m.logSearch, len(m.logMatches)))
+	}
+	if m.logInputMode != logInputNone {
+		numBadge = " " + th.FilterStyle.Render(m.logInput.View())
 	}
 
 	indicator := lipgloss.NewStyle().
@@ -298,24 +509,45 @@ func (m Model) BuildCommandPreview() string {
 	}
 	row := m.rows[idx]
 
+	if row.Type == RowTypeGroup && m.grouping.Kind != GroupByCompose {
+		names := make([]string, len(row.Containers))
+		for i, c := range row.Containers {
+			names[i] = c.Names
+		}
+		switch m.lastActionKey {
+		case "x":
+			return "docker stop " + strings.Join(names, " ")
+		case "r":
+			return "docker restart " + strings.Join(names, " ")
+		case "d":
+			return "docker rm " + strings.Join(names, " ")
+		default:
+			return "docker start " + strings.Join(names, " ")
+		}
+	}
+
 	if row.Type == RowTypeGroup {
-		project := row.GroupID
+		target := m.composeTarget(row.GroupID)
 		switch m.lastActionKey {
 		case "U":
-			return fmt.Sprintf("docker compose -p %s up -d --build", project)
+			return target.CommandLine("up", "-d", "--build")
 		case "R":
-			return fmt.Sprintf("docker compose -p %s up -d --force-recreate", project)
+			return target.CommandLine("up", "-d", "--force-recreate")
 		case "d":
-			return fmt.Sprintf("docker compose -p %s down", project)
+			return target.CommandLine("down")
 		case "p":
-			return fmt.Sprintf("docker compose -p %s pull", project)
+			return target.CommandLine("pull")
 		case "b":
-			return fmt.Sprintf("docker compose -p %s build", project)
+			return target.CommandLine("build")
 		default:
-			return fmt.Sprintf("docker compose -p %s up -d", project)
+			return target.CommandLine("up", "-d")
 		}
 	}
 
+	if row.Type == RowTypeGhost {
+		return m.composeTarget(row.GroupID).CommandLine("up", "-d", row.Service.Name)
+	}
+
 	name := row.Container.Names
 	switch m.lastActionKey {
 	case "s":
@@ -327,7 +559,20 @@ func (m Model) BuildCommandPreview() string {
 	case "d":
 		return fmt.Sprintf("docker rm %s", name)
 	case "e":
-		return fmt.Sprintf("docker exec -it %s sh", name)
+		prefs, ok := m.execPrefs[row.Container.Image]
+		if !ok {
+			prefs = defaultExecPrefs("")
+		}
+		return execPreview(name, prefs)
+	case "c":
+		return fmt.Sprintf("docker diff %s", name)
+	case "t":
+		return fmt.Sprintf("docker top %s aux", name)
+	case "!":
+		if history := m.execHistory[name]; len(history) > 0 {
+			return fmt.Sprintf("docker exec %s %s", name, history[0])
+		}
+		return fmt.Sprintf("docker exec %s <command>", name)
 	default:
 		return fmt.Sprintf("docker logs -f %s", name)
 	}
@@ -346,7 +591,7 @@ func (m Model) renderFooter() string {
 
 	// Filter bar
 	if m.filterActive {
-		filterBar := th.FilterStyle.Render("/ " + m.filterInput.View())
+		filterBar := th.FilterStyle.Render("/ " + m.activeFilter().View())
 		parts = append(parts, filterBar)
 	}
 
@@ -356,6 +601,16 @@ func (m Model) renderFooter() string {
 		parts = append(parts, "  "+m.progressBar.ViewAs(m.progressBar.Percent()))
 	}
 
+	// Resource alerts
+	if summary := m.alertSummary(); summary != "" {
+		parts = append(parts, th.StatusErrStyle.Render(ansi.Truncate(summary, max(m.width-4, 0), "…")))
+	}
+
+	// Configuration error
+	if m.configErr != "" {
+		parts = append(parts, th.StatusErrStyle.Render(ansi.Truncate(m.configErr, max(m.width-4, 0), "…")))
+	}
+
 	// Status / spinner
 	if m.statusMessage != "" && !m.progressVisible {
 		spinnerStr := ""
@@ -382,34 +637,70 @@ func (m Model) renderKeyHints() string {
 	switch m.currentView {
 	case ContainersView:
 		idx := m.containerCursor
+		if idx >= 0 && idx < len(m.rows) && m.rows[idx].Type == RowTypeGroup && m.grouping.Kind != GroupByCompose {
+			viewHints = []hint{
+				{"↑/↓", "move"}, {"→/←", "expand/collapse"}, {"l", "logs"},
+				{"s", "start all"}, {"x", "stop all"}, {"r", "restart all"}, {"d", "delete all"},
+				{"g", "group by"}, {"o/O", "sort"}, {"/", "filter"},
+			}
+			break
+		}
 		if idx >= 0 && idx < len(m.rows) && m.rows[idx].Type == RowTypeGroup {
 			viewHints = []hint{
 				{"↑/↓", "move"}, {"→/←", "expand/collapse"},
-				{"u", "up"}, {"U", "up+build"}, {"R", "recreate"},
+				{"u", "up"}, {"U", "up+build"}, {"E", "up with options"}, {"R", "recreate"},
 				{"d", "down"}, {"p", "pull"}, {"b", "build"},
-				{"/", "filter"},
+				{"h", "history"}, {"D", "drift"}, {"g", "group by"}, {"o/O", "sort"}, {"/", "filter"},
 			}
 			break
 		}
+		if idx >= 0 && idx < len(m.rows) && m.rows[idx].Type == RowTypeGhost {
+			viewHints = []hint{{"↑/↓", "move"}, {"←", "collapse"}, {"u", "up"}, {"/", "filter"}}
+			break
+		}
 		viewHints = []hint{
 			{"space", "actions"}, {"↑/↓", "move"}, {"enter", "details"}, {"l", "logs"},
 			{"i", "inspect"}, {"s", "start"}, {"x", "stop"},
-			{"r", "restart"}, {"d", "delete"}, {"e", "exec"}, {"/", "filter"},
+			{"r", "restart"}, {"d", "delete"}, {"e", "exec"}, {"!", "run"}, {"t", "top"}, {"S", "stats"}, {"c", "changes"}, {"/", "filter"}, {"H", "unhealthy"}, {"m", "mark"}, {"L", "multi-logs"}, {"g", "group by"}, {"o/O", "sort"},
+		}
+		if idx >= 0 && idx < len(m.rows) && m.composeProjectOf(m.rows[idx]) != "" {
+			viewHints = append(viewHints, hint{"a", "service"}, hint{"h", "compose history"})
 		}
 	case ImagesView:
-		viewHints = []hint{{"d", "remove"}, {"P", "prune"}, {"/", "filter"}}
+		viewHints = []hint{{"d", "remove"}, {"P", "prune"}, {"/", "filter"}, {"o/O", "sort"}}
 	case VolumesView:
-		viewHints = []hint{{"d", "remove"}, {"/", "filter"}}
+		viewHints = []hint{{"d", "remove"}, {"/", "filter"}, {"o/O", "sort"}}
 	case NetworksView:
-		viewHints = []hint{{"i", "inspect"}}
+		viewHints = []hint{{"i", "inspect"}, {"o/O", "sort"}}
 	case SystemView:
 		viewHints = []hint{{"b", "basic"}, {"a", "advanced"}, {"t", "total"}}
 	case LogsView:
-		viewHints = []hint{{"p", "pause"}, {"f", "follow"}, {"n", "line#"}, {"esc", "back"}}
+		viewHints = []hint{{"p", "pause"}, {"f", "follow"}, {"/", "search"}, {"n/N", "next/prev"}, {"&", "filter"}, {"l", "level"}, {"=", "field"}, {"c", "columns"}, {"m", "mute"}, {"s", "streams"}, {"o", "range"}, {"t", "time"}, {"r", "reload"}, {"w", "save"}, {"#", "line#"}, {"esc", "back"}}
 		global = nil
-	case InspectView, DetailsView:
+	case InspectView, StatsView:
 		viewHints = []hint{{"↑/↓", "scroll"}, {"esc", "back"}}
 		global = nil
+	case DetailsView:
+		viewHints = []hint{{"↑/↓", "scroll"}, {"[/]", "section"}, {"enter", "collapse"}, {"v", "reveal"}, {"esc", "back"}}
+		global = nil
+	case ChangesView:
+		viewHints = []hint{{"↑/↓", "move"}, {"e", "copy out"}, {"i", "copy in"}, {"r", "refresh"}, {"o/O", "sort"}, {"esc", "back"}}
+		global = nil
+	case TopView:
+		viewHints = []hint{{"↑/↓", "move"}, {"o", "sort"}, {"O", "reverse"}, {"K", "signal"}, {"/", "filter"}, {"esc", "back"}}
+		global = nil
+	case ExecOutputView:
+		viewHints = []hint{{"↑/↓", "scroll"}, {"r", "re-run"}, {"!", "new"}, {"h", "history"}, {"x", "stop"}, {"n", "line#"}, {"esc", "back"}}
+		global = nil
+	case ComposeOpsView:
+		viewHints = []hint{{"↑/↓", "move"}, {"enter", "output"}, {"r", "re-run"}, {"a", "all projects"}, {"o/O", "sort"}, {"esc", "back"}}
+		global = nil
+	case ComposeOutputView:
+		viewHints = []hint{{"↑/↓", "scroll"}, {"r", "re-run"}, {"esc", "back"}}
+		global = nil
+	case DriftView:
+		viewHints = []hint{{"↑/↓", "move"}, {"u", "up"}, {"r", "refresh"}, {"o/O", "sort"}, {"esc", "back"}}
+		global = nil
 	}
 
 	var segments []string